@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/spf13/cobra"
+)
+
+var breakingOnly bool
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-spec> <new-spec>",
+	Short: "Compare two specifications and report breaking changes",
+	Long: `Load two OpenAPI 2.0/3.x specifications (files or URLs), typically two
+merged outputs, and report the paths, operations and schemas that were added,
+removed or changed. The command exits with an error if any change is
+breaking: a removed path, operation or response, or a parameter that is new
+and required or became required.
+
+Example:
+  openapi-merge diff old/merged.yaml merged.yaml
+  openapi-merge diff old/merged.yaml merged.yaml --breaking-only`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVar(&breakingOnly, "breaking-only", false, "report only breaking changes")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	m := openapimerge.New(&config.Config{}, openapimerge.Options{Verbose: IsVerbose()})
+	base, err := m.LoadSpec(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", args[0], err)
+	}
+	revision, err := m.LoadSpec(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", args[1], err)
+	}
+
+	changes := openapimerge.DiffSpecs(base, revision)
+	breaking := openapimerge.BreakingChanges(changes)
+	if breakingOnly {
+		changes = breaking
+	}
+
+	printChanges(cmd.OutOrStdout(), changes)
+	if len(breaking) > 0 {
+		return fmt.Errorf("found %d breaking changes", len(breaking))
+	}
+	return nil
+}
+
+// printChanges writes the breaking changes first, then the others.
+func printChanges(out io.Writer, changes []openapimerge.SpecChange) {
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(out, "No changes found")
+		return
+	}
+
+	var breaking, other []openapimerge.SpecChange
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		} else {
+			other = append(other, c)
+		}
+	}
+
+	if len(breaking) > 0 {
+		_, _ = fmt.Fprintf(out, "Breaking changes (%d):\n", len(breaking))
+		for _, c := range breaking {
+			_, _ = fmt.Fprintf(out, "  %s\n", c)
+		}
+	}
+	if len(other) > 0 {
+		_, _ = fmt.Fprintf(out, "Other changes (%d):\n", len(other))
+		for _, c := range other {
+			_, _ = fmt.Fprintf(out, "  %s\n", c)
+		}
+	}
+}
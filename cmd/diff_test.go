@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintChanges(t *testing.T) {
+	var buf bytes.Buffer
+	printChanges(&buf, []openapimerge.SpecChange{
+		{Kind: openapimerge.ChangeAdded, Location: "path /invoices"},
+		{Kind: openapimerge.ChangeRemoved, Location: "path /legacy", Breaking: true},
+		{Kind: openapimerge.ChangeAdded, Location: "GET /users parameter tenant (header)", Detail: "required", Breaking: true},
+	})
+	assert.Equal(t, `Breaking changes (2):
+  removed path /legacy
+  added GET /users parameter tenant (header): required
+Other changes (1):
+  added path /invoices
+`, buf.String())
+
+	buf.Reset()
+	printChanges(&buf, nil)
+	assert.Equal(t, "No changes found\n", buf.String())
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/spf13/cobra"
+)
+
+// Supported export formats
+const (
+	exportFormatPostman  = "postman"
+	exportFormatMarkdown = "markdown"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert the merged specification into another format",
+	Long: `Run the merge described by the config and convert the result into another
+format instead of writing the OpenAPI output.
+
+Formats:
+  postman   Postman v2.1 collection with a folder per tag and a request per
+            operation, including example request bodies
+  markdown  Markdown summary with a section per tag listing its operations,
+            followed by a catalog of the schemas
+
+Example:
+  openapi-merge export --config merge-config.yaml --format postman -o collection.json
+  openapi-merge export --config merge-config.yaml --format markdown -o api.md`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if GetConfigFile() == "" {
+			return fmt.Errorf("required flag \"config\" not set")
+		}
+		return nil
+	},
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormatPostman, "export format: postman or markdown")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write the export to")
+	_ = exportCmd.MarkFlagRequired("output")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != exportFormatPostman && exportFormat != exportFormatMarkdown {
+		return fmt.Errorf("unknown format %q (expected %s or %s)", exportFormat, exportFormatPostman, exportFormatMarkdown)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	m := openapimerge.New(cfg, openapimerge.Options{Verbose: IsVerbose()})
+	data, err := exportData(m, exportFormat)
+	rep := &plainReporter{out: cmd.ErrOrStderr(), verbose: IsVerbose()}
+	for _, w := range m.Warnings() {
+		rep.Warning(w)
+	}
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if err := os.WriteFile(exportOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Exported %s to %s\n", exportFormat, exportOutput)
+	return nil
+}
+
+// exportData runs the merge and returns the result in the given format.
+func exportData(m *openapimerge.Merger, format string) ([]byte, error) {
+	if format == exportFormatMarkdown {
+		markdown, err := m.ExportMarkdown()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(markdown), nil
+	}
+
+	collection, err := m.ExportPostman()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal collection: %w", err)
+	}
+	return append(data, '\n'), nil
+}
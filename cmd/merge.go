@@ -3,15 +3,34 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/rperez95/openapi-merge/internal/config"
-	"github.com/rperez95/openapi-merge/internal/merger"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
-var outputFile string
+var (
+	outputFile   string
+	outputFormat string
+	outputURL    string
+	reporterName string
+	strictMode   bool
+	strictRefs   bool
+	onlyInputs   []string
+	checksum     bool
+	suggestMode  bool
+	updateLock   bool
+	dryRun       bool
+	reportFile   string
+	watchMode    bool
+
+	watchInterval time.Duration
+)
 
 // mergeCmd represents the merge command
 var mergeCmd = &cobra.Command{
@@ -28,7 +47,10 @@ The merge process:
 Example:
   openapi-merge merge --config merge-config.yaml
   openapi-merge merge --config merge-config.yaml -o unified-api.json
-  openapi-merge merge --config merge-config.yaml --output unified-api.yaml`,
+  openapi-merge merge --config merge-config.yaml --output unified-api.yaml
+  openapi-merge merge --config merge-config.yaml --dry-run
+  openapi-merge merge --config merge-config.yaml --watch
+  openapi-merge merge --config - --output - --format yaml < merge-config.yaml`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if GetConfigFile() == "" {
 			return fmt.Errorf("required flag \"config\" not set")
@@ -42,60 +64,275 @@ func init() {
 	rootCmd.AddCommand(mergeCmd)
 
 	// Add output flag
-	mergeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "output file path (overrides config file)")
+	mergeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "output file path, or - for standard output (overrides config file)")
+	_ = mergeCmd.MarkFlagFilename("output", "yaml", "yml", "json")
+	mergeCmd.Flags().StringVar(&outputFormat, "format", "", "output format when the output path has no .json, .yaml or .yml extension: json or yaml (overrides config file)")
+	mergeCmd.Flags().StringVar(&outputURL, "output-url", "", "also POST the merged spec to this URL (overrides config file)")
+	mergeCmd.Flags().StringVar(&reporterName, "reporter", reporterPlain, "format for warnings and errors: plain or github")
+	mergeCmd.Flags().StringArrayVar(&onlyInputs, "only", nil, "merge only the given inputs, by 1-based index, label or file name (repeatable)")
+	mergeCmd.Flags().BoolVar(&checksum, "checksum", false, "write a SHA-256 checksum file next to the output")
+	mergeCmd.Flags().BoolVar(&suggestMode, "suggest-prefixes", false, "report component conflicts and suggest dispute prefixes instead of writing output")
+	mergeCmd.Flags().StringVar(&reportFile, "report", "", "write a JSON report of renamed components, skipped operations and path rewrites (overrides config file)")
+	mergeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "run the merge and print a summary instead of writing output")
+	mergeCmd.Flags().BoolVar(&watchMode, "watch", false, "merge again whenever the config or a local input changes")
+	mergeCmd.Flags().DurationVar(&watchInterval, "watch-interval", defaultWatchInterval, "how often to poll URL inputs in --watch mode")
+	mergeCmd.Flags().BoolVar(&updateLock, "update-lock", false, "record the current content of remote inputs in the lock file instead of verifying it")
+	mergeCmd.Flags().BoolVar(&strictMode, "strict", false, "treat consistency warnings as errors (overrides config file)")
+	mergeCmd.Flags().BoolVar(&strictRefs, "strict-refs", false, "fail unless every $ref in the merged spec resolves (overrides config file)")
 }
 
 func runMerge(cmd *cobra.Command, args []string) error {
-	// Load configuration
+	cfg, err := loadMergeConfig()
+	if err != nil {
+		return err
+	}
+
+	// Keep standard output for the merged spec when writing it there
+	out := cmd.OutOrStdout()
+	if cfg.WritesToStdout() {
+		out = cmd.ErrOrStderr()
+	}
+
+	rep, err := newReporter(reporterName, out, cmd.ErrOrStderr(), IsVerbose())
+	if err != nil {
+		return err
+	}
+
+	// Create merger and execute
+	m := openapimerge.New(cfg, openapimerge.Options{Verbose: IsVerbose(), Command: commandLine()})
+
+	if suggestMode {
+		suggestions, err := m.SuggestPrefixes()
+		if err != nil {
+			rep.Error(err)
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		printPrefixSuggestions(out, suggestions, getConfigDir())
+		return nil
+	}
+
+	if dryRun {
+		summary, err := m.DryRun()
+		for _, w := range m.Warnings() {
+			rep.Warning(w)
+		}
+		if err != nil {
+			rep.Error(err)
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		printMergeSummary(cmd.OutOrStdout(), summary, getConfigDir())
+		return nil
+	}
+
+	if watchMode {
+		if cfg.WritesToStdout() {
+			return fmt.Errorf("--watch cannot write the merged spec to standard output")
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		watcher := &mergeWatcher{
+			out:         out,
+			interval:    watchInterval,
+			configFiles: GetConfigFiles(),
+			load:        loadMergeConfig,
+			merge: func(cfg *config.Config) error {
+				m := openapimerge.New(cfg, openapimerge.Options{Verbose: IsVerbose(), Command: commandLine()})
+				err := m.Merge()
+				for _, w := range m.Warnings() {
+					rep.Warning(w)
+				}
+				if err != nil {
+					rep.Error(err)
+				}
+				return err
+			},
+		}
+		return watcher.run(ctx)
+	}
+
+	if IsVerbose() {
+		_, _ = fmt.Fprintf(out, "Starting merge with %d input files\n", len(cfg.Inputs))
+		_, _ = fmt.Fprintf(out, "Output file: %s\n", cfg.Output)
+	}
+
+	err = m.Merge()
+	for _, w := range m.Warnings() {
+		rep.Warning(w)
+	}
+	if err != nil {
+		rep.Error(err)
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Successfully merged %d specifications into %s\n", len(cfg.Inputs), outputDestination(cfg))
+	return nil
+}
+
+// loadMergeConfig loads the configuration and applies the merge command's
+// flags on top of it, returning the validated result.
+func loadMergeConfig() (*config.Config, error) {
 	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Override output if flag is provided
 	if outputFile != "" {
 		// Make absolute path if relative
-		if !filepath.IsAbs(outputFile) {
+		if outputFile != config.StdoutOutput && !filepath.IsAbs(outputFile) {
 			cwd, _ := os.Getwd()
 			outputFile = filepath.Join(cwd, outputFile)
 		}
 		cfg.Output = outputFile
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+	if outputFormat != "" {
+		cfg.OutputFormat = outputFormat
 	}
 
-	// Create merger and execute
-	m := merger.New(cfg, IsVerbose())
+	if reportFile != "" {
+		if !filepath.IsAbs(reportFile) {
+			cwd, _ := os.Getwd()
+			reportFile = filepath.Join(cwd, reportFile)
+		}
+		cfg.ReportFile = reportFile
+	}
 
-	if IsVerbose() {
-		fmt.Printf("Starting merge with %d input files\n", len(cfg.Inputs))
-		fmt.Printf("Output file: %s\n", cfg.Output)
+	if outputURL != "" {
+		cfg.OutputURL = outputURL
 	}
 
-	if err := m.Merge(); err != nil {
-		return fmt.Errorf("merge failed: %w", err)
+	if strictMode {
+		cfg.Strict = true
+	}
+	if strictRefs {
+		cfg.StrictRefs = true
+	}
+	if checksum {
+		cfg.Checksum = true
+	}
+	if updateLock {
+		if cfg.LockFile == "" {
+			return nil, fmt.Errorf("--update-lock requires lockFile in the configuration")
+		}
+		cfg.UpdateLock = true
 	}
 
-	fmt.Printf("Successfully merged %d specifications into %s\n", len(cfg.Inputs), cfg.Output)
-	return nil
+	// Restrict to selected inputs
+	if err := cfg.SelectInputs(onlyInputs); err != nil {
+		return nil, fmt.Errorf("invalid --only: %w", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// outputDestination describes where the merged spec is written.
+func outputDestination(cfg *config.Config) string {
+	if cfg.WritesToStdout() {
+		return "standard output"
+	}
+	return cfg.Output
 }
 
 func loadConfig() (*config.Config, error) {
-	var cfg config.Config
+	if GetConfigFile() == stdinConfig && stdinConfigErr != nil {
+		return nil, stdinConfigErr
+	}
+
+	settings, err := configSettings()
+	if err != nil {
+		return nil, err
+	}
 
-	// Set up decoder options to use mapstructure tags
-	if err := viper.Unmarshal(&cfg, viper.DecodeHook(config.DecodeHook())); err != nil {
+	cfg, err := config.Decode(settings)
+	if err != nil {
 		return nil, fmt.Errorf("unable to decode config: %w", err)
 	}
 
-	// Resolve relative paths based on config file location
-	configDir := getConfigDir()
-	cfg.ResolveRelativePaths(configDir)
+	// Expand inputFile globs into one input per matching file
+	if err := cfg.Prepare(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// configSettings reads every config file, resolves its relative paths
+// against its own directory and deep-merges the files in order. The files
+// are read afresh on every call, so edits made while watching are picked up.
+func configSettings() (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	for i, file := range GetConfigFiles() {
+		data, err := readConfigData(file)
+		if err != nil {
+			return nil, err
+		}
+		fileSettings, err := config.ParseSettings(data)
+		if err != nil {
+			if file == stdinConfig {
+				return nil, fmt.Errorf("failed to parse config from standard input: %w", err)
+			}
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		resolveSettingsPaths(fileSettings, configFileDir(file))
+		settings = config.DeepMerge(settings, fileSettings)
+		if i > 0 && IsVerbose() {
+			fmt.Fprintln(os.Stderr, "Merged config file:", file)
+		}
+	}
+	return settings, nil
+}
+
+// configFileDir returns the directory relative paths in a config file are
+// resolved against: the file's own directory, or the working directory for
+// standard input.
+func configFileDir(file string) string {
+	if file == stdinConfig {
+		cwd, _ := os.Getwd()
+		return cwd
+	}
+	dir, _ := filepath.Abs(filepath.Dir(file))
+	return dir
+}
+
+// resolveSettingsPaths makes the relative file paths in the settings of one
+// config file absolute against dir, before the files are deep-merged and
+// the file each path came from is lost.
+func resolveSettingsPaths(settings map[string]interface{}, dir string) {
+	resolve := func(m map[string]interface{}, keys ...string) {
+		for key, value := range m {
+			path, ok := value.(string)
+			if !ok || !slices.ContainsFunc(keys, func(k string) bool { return strings.EqualFold(k, key) }) {
+				continue
+			}
+			if path == "" || path == config.StdoutOutput || config.IsURL(path) || filepath.IsAbs(path) {
+				continue
+			}
+			m[key] = filepath.Join(dir, path)
+		}
+	}
 
-	return &cfg, nil
+	resolve(settings, "output", "operationIndex", "reportFile", "lockFile")
+	for key, value := range settings {
+		switch {
+		case strings.EqualFold(key, "inputs"):
+			inputs, _ := value.([]interface{})
+			for _, input := range inputs {
+				if input, ok := input.(map[string]interface{}); ok {
+					resolve(input, "inputFile")
+				}
+			}
+		case strings.EqualFold(key, "auth"):
+			if auth, ok := value.(map[string]interface{}); ok {
+				resolve(auth, "tokenFile")
+			}
+		}
+	}
 }
 
 func getConfigDir() string {
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTestConfig loads the given config files the way the merge command does.
+func loadTestConfig(t *testing.T, files ...string) *config.Config {
+	t.Helper()
+	oldFiles := cfgFiles
+	t.Cleanup(func() { cfgFiles = oldFiles })
+	cfgFiles = files
+
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestLoadConfig_MultipleFilesResolvePathsPerFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	for _, dir := range []string{"a", "b"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, dir), 0755))
+	}
+	base := filepath.Join(tempDir, "a", "base.yaml")
+	extra := filepath.Join(tempDir, "b", "extra.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("inputs:\n  - inputFile: api1.json\noutput: merged.json\n"), 0644))
+	require.NoError(t, os.WriteFile(extra, []byte("inputs:\n  - inputFile: api2.json\nreportFile: report.json\nlockFile: merge.lock\n"), 0644))
+
+	cfg := loadTestConfig(t, base, extra)
+
+	require.Len(t, cfg.Inputs, 2)
+	assert.Equal(t, filepath.Join(tempDir, "a", "api1.json"), cfg.Inputs[0].InputFile)
+	assert.Equal(t, filepath.Join(tempDir, "b", "api2.json"), cfg.Inputs[1].InputFile)
+	assert.Equal(t, filepath.Join(tempDir, "a", "merged.json"), cfg.Output)
+	assert.Equal(t, filepath.Join(tempDir, "b", "report.json"), cfg.ReportFile)
+	assert.Equal(t, filepath.Join(tempDir, "b", "merge.lock"), cfg.LockFile)
+}
+
+// writeConfigFixture writes a users spec and the given YAML config next to
+// it, returning the config path.
+func writeConfigFixture(t *testing.T, configYAML string) string {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "users.json"), []byte(spec), 0644))
+
+	configPath := filepath.Join(tempDir, "merge-config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configYAML), 0644))
+	return configPath
+}
+
+func TestLoadConfig_DefaultErrorResponseSchemaKeepsCase(t *testing.T) {
+	cfg := loadTestConfig(t, writeConfigFixture(t, `
+inputs:
+  - inputFile: users.json
+output: merged.json
+defaultErrorResponse:
+  schemaRef: "#/components/schemas/Problem"
+  schema:
+    type: object
+    properties:
+      errorCode:
+        type: string
+        maxLength: 10
+`))
+	require.NoError(t, cfg.Validate())
+
+	doc, err := openapimerge.New(cfg, openapimerge.Options{}).MergeToDocument()
+	require.NoError(t, err)
+
+	problem := doc.Components.Schemas["Problem"]
+	require.NotNil(t, problem)
+	require.Contains(t, problem.Value.Properties, "errorCode")
+	require.NotNil(t, problem.Value.Properties["errorCode"].Value.MaxLength)
+	assert.Equal(t, uint64(10), *problem.Value.Properties["errorCode"].Value.MaxLength)
+	assert.Empty(t, problem.Value.Properties["errorCode"].Value.Extensions)
+}
+
+func TestLoadConfig_GlobalResponseRanges(t *testing.T) {
+	cfg := loadTestConfig(t, writeConfigFixture(t, `
+inputs:
+  - inputFile: users.json
+output: merged.json
+globalResponses:
+  5XX:
+    description: Server Error
+    schema:
+      type: object
+      properties:
+        traceId:
+          type: string
+          minLength: 8
+  4xx:
+    description: Client Error
+`))
+	require.NoError(t, cfg.Validate())
+
+	doc, err := openapimerge.New(cfg, openapimerge.Options{}).MergeToDocument()
+	require.NoError(t, err)
+
+	responses := doc.Paths.Value("/users").Get.Responses
+	require.NotNil(t, responses.Value("4XX"))
+	serverError := responses.Value("5XX")
+	require.NotNil(t, serverError)
+	schema := serverError.Value.Content.Get("application/json").Schema.Value
+	require.Contains(t, schema.Properties, "traceId")
+	assert.Equal(t, uint64(8), schema.Properties["traceId"].Value.MinLength)
+}
+
+func TestLoadConfig_GlobalParameterLengthLimits(t *testing.T) {
+	cfg := loadTestConfig(t, writeConfigFixture(t, `
+inputs:
+  - inputFile: users.json
+output: merged.json
+globalParameters:
+  - name: q
+    in: query
+    schema:
+      type: string
+      minLength: 2
+      maxLength: 10
+`))
+	require.NoError(t, cfg.Validate())
+
+	doc, err := openapimerge.New(cfg, openapimerge.Options{}).MergeToDocument()
+	require.NoError(t, err)
+
+	param := doc.Paths.Value("/users").Get.Parameters.GetByInAndName("query", "q")
+	require.NotNil(t, param)
+	assert.Equal(t, uint64(2), param.Schema.Value.MinLength)
+	require.NotNil(t, param.Schema.Value.MaxLength)
+	assert.Equal(t, uint64(10), *param.Schema.Value.MaxLength)
+}
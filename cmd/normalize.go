@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/spf13/cobra"
+)
+
+var (
+	normalizeOutput string
+	normalizePasses []string
+)
+
+// normalizeCmd represents the normalize command
+var normalizeCmd = &cobra.Command{
+	Use:   "normalize <spec-file>",
+	Short: "Clean up a single OpenAPI specification",
+	Long: `Load a single OpenAPI 2.0/3.x specification (file or URL), run
+normalization passes on it and write the result, without merging.
+
+Passes (all run by default, always in this order):
+  ` + strings.Join(openapimerge.NormalizePasses, ", ") + `
+
+Example:
+  openapi-merge normalize spec.yaml -o clean.yaml
+  openapi-merge normalize spec.json -o clean.json --passes prune,sort`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNormalize,
+}
+
+func init() {
+	rootCmd.AddCommand(normalizeCmd)
+
+	normalizeCmd.Flags().StringVarP(&normalizeOutput, "output", "o", "", "output file (.json, .yaml or .yml)")
+	normalizeCmd.Flags().StringSliceVar(&normalizePasses, "passes", openapimerge.NormalizePasses, "normalization passes to run")
+	_ = normalizeCmd.MarkFlagRequired("output")
+}
+
+func runNormalize(cmd *cobra.Command, args []string) error {
+	m := openapimerge.New(&config.Config{Output: normalizeOutput}, openapimerge.Options{Verbose: IsVerbose()})
+	err := m.Normalize(args[0], normalizePasses)
+	rep := &plainReporter{out: cmd.ErrOrStderr(), verbose: IsVerbose()}
+	for _, w := range m.Warnings() {
+		rep.Warning(w)
+	}
+	if err != nil {
+		return fmt.Errorf("normalize failed: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Successfully normalized %s into %s\n", args[0], normalizeOutput)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCmd(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"tags": [{"name": "users"}, {"name": "admin"}],
+		"paths": {
+			"/users": {
+				"get": {
+					"x-owner": "team-users",
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object"},
+				"Unused": {"type": "string"}
+			}
+		}
+	}`
+	inputPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(inputPath, []byte(spec), 0644))
+
+	tests := []struct {
+		name       string
+		passes     []string
+		wantUnused bool
+		wantOwner  bool
+	}{
+		{name: "all passes", passes: []string{"downconvert", "hoistExamples", "stripExtensions", "prune", "sort"}},
+		{name: "prune and sort", passes: []string{"prune", "sort"}, wantOwner: true},
+		{name: "strip only", passes: []string{"stripExtensions"}, wantUnused: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(tempDir, "clean-"+strings.Join(tt.passes, "-")+".json")
+			normalizeOutput, normalizePasses = outputPath, tt.passes
+			t.Cleanup(func() { normalizeOutput, normalizePasses = "", nil })
+
+			var out bytes.Buffer
+			normalizeCmd.SetOut(&out)
+			t.Cleanup(func() { normalizeCmd.SetOut(nil) })
+
+			require.NoError(t, normalizeCmd.RunE(normalizeCmd, []string{inputPath}))
+			assert.Contains(t, out.String(), "Successfully normalized")
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			var doc struct {
+				Paths map[string]struct {
+					Get map[string]interface{} `json:"get"`
+				} `json:"paths"`
+				Components struct {
+					Schemas map[string]interface{} `json:"schemas"`
+				} `json:"components"`
+			}
+			require.NoError(t, json.Unmarshal(data, &doc))
+			assert.Contains(t, doc.Components.Schemas, "User")
+			_, hasUnused := doc.Components.Schemas["Unused"]
+			assert.Equal(t, tt.wantUnused, hasUnused)
+			_, hasOwner := doc.Paths["/users"].Get["x-owner"]
+			assert.Equal(t, tt.wantOwner, hasOwner)
+		})
+	}
+}
+
+func TestNormalizeCmd_UnknownPass(t *testing.T) {
+	normalizeOutput, normalizePasses = "unused.json", []string{"minify"}
+	t.Cleanup(func() { normalizeOutput, normalizePasses = "", nil })
+
+	err := normalizeCmd.RunE(normalizeCmd, []string{"unused.json"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown normalization pass "minify"`)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+)
+
+// Supported reporter names
+const (
+	reporterPlain  = "plain"
+	reporterGitHub = "github"
+)
+
+// reporter outputs warnings and errors collected during a merge.
+type reporter interface {
+	Warning(w openapimerge.Warning)
+	Error(err error)
+}
+
+// newReporter returns the reporter registered under the given name. The
+// plain reporter only prints the loader's validation findings in verbose mode.
+func newReporter(name string, out, errOut io.Writer, verbose bool) (reporter, error) {
+	switch name {
+	case "", reporterPlain:
+		return &plainReporter{out: errOut, verbose: verbose}, nil
+	case reporterGitHub:
+		return &githubReporter{out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown reporter %q (expected %s or %s)", name, reporterPlain, reporterGitHub)
+	}
+}
+
+// plainReporter writes warnings as plain text lines; validation findings
+// are only written in verbose mode. Errors are left to the root command,
+// which prints them on exit.
+type plainReporter struct {
+	out     io.Writer
+	verbose bool
+}
+
+func (r *plainReporter) Warning(w openapimerge.Warning) {
+	if w.Validation && !r.verbose {
+		return
+	}
+	_, _ = fmt.Fprintf(r.out, "Warning: %s\n", w)
+}
+
+func (r *plainReporter) Error(err error) {}
+
+// githubReporter writes warnings and errors as GitHub Actions workflow commands,
+// so they show up as annotations on pull requests.
+type githubReporter struct {
+	out io.Writer
+}
+
+func (r *githubReporter) Warning(w openapimerge.Warning) {
+	r.annotate("warning", w.Source, w.Message)
+}
+
+func (r *githubReporter) Error(err error) {
+	source := ""
+	var inputErr *openapimerge.InputError
+	if errors.As(err, &inputErr) {
+		source = inputErr.Source
+	}
+	r.annotate("error", source, err.Error())
+}
+
+// annotate writes a single workflow command line.
+func (r *githubReporter) annotate(level, source, message string) {
+	if source == "" {
+		_, _ = fmt.Fprintf(r.out, "::%s::%s\n", level, escapeAnnotationData(message))
+		return
+	}
+	_, _ = fmt.Fprintf(r.out, "::%s file=%s::%s\n", level,
+		escapeAnnotationProperty(annotationPath(source)), escapeAnnotationData(message))
+}
+
+// annotationPath makes local file paths relative to the working directory,
+// which is the repository root in a typical Actions checkout.
+func annotationPath(source string) string {
+	if !filepath.IsAbs(source) {
+		return source
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return source
+	}
+	rel, err := filepath.Rel(cwd, source)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return source
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeAnnotationData escapes a workflow command message.
+func escapeAnnotationData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// escapeAnnotationProperty escapes a workflow command property value.
+func escapeAnnotationProperty(s string) string {
+	s = escapeAnnotationData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	s = strings.ReplaceAll(s, ",", "%2C")
+	return s
+}
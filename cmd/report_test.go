@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubReporter(t *testing.T) {
+	var out bytes.Buffer
+	rep, err := newReporter(reporterGitHub, &out, &bytes.Buffer{}, false)
+	require.NoError(t, err)
+
+	rep.Warning(openapimerge.Warning{Source: "apis/users.json", Message: "validation issues: bad\nschema"})
+	rep.Warning(openapimerge.Warning{Message: "100% unsourced"})
+	rep.Error(&openapimerge.InputError{Source: "apis/orders.json", Err: errors.New("failed to load apis/orders.json")})
+
+	assert.Equal(t,
+		"::warning file=apis/users.json::validation issues: bad%0Aschema\n"+
+			"::warning::100%25 unsourced\n"+
+			"::error file=apis/orders.json::failed to load apis/orders.json\n",
+		out.String())
+}
+
+func TestPlainReporter(t *testing.T) {
+	var errOut bytes.Buffer
+	rep, err := newReporter(reporterPlain, &bytes.Buffer{}, &errOut, true)
+	require.NoError(t, err)
+
+	rep.Warning(openapimerge.Warning{Source: "api.json", Message: "something odd"})
+	assert.Equal(t, "Warning: api.json: something odd\n", errOut.String())
+
+	// Without --verbose only the loader's validation findings are hidden
+	errOut.Reset()
+	rep, err = newReporter(reporterPlain, &bytes.Buffer{}, &errOut, false)
+	require.NoError(t, err)
+	rep.Warning(openapimerge.Warning{Source: "api.json", Message: "validation issues: bad schema", Validation: true})
+	rep.Warning(openapimerge.Warning{Source: "api.json", Message: "something odd"})
+	assert.Equal(t, "Warning: api.json: something odd\n", errOut.String())
+}
+
+func TestUnknownReporter(t *testing.T) {
+	_, err := newReporter("xml", &bytes.Buffer{}, &bytes.Buffer{}, false)
+	assert.Error(t, err)
+}
@@ -3,15 +3,31 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
+// stdinConfig is the --config value that reads the configuration from
+// standard input.
+const stdinConfig = "-"
+
 var (
-	cfgFile string
-	verbose bool
+	cfgFiles []string
+	verbose  bool
+
+	// stdin is where a "-" config is read from
+	stdin io.Reader = os.Stdin
+
+	// stdinConfigErr holds the error from reading the config from stdin, which
+	// can only be read once, in initConfig
+	stdinConfigErr error
+
+	// stdinConfigData holds the config read from stdin
+	stdinConfigData []byte
 
 	// Version info set by main
 	version = "dev"
@@ -24,6 +40,7 @@ func SetVersionInfo(v, c, d string) {
 	version = v
 	commit = c
 	date = d
+	openapimerge.SetVersion(v)
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -54,8 +71,9 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (required for merge)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfgFiles, "config", nil, "config file, or - for standard input (required for merge); repeat to deep-merge later files over earlier ones")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
+	_ = rootCmd.MarkPersistentFlagFilename("config", "yaml", "yml", "json")
 
 	// Set version template
 	rootCmd.SetVersionTemplate(`{{.Name}} {{.Version}}
@@ -71,17 +89,41 @@ func updateVersion() {
 	}
 }
 
-// initConfig reads in config file and ENV variables if set.
+// initConfig buffers a config read from standard input, which can only be
+// read once. Config files are read each time the config is loaded.
 func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
+	cfgFile := GetConfigFile()
+	if cfgFile == stdinConfig {
+		stdinConfigErr = readStdinConfig()
+		return
 	}
+	if cfgFile != "" && verbose {
+		fmt.Fprintln(os.Stderr, "Using config file:", cfgFile)
+	}
+}
 
-	viper.AutomaticEnv()
+// readStdinConfig reads the config given as "-" from standard input. It is
+// parsed along with the config files, as YAML, which also accepts JSON.
+func readStdinConfig() error {
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read config from standard input: %w", err)
+	}
+	stdinConfigData = data
+	return nil
+}
 
-	if err := viper.ReadInConfig(); err == nil && verbose {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+// readConfigData returns the contents of a config file, or of the config
+// read from standard input by initConfig.
+func readConfigData(file string) ([]byte, error) {
+	if file == stdinConfig {
+		return stdinConfigData, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
 	}
+	return data, nil
 }
 
 // IsVerbose returns whether verbose mode is enabled.
@@ -89,7 +131,21 @@ func IsVerbose() bool {
 	return verbose
 }
 
-// GetConfigFile returns the config file path.
+// commandLine returns the command line the tool was run with, for the output
+// header.
+func commandLine() string {
+	return strings.Join(append([]string{"openapi-merge"}, os.Args[1:]...), " ")
+}
+
+// GetConfigFile returns the first config file path.
 func GetConfigFile() string {
-	return cfgFile
+	if len(cfgFiles) == 0 {
+		return ""
+	}
+	return cfgFiles[0]
+}
+
+// GetConfigFiles returns all config file paths, in the order given.
+func GetConfigFiles() []string {
+	return cfgFiles
 }
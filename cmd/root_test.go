@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Stdin(t *testing.T) {
+	oldStdin, oldFiles := stdin, cfgFiles
+	t.Cleanup(func() {
+		stdin, cfgFiles = oldStdin, oldFiles
+		stdinConfigData, stdinConfigErr = nil, nil
+	})
+	cfgFiles = []string{stdinConfig}
+
+	stdin = strings.NewReader("inputs:\n  - inputFile: users.yaml\noutput: \"-\"\n")
+	initConfig()
+	cfg, err := loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "-", cfg.Output)
+
+	// JSON is valid YAML
+	stdin = strings.NewReader(`{"output": "merged.json"}`)
+	initConfig()
+	cfg, err = loadConfig()
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(cfg.Output, "merged.json"))
+
+	stdin = strings.NewReader("output: [")
+	initConfig()
+	_, err = loadConfig()
+	assert.ErrorContains(t, err, "standard input")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/spf13/cobra"
+)
+
+var statsFormat string
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats <spec-file>",
+	Short: "Report metrics for a single OpenAPI specification",
+	Long: `Load a single OpenAPI 2.0/3.x specification (file or URL) and report
+metrics about it: paths, operations by method, schemas, parameters, tags,
+average properties per schema, and the largest schemas.
+
+Example:
+  openapi-merge stats apis/users.json
+  openapi-merge stats apis/users.yaml --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsFormat, "format", "text", "output format: text or json")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if statsFormat != "text" && statsFormat != "json" {
+		return fmt.Errorf("unknown format %q (expected text or json)", statsFormat)
+	}
+
+	m := openapimerge.New(&config.Config{}, openapimerge.Options{Verbose: IsVerbose()})
+	spec, err := m.LoadSpec(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", args[0], err)
+	}
+
+	stats := openapimerge.ComputeStats(spec)
+	out := cmd.OutOrStdout()
+
+	if statsFormat == "json" {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal stats: %w", err)
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	printStats(out, stats)
+	return nil
+}
+
+// printStats writes stats in a human-readable form.
+func printStats(out io.Writer, stats openapimerge.SpecStats) {
+	_, _ = fmt.Fprintf(out, "Paths:               %d\n", stats.Paths)
+	_, _ = fmt.Fprintf(out, "Operations:          %d\n", stats.Operations)
+	for _, method := range []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE"} {
+		if count := stats.OperationsByMethod[method]; count > 0 {
+			_, _ = fmt.Fprintf(out, "  %-18s %d\n", method+":", count)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "Schemas:             %d\n", stats.Schemas)
+	_, _ = fmt.Fprintf(out, "Parameters:          %d (components), %d (in operations)\n", stats.Parameters, stats.OperationParameters)
+	_, _ = fmt.Fprintf(out, "Tags:                %d\n", stats.Tags)
+	_, _ = fmt.Fprintf(out, "Avg props/schema:    %.1f\n", stats.AvgPropertiesPerSchema)
+
+	if len(stats.LargestSchemas) > 0 {
+		_, _ = fmt.Fprintln(out, "Largest schemas:")
+		for _, s := range stats.LargestSchemas {
+			_, _ = fmt.Fprintf(out, "  %-30s %d properties\n", s.Name, s.Properties)
+		}
+	}
+}
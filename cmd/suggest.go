@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+)
+
+// printPrefixSuggestions writes the suggested dispute prefixes as a config
+// snippet that can be pasted into the inputs list. Paths are shown relative
+// to baseDir when possible.
+func printPrefixSuggestions(w io.Writer, suggestions []openapimerge.PrefixSuggestion, baseDir string) {
+	if len(suggestions) == 0 {
+		fmt.Fprintln(w, "No component conflicts found; no dispute prefixes needed.")
+		return
+	}
+
+	fmt.Fprintln(w, "# Add these dispute prefixes to the matching inputs:")
+	fmt.Fprintln(w, "inputs:")
+	for _, s := range suggestions {
+		fmt.Fprintf(w, "  - inputFile: %s\n", relativeTo(baseDir, s.InputFile))
+		for _, c := range s.Conflicts {
+			fmt.Fprintf(w, "    # %s/%s conflicts with %s\n", c.Kind, c.Name, relativeTo(baseDir, c.First))
+		}
+		fmt.Fprintln(w, "    dispute:")
+		fmt.Fprintf(w, "      prefix: %q\n", s.Prefix)
+	}
+}
+
+// relativeTo returns path relative to baseDir, or path unchanged if it is a
+// URL or cannot be made relative.
+func relativeTo(baseDir, path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	if rel, err := filepath.Rel(baseDir, path); err == nil {
+		return rel
+	}
+	return path
+}
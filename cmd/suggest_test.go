@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintPrefixSuggestions(t *testing.T) {
+	var buf bytes.Buffer
+	printPrefixSuggestions(&buf, []openapimerge.PrefixSuggestion{
+		{
+			InputFile: "/configs/apis/orders.json",
+			Prefix:    "Orders_",
+			Conflicts: []openapimerge.Conflict{
+				{Kind: "schemas", Name: "User", First: "/configs/apis/users.json", Second: "/configs/apis/orders.json"},
+			},
+		},
+	}, "/configs")
+
+	assert.Equal(t, `# Add these dispute prefixes to the matching inputs:
+inputs:
+  - inputFile: apis/orders.json
+    # schemas/User conflicts with apis/users.json
+    dispute:
+      prefix: "Orders_"
+`, buf.String())
+
+	buf.Reset()
+	printPrefixSuggestions(&buf, nil, "/configs")
+	assert.Equal(t, "No component conflicts found; no dispute prefixes needed.\n", buf.String())
+}
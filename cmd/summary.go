@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+)
+
+// printMergeSummary writes the result of a dry run: what each input
+// contributed, the merged components by kind, renamed components, the
+// operations dropped by filters and rewritten paths. Paths are shown relative
+// to baseDir when possible.
+func printMergeSummary(w io.Writer, summary *openapimerge.Summary, baseDir string) {
+	fmt.Fprintln(w, "Inputs:")
+	for _, input := range summary.Inputs {
+		fmt.Fprintf(w, "  %s: %d paths, %d operations\n", relativeTo(baseDir, input.Source), input.Paths, input.Operations)
+	}
+
+	fmt.Fprintln(w, "Components:")
+	if len(summary.Components) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	kinds := make([]string, 0, len(summary.Components))
+	for kind := range summary.Components {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "  %s: %d\n", kind, summary.Components[kind])
+	}
+
+	if len(summary.Renames) > 0 {
+		fmt.Fprintln(w, "Renamed components:")
+		for _, r := range summary.Renames {
+			fmt.Fprintf(w, "  %s/%s -> %s (%s)\n", r.Kind, r.From, r.To, relativeTo(baseDir, r.Source))
+		}
+	}
+
+	if len(summary.Skipped) > 0 {
+		fmt.Fprintln(w, "Skipped operations:")
+		for _, s := range summary.Skipped {
+			fmt.Fprintf(w, "  %s %s (%s): %s\n", s.Method, s.Path, relativeTo(baseDir, s.Source), s.Reason)
+		}
+	}
+
+	if len(summary.PathRewrites) > 0 {
+		fmt.Fprintln(w, "Rewritten paths:")
+		for _, r := range summary.PathRewrites {
+			fmt.Fprintf(w, "  %s -> %s (%s)\n", r.From, r.To, relativeTo(baseDir, r.Source))
+		}
+	}
+}
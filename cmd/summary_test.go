@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintMergeSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printMergeSummary(&buf, &openapimerge.Summary{
+		Inputs: []openapimerge.InputSummary{
+			{Source: "/configs/apis/users.json", Paths: 2, Operations: 3},
+			{Source: "/configs/apis/orders.json", Paths: 1, Operations: 1},
+		},
+		Components: map[string]int{"schemas": 3, "parameters": 1},
+		Renames: []openapimerge.ComponentRename{
+			{Kind: "schemas", From: "User", To: "Orders_User", Source: "/configs/apis/orders.json"},
+		},
+		Skipped: []openapimerge.SkippedOperation{
+			{Method: "DELETE", Path: "/users/{id}", Source: "/configs/apis/users.json", Reason: "operationSelection"},
+		},
+		PathRewrites: []openapimerge.PathRewrite{
+			{From: "/orders", To: "/api/orders", Source: "/configs/apis/orders.json"},
+		},
+	}, "/configs")
+
+	assert.Equal(t, `Inputs:
+  apis/users.json: 2 paths, 3 operations
+  apis/orders.json: 1 paths, 1 operations
+Components:
+  parameters: 1
+  schemas: 3
+Renamed components:
+  schemas/User -> Orders_User (apis/orders.json)
+Skipped operations:
+  DELETE /users/{id} (apis/users.json): operationSelection
+Rewritten paths:
+  /orders -> /api/orders (apis/orders.json)
+`, buf.String())
+
+	buf.Reset()
+	printMergeSummary(&buf, &openapimerge.Summary{}, "/configs")
+	assert.Equal(t, "Inputs:\nComponents:\n  none\n", buf.String())
+}
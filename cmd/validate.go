@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/spf13/cobra"
+)
+
+var validateFormat string
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the merged specification without writing it",
+	Long: `Run the merge described by the config in memory and check the result:
+every $ref must resolve, operationIds must be unique, and the specification
+must pass OpenAPI validation. No output file is written. The command exits
+with an error if any problem is found, for gating merges in CI.
+
+Example:
+  openapi-merge validate --config merge-config.yaml
+  openapi-merge validate --config merge-config.yaml --format json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if GetConfigFile() == "" {
+			return fmt.Errorf("required flag \"config\" not set")
+		}
+		return nil
+	},
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text or json")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if validateFormat != "text" && validateFormat != "json" {
+		return fmt.Errorf("unknown format %q (expected text or json)", validateFormat)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	m := openapimerge.New(cfg, openapimerge.Options{Verbose: IsVerbose()})
+	problems, err := m.Validate()
+	rep := &plainReporter{out: cmd.ErrOrStderr(), verbose: IsVerbose()}
+	for _, w := range m.Warnings() {
+		rep.Warning(w)
+	}
+	if err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	if err := printValidationProblems(cmd.OutOrStdout(), problems, validateFormat); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("validation failed with %d problems", len(problems))
+	}
+	return nil
+}
+
+// printValidationProblems writes the problems as a list, or as a JSON array.
+func printValidationProblems(out io.Writer, problems []openapimerge.ValidationProblem, format string) error {
+	if format == "json" {
+		if problems == nil {
+			problems = []openapimerge.ValidationProblem{}
+		}
+		data, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal problems: %w", err)
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	if len(problems) == 0 {
+		_, _ = fmt.Fprintln(out, "Merged specification is valid")
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Found %d problems:\n", len(problems))
+	for _, p := range problems {
+		_, _ = fmt.Fprintf(out, "  %s\n", p)
+	}
+	return nil
+}
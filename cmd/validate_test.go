@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintValidationProblems(t *testing.T) {
+	problems := []openapimerge.ValidationProblem{
+		{Kind: openapimerge.ProblemRef, Location: "#/paths/~1users/get", Message: "#/components/schemas/Gone does not resolve"},
+		{Kind: openapimerge.ProblemOperationID, Location: "list", Message: "used by GET /orders, GET /users"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, printValidationProblems(&text, problems, "text"))
+	assert.Equal(t, `Found 2 problems:
+  [ref] #/paths/~1users/get: #/components/schemas/Gone does not resolve
+  [operationId] list: used by GET /orders, GET /users
+`, text.String())
+
+	var valid bytes.Buffer
+	require.NoError(t, printValidationProblems(&valid, nil, "text"))
+	assert.Equal(t, "Merged specification is valid\n", valid.String())
+
+	var empty bytes.Buffer
+	require.NoError(t, printValidationProblems(&empty, nil, "json"))
+	assert.Equal(t, "[]\n", empty.String())
+}
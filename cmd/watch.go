@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// defaultWatchInterval is how often --watch polls URL inputs.
+const defaultWatchInterval = 30 * time.Second
+
+// watchDebounce groups the file events of a single save into one merge.
+const watchDebounce = 100 * time.Millisecond
+
+// mergeWatcher merges again whenever a config file or a local input changes.
+type mergeWatcher struct {
+	out         io.Writer
+	interval    time.Duration
+	configFiles []string
+	load        func() (*config.Config, error)
+	merge       func(*config.Config) error
+
+	watcher *fsnotify.Watcher
+	files   map[string]bool
+	dirs    map[string]bool
+	hasURLs bool
+
+	merged   bool
+	lastOK   bool
+	lastHash [sha256.Size]byte
+}
+
+// run merges once and then on every change until ctx is done, printing a
+// timestamped line for each merge. URL inputs are polled every interval, and
+// a poll only prints when the outcome or the merged output changed. Failed
+// merges, including a config that no longer loads, do not stop the watcher.
+func (mw *mergeWatcher) run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watching files: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	mw.watcher = watcher
+	mw.files = make(map[string]bool)
+	mw.dirs = make(map[string]bool)
+
+	mw.mergeOnce(false)
+
+	var poll <-chan time.Time
+	if mw.interval > 0 {
+		ticker := time.NewTicker(mw.interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op != fsnotify.Chmod && mw.files[filepath.Clean(event.Name)] {
+				pending = time.After(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			mw.printf("Watch error: %v", err)
+		case <-pending:
+			pending = nil
+			mw.mergeOnce(false)
+		case <-poll:
+			if mw.hasURLs {
+				mw.mergeOnce(true)
+			}
+		}
+	}
+}
+
+// mergeOnce loads the config, updates the watched files and merges. Polls
+// stay quiet unless something changed since the previous merge.
+func (mw *mergeWatcher) mergeOnce(poll bool) {
+	cfg, err := mw.load()
+	if err == nil {
+		mw.watchInputs(cfg)
+		err = mw.merge(cfg)
+	} else {
+		mw.watchInputs(nil)
+	}
+
+	var hash [sha256.Size]byte
+	if err == nil {
+		if data, readErr := os.ReadFile(cfg.Output); readErr == nil {
+			hash = sha256.Sum256(data)
+		}
+	}
+	ok := err == nil
+	changed := !mw.merged || ok != mw.lastOK || !bytes.Equal(hash[:], mw.lastHash[:])
+	mw.merged, mw.lastOK, mw.lastHash = true, ok, hash
+	if poll && !changed {
+		return
+	}
+
+	if err != nil {
+		mw.printf("Merge failed: %v", err)
+		return
+	}
+	mw.printf("Merged %d specifications into %s", len(cfg.Inputs), outputDestination(cfg))
+}
+
+// watchInputs watches the config files and the local inputs of cfg. Their
+// directories are watched rather than the files, so that editors that save
+// by replacing a file are noticed too.
+func (mw *mergeWatcher) watchInputs(cfg *config.Config) {
+	paths := make([]string, 0, len(mw.configFiles))
+	for _, file := range mw.configFiles {
+		if file != stdinConfig {
+			paths = append(paths, file)
+		}
+	}
+	if cfg != nil {
+		mw.hasURLs = false
+		for _, input := range cfg.Inputs {
+			if config.IsURL(input.InputFile) {
+				mw.hasURLs = true
+				continue
+			}
+			paths = append(paths, input.InputFile)
+		}
+	}
+
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		mw.files[path] = true
+
+		dir := filepath.Dir(path)
+		if mw.dirs[dir] {
+			continue
+		}
+		if err := mw.watcher.Add(dir); err != nil {
+			mw.printf("Cannot watch %s: %v", dir, err)
+			continue
+		}
+		mw.dirs[dir] = true
+	}
+}
+
+// printf writes a line prefixed with the current time.
+func (mw *mergeWatcher) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(mw.out, "[%s] %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
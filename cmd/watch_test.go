@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/rperez95/openapi-merge/pkg/openapimerge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func watchSpec(title string) string {
+	return `{"openapi": "3.0.0", "info": {"title": "` + title + `", "version": "1.0.0"}, "paths": {"/` + strings.ToLower(title) + `": {"get": {"responses": {"200": {"description": "OK"}}}}}}`
+}
+
+// startWatcher runs a mergeWatcher for cfg until the test ends.
+func startWatcher(t *testing.T, cfg *config.Config, interval time.Duration) *syncBuffer {
+	t.Helper()
+	out := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- (&mergeWatcher{
+			out:      out,
+			interval: interval,
+			load:     func() (*config.Config, error) { return cfg, nil },
+			merge:    func(cfg *config.Config) error { return openapimerge.New(cfg, openapimerge.Options{}).Merge() },
+		}).run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return out
+}
+
+func TestMergeWatcher_LocalInputs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	input := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(input, []byte(watchSpec("Users")), 0644))
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: input}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	out := startWatcher(t, cfg, 0)
+	waitFor := func(s string, n int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return strings.Count(out.String(), s) == n }, 5*time.Second, 20*time.Millisecond, out.String())
+	}
+
+	waitFor("Merged 1 specifications into "+cfg.Output, 1)
+
+	// A broken input is reported and the watcher carries on
+	require.NoError(t, os.WriteFile(input, []byte("{"), 0644))
+	waitFor("Merge failed:", 1)
+
+	require.NoError(t, os.WriteFile(input, []byte(watchSpec("Accounts")), 0644))
+	waitFor("Merged 1 specifications into "+cfg.Output, 2)
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "/accounts")
+}
+
+func TestMergeWatcher_PollsURLInputs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	var title atomic.Value
+	title.Store("Users")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(watchSpec(title.Load().(string))))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: server.URL + "/openapi.json"}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	out := startWatcher(t, cfg, 20*time.Millisecond)
+	merged := func() int { return strings.Count(out.String(), "Merged 1 specifications") }
+	require.Eventually(t, func() bool { return merged() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Polls that change nothing stay quiet
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, merged(), out.String())
+
+	title.Store("Orders")
+	require.Eventually(t, func() bool { return merged() == 2 }, 5*time.Second, 10*time.Millisecond, out.String())
+}
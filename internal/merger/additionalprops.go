@@ -0,0 +1,20 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// applyDefaultAdditionalProperties sets additionalProperties to value on every
+// object schema that does not declare it.
+func applyDefaultAdditionalProperties(spec *openapi3.T, value bool) {
+	walkSpecSchemas(spec, func(schema *openapi3.Schema) {
+		if schema.Type == nil || !schema.Type.Is(openapi3.TypeObject) {
+			return
+		}
+		if schema.AdditionalProperties.Has != nil || schema.AdditionalProperties.Schema != nil {
+			return
+		}
+		v := value
+		schema.AdditionalProperties.Has = &v
+	})
+}
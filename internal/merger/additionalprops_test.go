@@ -0,0 +1,94 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_DefaultAdditionalProperties(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "Success",
+							"content": {
+								"application/json": {
+									"schema": {"type": "object", "properties": {"total": {"type": "integer"}}}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {
+					"type": "object",
+					"properties": {
+						"address": {"type": "object", "properties": {"city": {"type": "string"}}},
+						"name": {"type": "string"}
+					}
+				},
+				"Labels": {
+					"type": "object",
+					"additionalProperties": {"type": "string"}
+				},
+				"Open": {
+					"type": "object",
+					"additionalProperties": true
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	value := false
+	cfg := &config.Config{
+		Inputs:                      []config.InputConfig{{InputFile: specPath}},
+		Output:                      filepath.Join(tempDir, "merged.json"),
+		DefaultAdditionalProperties: &value,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := m.master.Components.Schemas
+
+	user := schemas["User"].Value
+	require.NotNil(t, user.AdditionalProperties.Has)
+	assert.False(t, *user.AdditionalProperties.Has)
+
+	address := user.Properties["address"].Value
+	require.NotNil(t, address.AdditionalProperties.Has)
+	assert.False(t, *address.AdditionalProperties.Has)
+
+	// Non-object schemas are untouched
+	assert.Nil(t, user.Properties["name"].Value.AdditionalProperties.Has)
+
+	// Explicit values are preserved
+	assert.Nil(t, schemas["Labels"].Value.AdditionalProperties.Has)
+	assert.NotNil(t, schemas["Labels"].Value.AdditionalProperties.Schema)
+	require.NotNil(t, schemas["Open"].Value.AdditionalProperties.Has)
+	assert.True(t, *schemas["Open"].Value.AdditionalProperties.Has)
+
+	// Inline schemas in paths are covered too
+	inline := m.master.Paths.Value("/users").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value
+	require.NotNil(t, inline.AdditionalProperties.Has)
+	assert.False(t, *inline.AdditionalProperties.Has)
+}
@@ -0,0 +1,67 @@
+package merger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// reconcileBasePathServers handles output servers whose URL path already ends
+// with the global basePath, which would otherwise be applied twice by clients
+// (https://api.example.com/v1 + /v1/users).
+func (m *Merger) reconcileBasePathServers() {
+	mode := m.cfg.BasePathOverlap
+	if mode == config.BasePathOverlapIgnore {
+		return
+	}
+
+	basePath := normalizedBasePath(m.cfg.BasePath)
+	if basePath == "" {
+		return
+	}
+
+	for _, server := range m.master.Servers {
+		trimmed, ok := trimServerBasePath(server.URL, basePath)
+		if !ok {
+			continue
+		}
+		if mode == config.BasePathOverlapStrip {
+			if m.verbose {
+				fmt.Fprintf(m.log, "Stripped basePath %s from server %s\n", basePath, server.URL)
+			}
+			server.URL = trimmed
+			continue
+		}
+		m.warnf("", "server %s already ends with basePath %s; paths will carry it twice (set basePathOverlap: strip to remove it from the server)",
+			server.URL, basePath)
+	}
+}
+
+// trimServerBasePath returns serverURL without basePath at the end of its
+// path, and whether it ended with it. Server URLs may be relative and may
+// contain {variables}, so they are not parsed as URLs.
+func trimServerBasePath(serverURL, basePath string) (string, bool) {
+	rest := strings.TrimSuffix(serverURL, "/")
+
+	pathStart := 0
+	if i := strings.Index(rest, "://"); i >= 0 {
+		slash := strings.Index(rest[i+3:], "/")
+		if slash < 0 {
+			return serverURL, false
+		}
+		pathStart = i + 3 + slash
+	}
+
+	// basePath starts with /, so this matches whole segments only:
+	// /api/v1 ends with /v1, /apiv1 does not
+	if !strings.HasSuffix(rest[pathStart:], basePath) {
+		return serverURL, false
+	}
+
+	trimmed := rest[:len(rest)-len(basePath)]
+	if trimmed == "" {
+		trimmed = "/"
+	}
+	return trimmed, true
+}
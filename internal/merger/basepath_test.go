@@ -0,0 +1,101 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_BasePathOverlap(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	merge := func(t *testing.T, mode string) *Merger {
+		cfg := &config.Config{
+			Inputs:          []config.InputConfig{{InputFile: specPath}},
+			Output:          filepath.Join(tempDir, "merged.json"),
+			BasePath:        "/v1",
+			BasePathOverlap: mode,
+			Servers: []config.ServerConfig{
+				{URL: "https://api.example.com/v1"},
+				{URL: "https://{region}.example.com/v1/"},
+				{URL: "https://legacy.example.com/apiv1"},
+			},
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		return m
+	}
+
+	serverURLs := func(m *Merger) []string {
+		var urls []string
+		for _, server := range m.master.Servers {
+			urls = append(urls, server.URL)
+		}
+		return urls
+	}
+
+	t.Run("strip", func(t *testing.T) {
+		m := merge(t, config.BasePathOverlapStrip)
+		assert.NotNil(t, m.master.Paths.Value("/v1/users"))
+		assert.Equal(t, []string{
+			"https://api.example.com",
+			"https://{region}.example.com",
+			"https://legacy.example.com/apiv1",
+		}, serverURLs(m))
+		assert.Empty(t, m.Warnings())
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		m := merge(t, "")
+		assert.Equal(t, "https://api.example.com/v1", m.master.Servers[0].URL)
+		require.Len(t, m.Warnings(), 2)
+		assert.Contains(t, m.Warnings()[0].Message, "server https://api.example.com/v1 already ends with basePath /v1")
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		m := merge(t, config.BasePathOverlapIgnore)
+		assert.Equal(t, "https://api.example.com/v1", m.master.Servers[0].URL)
+		assert.Empty(t, m.Warnings())
+	})
+}
+
+func TestTrimServerBasePath(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		overlap bool
+	}{
+		{"https://api.example.com/v1", "https://api.example.com", true},
+		{"https://api.example.com/gateway/v1", "https://api.example.com/gateway", true},
+		{"/v1", "/", true},
+		{"https://api.example.com", "https://api.example.com", false},
+		{"https://api.example.com/v10", "https://api.example.com/v10", false},
+		{"https://v1", "https://v1", false},
+	}
+
+	for _, tt := range tests {
+		got, overlap := trimServerBasePath(tt.url, "/v1")
+		assert.Equal(t, tt.want, got, tt.url)
+		assert.Equal(t, tt.overlap, overlap, tt.url)
+	}
+}
@@ -0,0 +1,322 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// refBundler copies the targets of external references into the components
+// of an input and points the references at the copies.
+type refBundler struct {
+	m    *Merger
+	spec *openapi3.T
+
+	// root is the location of the input itself
+	root string
+
+	// headers are the input's request headers, sent only to its own host
+	headers map[string]string
+	host    string
+
+	// docs caches parsed external documents by location
+	docs map[string]interface{}
+
+	// bundled maps each external reference (location#pointer) to the local
+	// reference of its copy; an entry is added before the target's own
+	// references are followed, which stops cyclic references
+	bundled map[string]string
+}
+
+// bundleExternalRefs resolves every $ref to another file or URL in the parsed
+// input, copies its target into the input's components and returns the
+// rewritten document. References inside bundled documents are resolved
+// relative to the document they appear in; documents on the input's host are
+// fetched with the input's headers. Components keep the last token of
+// the reference as their name, with a numeric suffix if the input already
+// uses it; collisions across inputs go through the usual dispute and conflict
+// handling.
+func (m *Merger) bundleExternalRefs(filePath string, headers map[string]string, raw map[string]interface{}) ([]byte, error) {
+	root, err := inputLocation(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode without resolving references so they can be rewritten
+	data, err := json.Marshal(stringKeys(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle external references: %w", err)
+	}
+	var spec openapi3.T
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to bundle external references: %w", err)
+	}
+
+	b := &refBundler{
+		m:       m,
+		spec:    &spec,
+		root:    root.String(),
+		headers: headers,
+		host:    root.Host,
+		docs:    make(map[string]interface{}),
+		bundled: make(map[string]string),
+	}
+
+	var bundleErr error
+	visitRefs(&spec, func(kind string, ref *string) {
+		if bundleErr != nil || strings.HasPrefix(*ref, "#") {
+			return
+		}
+		*ref, bundleErr = b.bundle(kind, root, *ref)
+	})
+	if bundleErr != nil {
+		return nil, bundleErr
+	}
+
+	if m.verbose && len(b.bundled) > 0 {
+		fmt.Fprintf(m.log, "  Bundled %d external components\n", len(b.bundled))
+	}
+
+	return json.Marshal(&spec)
+}
+
+// inputLocation returns the location external references of an input are
+// resolved against.
+func inputLocation(filePath string) (*url.URL, error) {
+	if config.IsURL(filePath) {
+		return url.Parse(convertGitHubURL(filePath))
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", filePath, err)
+	}
+	return &url.URL{Path: filepath.ToSlash(abs)}, nil
+}
+
+// bundle copies the target of ref, found in the document at from, into the
+// components and returns the local reference to it.
+func (b *refBundler) bundle(kind string, from *url.URL, ref string) (string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	target := from.ResolveReference(refURL)
+	pointer := target.Fragment
+	target.Fragment = ""
+	location := target.String()
+
+	// A bundled document may point back into the input itself
+	if location == b.root {
+		return "#" + pointer, nil
+	}
+
+	key := location + "#" + pointer
+	if local, ok := b.bundled[key]; ok {
+		return local, nil
+	}
+
+	doc, err := b.load(target)
+	if err != nil {
+		return "", err
+	}
+	value, ok := lookupPointer(doc, pointer)
+	if !ok {
+		return "", fmt.Errorf("external reference %s does not resolve", key)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to bundle %s: %w", key, err)
+	}
+
+	name := b.componentName(kind, pointer, target.Path)
+	local := componentsRefPrefix + kind + "/" + name
+	b.bundled[key] = local
+
+	var nestedErr error
+	nested := func(nestedKind string, nestedRef *string) {
+		if nestedErr == nil {
+			*nestedRef, nestedErr = b.bundle(nestedKind, target, *nestedRef)
+		}
+	}
+	if err := b.addComponent(kind, name, data, nested); err != nil {
+		return "", fmt.Errorf("failed to bundle %s: %w", key, err)
+	}
+	if nestedErr != nil {
+		return "", nestedErr
+	}
+
+	return local, nil
+}
+
+// load returns the parsed document at location, reading it on first use.
+func (b *refBundler) load(location *url.URL) (interface{}, error) {
+	if doc, ok := b.docs[location.String()]; ok {
+		return doc, nil
+	}
+
+	var data []byte
+	var ext string
+	var err error
+	if location.Scheme == "http" || location.Scheme == "https" {
+		var headers map[string]string
+		if location.Host == b.host {
+			headers = b.headers
+		}
+		data, ext, err = b.m.fetchFromURL(location.String(), headers)
+	} else {
+		data, err = os.ReadFile(filepath.FromSlash(location.Path))
+		ext = strings.ToLower(path.Ext(location.Path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external reference %s: %w", location, err)
+	}
+
+	var doc interface{}
+	if ext == ".json" {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse external reference %s: %w", location, err)
+	}
+
+	doc = stringKeys(doc)
+	b.docs[location.String()] = doc
+	return doc, nil
+}
+
+// componentName picks a free name for a bundled component: the last token of
+// the pointer, or the file name when the reference points at a whole file.
+func (b *refBundler) componentName(kind, pointer, filePath string) string {
+	base := unescapePointerToken(path.Base("/" + strings.TrimSuffix(pointer, "/")))
+	if pointer == "" || base == "/" {
+		base = strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+	}
+
+	taken := func(name string) bool {
+		local := componentsRefPrefix + kind + "/" + name
+		if hasComponent(b.spec.Components, local) {
+			return true
+		}
+		for _, bundled := range b.bundled {
+			if bundled == local {
+				return true
+			}
+		}
+		return false
+	}
+
+	name := base
+	for n := 2; taken(name); n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	return name
+}
+
+// addComponent decodes data as a component of the given kind, passes its
+// references to visit and stores it under name.
+func (b *refBundler) addComponent(kind, name string, data []byte, visit refVisitor) error {
+	if b.spec.Components == nil {
+		b.spec.Components = &openapi3.Components{}
+	}
+	c := b.spec.Components
+
+	switch kind {
+	case "schemas":
+		var v openapi3.SchemaRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitSchemaRefs(&v, visit)
+		if c.Schemas == nil {
+			c.Schemas = make(openapi3.Schemas)
+		}
+		c.Schemas[name] = &v
+	case "parameters":
+		var v openapi3.ParameterRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitParameterRefs(&v, visit)
+		if c.Parameters == nil {
+			c.Parameters = make(openapi3.ParametersMap)
+		}
+		c.Parameters[name] = &v
+	case "responses":
+		var v openapi3.ResponseRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitResponseRefs(&v, visit)
+		if c.Responses == nil {
+			c.Responses = make(openapi3.ResponseBodies)
+		}
+		c.Responses[name] = &v
+	case "requestBodies":
+		var v openapi3.RequestBodyRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitRequestBodyRefs(&v, visit)
+		if c.RequestBodies == nil {
+			c.RequestBodies = make(openapi3.RequestBodies)
+		}
+		c.RequestBodies[name] = &v
+	case "headers":
+		var v openapi3.HeaderRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitHeaderRefs(&v, visit)
+		if c.Headers == nil {
+			c.Headers = make(openapi3.Headers)
+		}
+		c.Headers[name] = &v
+	case "callbacks":
+		var v openapi3.CallbackRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		visitCallbackRefs(&v, visit)
+		if c.Callbacks == nil {
+			c.Callbacks = make(openapi3.Callbacks)
+		}
+		c.Callbacks[name] = &v
+	case "examples":
+		var v openapi3.ExampleRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Ref != "" {
+			visit(kind, &v.Ref)
+		}
+		if c.Examples == nil {
+			c.Examples = make(openapi3.Examples)
+		}
+		c.Examples[name] = &v
+	case "links":
+		var v openapi3.LinkRef
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Ref != "" {
+			visit(kind, &v.Ref)
+		}
+		if c.Links == nil {
+			c.Links = make(openapi3.Links)
+		}
+		c.Links[name] = &v
+	default:
+		return fmt.Errorf("cannot bundle %s", kind)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_BundleExternalRefs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `
+openapi: 3.0.0
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      parameters:
+        - $ref: "./shared/common.yaml#/components/parameters/PageSize"
+      responses:
+        "200":
+          description: Orders
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "./shared/common.yaml#/components/schemas/Order"
+        default:
+          $ref: "./shared/common.yaml#/components/responses/Error"
+    post:
+      requestBody:
+        $ref: "./shared/common.yaml#/components/requestBodies/NewOrder"
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    Money:
+      type: integer
+`
+
+	common := `
+components:
+  parameters:
+    PageSize:
+      name: pageSize
+      in: query
+      schema:
+        type: integer
+  requestBodies:
+    NewOrder:
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/Order"
+  responses:
+    Error:
+      description: Error
+      content:
+        application/json:
+          schema:
+            type: object
+            properties:
+              message:
+                type: string
+  schemas:
+    Money:
+      type: object
+      properties:
+        amount:
+          type: string
+        currency:
+          type: string
+    Order:
+      type: object
+      properties:
+        total:
+          $ref: "#/components/schemas/Money"
+        parent:
+          $ref: "#/components/schemas/Order"
+        category:
+          $ref: "./category.yaml"
+`
+
+	// Points back into common.yaml, which points here: a cycle across files
+	category := `
+type: object
+properties:
+  name:
+    type: string
+  featured:
+    type: array
+    items:
+      $ref: "./common.yaml#/components/schemas/Order"
+`
+
+	specPath := filepath.Join(tempDir, "orders.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "shared", "common.yaml"), []byte(common), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "shared", "category.yaml"), []byte(category), 0644))
+
+	cfg := &config.Config{
+		Inputs:             []config.InputConfig{{InputFile: specPath}},
+		Output:             filepath.Join(tempDir, "merged.json"),
+		BundleExternalRefs: true,
+	}
+	require.NoError(t, cfg.Validate())
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Empty(t, m.Warnings())
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), ".yaml")
+
+	components := m.master.Components
+	assert.Equal(t, []string{"Money", "Money2", "Order", "category"}, sortedKeys(components.Schemas))
+	assert.Contains(t, components.Parameters, "PageSize")
+	assert.Contains(t, components.Responses, "Error")
+	assert.Contains(t, components.RequestBodies, "NewOrder")
+
+	// The input's own Money is kept; the external one is renamed
+	order := components.Schemas["Order"].Value
+	assert.Equal(t, "#/components/schemas/Money2", order.Properties["total"].Ref)
+	assert.Equal(t, "#/components/schemas/Order", order.Properties["parent"].Ref)
+	assert.Equal(t, "#/components/schemas/category", order.Properties["category"].Ref)
+	assert.Equal(t, "#/components/schemas/Order", components.Schemas["category"].Value.Properties["featured"].Value.Items.Ref)
+
+	get := m.master.Paths.Value("/orders").Get
+	assert.Equal(t, "#/components/parameters/PageSize", get.Parameters[0].Ref)
+	assert.Equal(t, "#/components/responses/Error", get.Responses.Default().Ref)
+	assert.Equal(t, "#/components/schemas/Order", get.Responses.Status(200).Value.Content["application/json"].Schema.Value.Items.Ref)
+	assert.Equal(t, "#/components/requestBodies/NewOrder", m.master.Paths.Value("/orders").Post.RequestBody.Ref)
+}
+
+func TestConfig_ValidateBundleExternalRefs(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:             []config.InputConfig{{InputFile: "api.yaml"}},
+		Output:             "merged.yaml",
+		KeepExternalRefs:   true,
+		BundleExternalRefs: true,
+	}
+	assert.ErrorContains(t, cfg.Validate(), "cannot both be set")
+}
@@ -0,0 +1,30 @@
+package merger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// checksumExt is appended to the output path for the checksum sidecar.
+const checksumExt = ".sha256"
+
+// writeChecksum writes a SHA-256 sidecar for data next to path, in the format
+// understood by `sha256sum -c`.
+func (m *Merger) writeChecksum(path string, data []byte) error {
+	sum := sha256.Sum256(data)
+	line := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), filepath.Base(path))
+
+	checksumPath := path + checksumExt
+	if err := os.WriteFile(checksumPath, []byte(line), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum file: %w", err)
+	}
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Wrote checksum to %s\n", checksumPath)
+	}
+
+	return nil
+}
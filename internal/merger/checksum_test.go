@@ -0,0 +1,43 @@
+package merger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Checksum(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:   []config.InputConfig{{InputFile: specPath}},
+		Output:   filepath.Join(tempDir, "merged.yaml"),
+		Checksum: true,
+	}
+	require.NoError(t, New(cfg, false).Merge())
+
+	output, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	checksum, err := os.ReadFile(cfg.Output + ".sha256")
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(output)
+	assert.Equal(t, hex.EncodeToString(sum[:])+"  merged.yaml\n", string(checksum))
+}
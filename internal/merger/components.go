@@ -0,0 +1,169 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// filterComponents drops the components of an input whose names do not pass
+// its include/exclude globs. Security schemes are never filtered. Schemas
+// referenced by a kept operation or component are kept too, transitively,
+// and excluding one of them fails; keepReferenced does the same for the other
+// component types. References left dangling by the removal of those are
+// reported as warnings, or as an error in strict mode.
+func (m *Merger) filterComponents(spec *openapi3.T, input *config.InputConfig) (*openapi3.T, error) {
+	sel := input.Components
+	if sel == nil || spec.Components == nil || (len(sel.Include) == 0 && len(sel.Exclude) == 0) {
+		return spec, nil
+	}
+
+	kept, err := keptComponents(spec, sel)
+	if err != nil {
+		return nil, err
+	}
+	drop := func(kind, name string) bool { return !kept[kind+"/"+name] }
+
+	c := spec.Components
+	before := selectableCount(c)
+	maps.DeleteFunc(c.Schemas, func(name string, _ *openapi3.SchemaRef) bool { return drop("schemas", name) })
+	maps.DeleteFunc(c.Parameters, func(name string, _ *openapi3.ParameterRef) bool { return drop("parameters", name) })
+	maps.DeleteFunc(c.Headers, func(name string, _ *openapi3.HeaderRef) bool { return drop("headers", name) })
+	maps.DeleteFunc(c.RequestBodies, func(name string, _ *openapi3.RequestBodyRef) bool { return drop("requestBodies", name) })
+	maps.DeleteFunc(c.Responses, func(name string, _ *openapi3.ResponseRef) bool { return drop("responses", name) })
+	maps.DeleteFunc(c.Examples, func(name string, _ *openapi3.ExampleRef) bool { return drop("examples", name) })
+	maps.DeleteFunc(c.Links, func(name string, _ *openapi3.LinkRef) bool { return drop("links", name) })
+	maps.DeleteFunc(c.Callbacks, func(name string, _ *openapi3.CallbackRef) bool { return drop("callbacks", name) })
+	if removed := before - selectableCount(c); m.verbose && removed > 0 {
+		fmt.Fprintf(m.log, "  Filtered out %d components\n", removed)
+	}
+
+	dangling, err := danglingRefs(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(dangling) == 0 {
+		return spec, nil
+	}
+	if m.cfg.Strict {
+		return nil, fmt.Errorf("component selection leaves dangling references: %s", strings.Join(dangling, ", "))
+	}
+	for _, ref := range dangling {
+		m.warnf(input.InputFile, "reference %s points to a component excluded by component selection", ref)
+	}
+	return spec, nil
+}
+
+// selectableKinds are the component types that component selection filters.
+var selectableKinds = []string{
+	"schemas", "parameters", "headers", "requestBodies", "responses", "examples", "links", "callbacks",
+}
+
+// selectableCount returns the number of components of the selectable kinds.
+func selectableCount(c *openapi3.Components) int {
+	return len(c.Schemas) + len(c.Parameters) + len(c.Headers) + len(c.RequestBodies) +
+		len(c.Responses) + len(c.Examples) + len(c.Links) + len(c.Callbacks)
+}
+
+// keptComponents returns the components of spec that component selection
+// keeps, keyed by "<kind>/<name>": those passing the include/exclude globs
+// and the schemas (or, with keepReferenced, components of any type)
+// referenced by a kept component or by anything outside the filtered
+// component types, transitively. Excluding such a referenced component is an
+// error naming what references it.
+func keptComponents(spec *openapi3.T, sel *config.ComponentSelectionConfig) (map[string]bool, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	filtered := make(map[string]map[string]interface{})
+	for _, kind := range selectableKinds {
+		filtered[kind], _ = components[kind].(map[string]interface{})
+		delete(components, kind)
+	}
+
+	// referrers records who first referenced each kept component; selected
+	// components have an empty referrer
+	referrers := make(map[string]string)
+	var queue []string
+	keep := func(key, referrer string) {
+		if referrer != "" && !sel.KeepReferenced && !strings.HasPrefix(key, "schemas/") {
+			return
+		}
+		if _, ok := referrers[key]; !ok {
+			referrers[key] = referrer
+			queue = append(queue, key)
+		}
+	}
+
+	var walk func(v interface{}, referrer string)
+	walk = func(v interface{}, referrer string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix) {
+				kind, name, _ := strings.Cut(strings.TrimPrefix(ref, componentsRefPrefix), "/")
+				name, _, _ = strings.Cut(name, "/")
+				name = unescapePointerToken(name)
+				if _, ok := filtered[kind][name]; ok {
+					keep(kind+"/"+name, referrer)
+				}
+			}
+			for _, child := range v {
+				walk(child, referrer)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child, referrer)
+			}
+		}
+	}
+
+	excluded := func(name string) bool { return matchAnyGlob(sel.Exclude, name) }
+	for _, kind := range selectableKinds {
+		for _, name := range sortedKeys(filtered[kind]) {
+			if !excluded(name) && (len(sel.Include) == 0 || matchAnyGlob(sel.Include, name)) {
+				keep(kind+"/"+name, "")
+			}
+		}
+	}
+	walk(doc, "an operation or component")
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		kind, name, _ := strings.Cut(key, "/")
+		walk(filtered[kind][name], componentLabels[kind]+" "+name)
+	}
+
+	var referenced []string
+	kept := make(map[string]bool, len(referrers))
+	for _, key := range sortedKeys(referrers) {
+		kind, name, _ := strings.Cut(key, "/")
+		if excluded(name) {
+			referenced = append(referenced, fmt.Sprintf("%s %s (referenced by %s)", componentLabels[kind], name, referrers[key]))
+		}
+		kept[key] = true
+	}
+	if len(referenced) > 0 {
+		return nil, fmt.Errorf("component selection excludes components that are still referenced: %s", strings.Join(referenced, ", "))
+	}
+	return kept, nil
+}
+
+// matchAnyGlob reports whether name matches any of the glob patterns.
+func matchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
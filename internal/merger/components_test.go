@@ -0,0 +1,196 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_ComponentSelection(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Library", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/PageSize"}],
+					"responses": {
+						"200": {
+							"description": "Success",
+							"content": {
+								"application/json": {"schema": {"$ref": "#/components/schemas/User"}}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"address": {"$ref": "#/components/schemas/UserAddress"}}},
+				"UserAddress": {"type": "object"},
+				"Invoice": {"type": "object"},
+				"InternalAudit": {"type": "object"}
+			},
+			"parameters": {
+				"PageSize": {"name": "pageSize", "in": "query", "schema": {"type": "integer"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "library.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, sel *config.ComponentSelectionConfig, strict bool) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath, Components: sel}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			Strict: strict,
+		}
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("include", func(t *testing.T) {
+		m, err := run(t, &config.ComponentSelectionConfig{Include: []string{"User*", "PageSize"}}, false)
+		require.NoError(t, err)
+
+		schemas := m.master.Components.Schemas
+		assert.Contains(t, schemas, "User")
+		assert.Contains(t, schemas, "UserAddress")
+		assert.NotContains(t, schemas, "Invoice")
+		assert.NotContains(t, schemas, "InternalAudit")
+		assert.Contains(t, m.master.Components.Parameters, "PageSize")
+		assert.Empty(t, m.Warnings())
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		m, err := run(t, &config.ComponentSelectionConfig{Exclude: []string{"Internal*", "Invoice"}}, false)
+		require.NoError(t, err)
+
+		schemas := m.master.Components.Schemas
+		assert.Contains(t, schemas, "User")
+		assert.Contains(t, schemas, "UserAddress")
+		assert.NotContains(t, schemas, "Invoice")
+		assert.NotContains(t, schemas, "InternalAudit")
+		assert.Empty(t, m.Warnings())
+	})
+
+	t.Run("excluding a referenced schema fails", func(t *testing.T) {
+		_, err := run(t, &config.ComponentSelectionConfig{Exclude: []string{"UserAddress"}}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "excludes components that are still referenced: schema UserAddress (referenced by schema User)")
+	})
+
+	t.Run("dangling reference warns", func(t *testing.T) {
+		m, err := run(t, &config.ComponentSelectionConfig{Exclude: []string{"PageSize"}}, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Warning{{
+			Source:  specPath,
+			Message: "reference #/components/parameters/PageSize points to a component excluded by component selection",
+		}}, m.Warnings())
+	})
+
+	t.Run("dangling reference fails in strict mode", func(t *testing.T) {
+		// UserAddress is kept as User references it; PageSize is not followed
+		_, err := run(t, &config.ComponentSelectionConfig{Include: []string{"User"}}, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "leaves dangling references: #/components/parameters/PageSize")
+		assert.NotContains(t, err.Error(), "UserAddress")
+	})
+}
+
+func TestMerger_ComponentSelectionKeepReferenced(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Library", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/PageSize"}],
+					"responses": {
+						"200": {
+							"description": "Success",
+							"content": {
+								"application/json": {"schema": {"$ref": "#/components/schemas/UserList"}}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"UserList": {"type": "array", "items": {"$ref": "#/components/schemas/User"}},
+				"User": {"type": "object", "properties": {"address": {"$ref": "#/components/schemas/Address"}}},
+				"Address": {"type": "object"},
+				"Money": {"type": "object", "properties": {"currency": {"$ref": "#/components/schemas/Currency"}}},
+				"Currency": {"type": "string"},
+				"Invoice": {"type": "object", "properties": {"total": {"$ref": "#/components/schemas/Money"}}},
+				"InvoiceLine": {"type": "object"}
+			},
+			"parameters": {
+				"PageSize": {"name": "pageSize", "in": "query", "schema": {"type": "integer"}},
+				"Cursor": {"name": "cursor", "in": "query", "schema": {"type": "string"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "library.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	merge := func(sel config.ComponentSelectionConfig) (*Merger, error) {
+		sel.KeepReferenced = true
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath, Components: &sel}},
+			Output: filepath.Join(tempDir, "merged.json"),
+		}
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("include keeps referenced schemas", func(t *testing.T) {
+		m, err := merge(config.ComponentSelectionConfig{Include: []string{"Money"}})
+		require.NoError(t, err)
+		// Money pulls in Currency; the operation pulls in UserList, User and Address
+		assert.Equal(t, []string{"Address", "Currency", "Money", "User", "UserList"},
+			sortedKeys(m.master.Components.Schemas))
+		assert.Equal(t, []string{"PageSize"}, sortedKeys(m.master.Components.Parameters))
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		m, err := merge(config.ComponentSelectionConfig{Exclude: []string{"Invoice*"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Address", "Currency", "Money", "User", "UserList"},
+			sortedKeys(m.master.Components.Schemas))
+	})
+
+	t.Run("excluding a referenced schema fails", func(t *testing.T) {
+		_, err := merge(config.ComponentSelectionConfig{Exclude: []string{"Address", "Currency"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schema Address (referenced by schema User)")
+		assert.Contains(t, err.Error(), "schema Currency (referenced by schema Money)")
+	})
+
+	t.Run("excluded schemas only used by dropped schemas", func(t *testing.T) {
+		m, err := merge(config.ComponentSelectionConfig{
+			Include: []string{"User*"},
+			Exclude: []string{"Currency"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Address", "User", "UserList"}, sortedKeys(m.master.Components.Schemas))
+	})
+}
@@ -0,0 +1,90 @@
+package merger
+
+import (
+	"fmt"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// componentLabels names each component type in collision errors.
+var componentLabels = map[string]string{
+	"schemas":         "schema",
+	"responses":       "response",
+	"parameters":      "parameter",
+	"securitySchemes": "security scheme",
+	"requestBodies":   "request body",
+	"examples":        "example",
+	"headers":         "header",
+	"links":           "link",
+	"callbacks":       "callback",
+	"pathItems":       "path item",
+}
+
+// disputedKinds are the component kinds that applyDispute renames.
+var disputedKinds = map[string]bool{
+	"schemas":         true,
+	"responses":       true,
+	"parameters":      true,
+	"securitySchemes": true,
+	"requestBodies":   true,
+	"pathItems":       true,
+}
+
+// mergeComponentMap merges the src components of one type into dest. Same-named
+// components that differ are resolved by the configured conflict policy for
+// kind. An input with a dispute has already renamed its components, so for
+// the renamed kinds a differing component under the same name is an error.
+// The prefix policy stores the incoming component under a new name; the
+// returned map holds the references to rewrite in the input, old to new.
+func mergeComponentMap[V any](m *Merger, kind string, dest, src map[string]V, equal func(a, b V) bool, input *config.InputConfig) (map[string]string, error) {
+	policy := m.cfg.ConflictPolicy.For(kind)
+	renames := make(map[string]string)
+
+	for _, name := range sortedKeys(src) {
+		component := src[name]
+		existing, ok := dest[name]
+		if !ok {
+			dest[name] = component
+			m.componentSources[kind+"/"+name] = input.InputFile
+			continue
+		}
+		if equal(existing, component) {
+			continue
+		}
+
+		// A disputed name is already renamed, so a collision means two
+		// inputs ended up with the same name, e.g. through a shared prefix.
+		// Kinds the dispute leaves alone keep the earlier component.
+		if input.Dispute.Active() {
+			if !disputedKinds[kind] || m.recordConflict(kind, name, input) {
+				continue
+			}
+			return nil, fmt.Errorf("%s collision for '%s' between %s and %s after dispute renaming",
+				componentLabels[kind], name, m.componentSources[kind+"/"+name], input.InputFile)
+		}
+
+		switch policy {
+		case config.ConflictPolicyFirst:
+		case config.ConflictPolicyLast:
+			dest[name] = component
+			m.componentSources[kind+"/"+name] = input.InputFile
+		case config.ConflictPolicyPrefix:
+			newName := suggestedPrefix(input) + name
+			if _, taken := dest[newName]; taken {
+				return nil, fmt.Errorf("%s collision for '%s': prefixed name '%s' is also taken", componentLabels[kind], name, newName)
+			}
+			dest[newName] = component
+			m.recordRename(kind, component, name, newName, input.InputFile)
+			m.componentSources[kind+"/"+newName] = input.InputFile
+			renames[componentsRefPrefix+kind+"/"+name] = componentsRefPrefix + kind + "/" + newName
+			m.warnf(input.InputFile, "%s '%s' conflicts with an earlier input; merged as '%s'", componentLabels[kind], name, newName)
+		default:
+			if m.recordConflict(kind, name, input) {
+				continue
+			}
+			return nil, fmt.Errorf("%s collision for '%s' without dispute prefix", componentLabels[kind], name)
+		}
+	}
+
+	return renames, nil
+}
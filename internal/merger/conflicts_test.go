@@ -0,0 +1,212 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConflictSpecs writes two inputs whose Limit parameter, Error schema
+// and NotFound response differ.
+func writeConflictSpecs(t *testing.T) (string, string, string) {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := func(title, path, limitDesc, errorField, notFoundDesc string) string {
+		return `{
+			"openapi": "3.0.0",
+			"info": {"title": "` + title + `", "version": "1.0.0"},
+			"paths": {
+				"` + path + `": {
+					"get": {
+						"parameters": [{"$ref": "#/components/parameters/Limit"}],
+						"responses": {
+							"200": {
+								"description": "OK",
+								"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+							},
+							"404": {"$ref": "#/components/responses/NotFound"}
+						}
+					}
+				}
+			},
+			"components": {
+				"parameters": {
+					"Limit": {"name": "limit", "in": "query", "description": "` + limitDesc + `", "schema": {"type": "integer"}}
+				},
+				"schemas": {
+					"Error": {"type": "object", "properties": {"` + errorField + `": {"type": "string"}}}
+				},
+				"responses": {
+					"NotFound": {"description": "` + notFoundDesc + `"}
+				}
+			}
+		}`
+	}
+
+	input1 := filepath.Join(tempDir, "users.json")
+	input2 := filepath.Join(tempDir, "orders-api.json")
+	require.NoError(t, os.WriteFile(input1, []byte(spec("Users", "/users", "Page size", "message", "No such user")), 0644))
+	require.NoError(t, os.WriteFile(input2, []byte(spec("Orders", "/orders", "Max results", "detail", "No such order")), 0644))
+	return tempDir, input1, input2
+}
+
+func TestMerger_ConflictPolicyErrorForSchemas(t *testing.T) {
+	tempDir, input1, input2 := writeConflictSpecs(t)
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output: filepath.Join(tempDir, "merged.json"),
+		ConflictPolicy: &config.ConflictPolicyConfig{
+			Schemas:    config.ConflictPolicyError,
+			Parameters: config.ConflictPolicyFirst,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	err := New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema collision for 'Error' without dispute prefix")
+}
+
+func TestMerger_SharedDisputePrefixCollision(t *testing.T) {
+	tempDir, input1, input2 := writeConflictSpecs(t)
+
+	merge := func(prefix1, prefix2, second string) error {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: input1, Dispute: &config.DisputeConfig{Prefix: prefix1}},
+				{InputFile: second, Dispute: &config.DisputeConfig{Prefix: prefix2}},
+			},
+			Output: filepath.Join(tempDir, "merged.json"),
+		}
+		require.NoError(t, cfg.Validate())
+		return New(cfg, false).Merge()
+	}
+
+	err := merge("API_", "API_", input2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema collision for 'API_Error' between "+input1+" and "+input2+" after dispute renaming")
+
+	// Distinct prefixes, or identical components, do not collide
+	assert.NoError(t, merge("Users_", "Orders_", input2))
+	assert.NoError(t, merge("API_", "API_", input1))
+}
+
+func TestMerger_ConflictPolicyLastForParameters(t *testing.T) {
+	tempDir, input1, input2 := writeConflictSpecs(t)
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output: filepath.Join(tempDir, "merged.json"),
+		ConflictPolicy: &config.ConflictPolicyConfig{
+			Schemas:    config.ConflictPolicyFirst,
+			Parameters: config.ConflictPolicyLast,
+		},
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	c := m.master.Components
+	assert.Equal(t, "Max results", c.Parameters["Limit"].Value.Description, "last parameter wins")
+	assert.Contains(t, c.Schemas["Error"].Value.Properties, "message", "first schema wins")
+	assert.Equal(t, "No such user", *c.Responses["NotFound"].Value.Description, "responses default to first")
+}
+
+func TestMerger_ConflictPolicyPrefix(t *testing.T) {
+	tempDir, input1, input2 := writeConflictSpecs(t)
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output: filepath.Join(tempDir, "merged.json"),
+		ConflictPolicy: &config.ConflictPolicyConfig{
+			Schemas:    config.ConflictPolicyPrefix,
+			Parameters: config.ConflictPolicyFirst,
+			Responses:  config.ConflictPolicyPrefix,
+		},
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	c := m.master.Components
+	assert.Contains(t, c.Schemas["Error"].Value.Properties, "message")
+	assert.Contains(t, c.Schemas["OrdersApi_Error"].Value.Properties, "detail")
+	assert.Equal(t, "No such order", *c.Responses["OrdersApi_NotFound"].Value.Description)
+
+	orders := m.master.Paths.Value("/orders").Get
+	assert.Equal(t, "#/components/schemas/OrdersApi_Error",
+		orders.Responses.Value("200").Value.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/responses/OrdersApi_NotFound", orders.Responses.Value("404").Ref)
+
+	users := m.master.Paths.Value("/users").Get
+	assert.Equal(t, "#/components/schemas/Error",
+		users.Responses.Value("200").Value.Content["application/json"].Schema.Ref)
+	assert.Len(t, m.Warnings(), 2)
+}
+
+func TestConfig_ValidateConflictPolicy(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:         []config.InputConfig{{InputFile: "api.json"}},
+		Output:         "merged.json",
+		ConflictPolicy: &config.ConflictPolicyConfig{Headers: "merge"},
+	}
+	assert.EqualError(t, cfg.Validate(), `invalid conflictPolicy.headers "merge" (expected error, first, last or prefix)`)
+}
+
+func TestMerger_ConflictPolicyPrefixSecuritySchemes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"security": [{"apiKey": []}],
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}},
+		"components": {"securitySchemes": {"apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"security": [{"apiKey": []}],
+		"paths": {"/orders": {"get": {
+			"security": [{"apiKey": []}],
+			"responses": {"200": {"description": "OK"}}
+		}}},
+		"components": {"securitySchemes": {"apiKey": {"type": "apiKey", "in": "query", "name": "key"}}}
+	}`
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	ordersPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(users), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(orders), 0644))
+
+	cfg := &config.Config{
+		Inputs:                []config.InputConfig{{InputFile: usersPath}, {InputFile: ordersPath}},
+		Output:                filepath.Join(tempDir, "merged.json"),
+		SecurityMergeStrategy: config.SecurityMergeStrategyMerge,
+		ConflictPolicy:        &config.ConflictPolicyConfig{SecuritySchemes: config.ConflictPolicyPrefix},
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemes := m.master.Components.SecuritySchemes
+	assert.Equal(t, "header", schemes["apiKey"].Value.In)
+	assert.Equal(t, "query", schemes["Orders_apiKey"].Value.In)
+
+	assert.Equal(t, openapi3.SecurityRequirements{{"apiKey": []string{}}, {"Orders_apiKey": []string{}}}, m.master.Security)
+	op := m.master.Paths.Value("/orders").Get
+	require.NotNil(t, op.Security)
+	assert.Equal(t, openapi3.SecurityRequirements{{"Orders_apiKey": []string{}}}, *op.Security)
+	assert.Nil(t, m.master.Paths.Value("/users").Get.Security)
+}
@@ -0,0 +1,87 @@
+package merger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// CoverageStats holds documentation coverage of a specification's operations.
+type CoverageStats struct {
+	Operations      int `json:"operations"`
+	Tagged          int `json:"tagged"`
+	WithSummary     int `json:"withSummary"`
+	WithDescription int `json:"withDescription"`
+}
+
+// percent returns n as a percentage of all operations (100 when there are none).
+func (c CoverageStats) percent(n int) float64 {
+	if c.Operations == 0 {
+		return 100
+	}
+	return float64(n) * 100 / float64(c.Operations)
+}
+
+// ComputeCoverage counts how many operations have tags, summaries and descriptions.
+func ComputeCoverage(spec *openapi3.T) CoverageStats {
+	var stats CoverageStats
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		stats.Operations++
+		if len(op.Tags) > 0 {
+			stats.Tagged++
+		}
+		if strings.TrimSpace(op.Summary) != "" {
+			stats.WithSummary++
+		}
+		if strings.TrimSpace(op.Description) != "" {
+			stats.WithDescription++
+		}
+	})
+	return stats
+}
+
+// checkCoverage compares the merged operations against the coverage
+// thresholds. Shortfalls are reported as warnings, or as an error in strict mode.
+func (m *Merger) checkCoverage() error {
+	cov := m.cfg.Coverage
+	if cov == nil {
+		return nil
+	}
+
+	stats := ComputeCoverage(m.master)
+	if m.verbose {
+		fmt.Fprintf(m.log, "Coverage: %d operations, %.1f%% tagged, %.1f%% with summary, %.1f%% with description\n",
+			stats.Operations, stats.percent(stats.Tagged), stats.percent(stats.WithSummary), stats.percent(stats.WithDescription))
+	}
+
+	var problems []string
+	report := func(source, msg string) {
+		if m.cfg.Strict {
+			problems = append(problems, msg)
+		} else {
+			m.warnf(source, "%s", msg)
+		}
+	}
+
+	if cov.RequireTags {
+		forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+			if len(op.Tags) == 0 {
+				report(m.sources[op], fmt.Sprintf("operation %s %s has no tags", method, path))
+			}
+		})
+	}
+	if pct := stats.percent(stats.WithSummary); pct < cov.MinSummaryPercent {
+		report("", fmt.Sprintf("%.1f%% of operations have a summary (%d of %d), below the minimum of %g%%",
+			pct, stats.WithSummary, stats.Operations, cov.MinSummaryPercent))
+	}
+	if pct := stats.percent(stats.WithDescription); pct < cov.MinDescriptionPercent {
+		report("", fmt.Sprintf("%.1f%% of operations have a description (%d of %d), below the minimum of %g%%",
+			pct, stats.WithDescription, stats.Operations, cov.MinDescriptionPercent))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("documentation coverage check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
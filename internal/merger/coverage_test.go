@@ -0,0 +1,79 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Coverage(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"tags": [{"name": "Users"}],
+		"paths": {
+			"/users": {
+				"get": {"tags": ["Users"], "summary": "List users", "description": "Pages through all users.", "responses": {"200": {"description": "OK"}}},
+				"post": {"tags": ["Users"], "summary": "Create a user", "responses": {"201": {"description": "Created"}}}
+			},
+			"/users/export": {
+				"get": {"tags": ["Users"], "summary": " ", "responses": {"200": {"description": "OK"}}}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, strict bool) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			Strict: strict,
+			Coverage: &config.CoverageConfig{
+				RequireTags:           true,
+				MinSummaryPercent:     75,
+				MinDescriptionPercent: 20,
+			},
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("stats", func(t *testing.T) {
+		m, err := run(t, false)
+		require.NoError(t, err)
+
+		stats := ComputeCoverage(m.master)
+		assert.Equal(t, CoverageStats{Operations: 4, Tagged: 3, WithSummary: 2, WithDescription: 1}, stats)
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		m, err := run(t, false)
+		require.NoError(t, err)
+
+		require.Len(t, m.Warnings(), 2)
+		assert.Equal(t, specPath, m.Warnings()[0].Source)
+		assert.Equal(t, "operation GET /health has no tags", m.Warnings()[0].Message)
+		assert.Equal(t, "50.0% of operations have a summary (2 of 4), below the minimum of 75%", m.Warnings()[1].Message)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := run(t, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "documentation coverage check failed")
+		assert.Contains(t, err.Error(), "GET /health has no tags")
+	})
+}
@@ -0,0 +1,157 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// deduplicateSchemas removes component schemas that are structurally equal
+// to another one and points their references at the one kept, the schema
+// with the shortest name (then the first alphabetically). Rename annotations
+// are ignored when comparing. Schemas named by a discriminator mapping are
+// never removed, and two members of the same oneOf or anyOf are never merged,
+// since equal shapes there still mean different things. It repeats until
+// nothing changes, since rewriting references can make schemas that used the
+// duplicates equal too.
+func (m *Merger) deduplicateSchemas() {
+	if m.master.Components == nil {
+		return
+	}
+	schemas := m.master.Components.Schemas
+
+	removed := 0
+	for {
+		mapped, siblings := dedupeExclusions(m.master)
+		kept := make(map[string]string)
+		renames := make(map[string]string)
+		for _, name := range dedupeOrder(schemas) {
+			key, ok := schemaKey(schemas[name])
+			if !ok {
+				continue
+			}
+			if canonical, ok := kept[key]; ok {
+				if mapped[name] || siblings[name][canonical] {
+					continue
+				}
+				renames[componentsRefPrefix+"schemas/"+name] = componentsRefPrefix + "schemas/" + canonical
+				delete(schemas, name)
+				if m.verbose {
+					fmt.Fprintf(m.log, "  Schema %s duplicates %s\n", name, canonical)
+				}
+				continue
+			}
+			kept[key] = name
+		}
+		if len(renames) == 0 {
+			break
+		}
+		updateRefs(m.master, renames)
+		removed += len(renames)
+	}
+
+	if m.verbose && removed > 0 {
+		fmt.Fprintf(m.log, "Deduplicated %d schemas\n", removed)
+	}
+}
+
+// dedupeExclusions returns the schemas named by a discriminator mapping, and
+// for each schema the other schemas it shares a oneOf or anyOf with.
+func dedupeExclusions(spec *openapi3.T) (map[string]bool, map[string]map[string]bool) {
+	mapped := make(map[string]bool)
+	siblings := make(map[string]map[string]bool)
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return mapped, siblings
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return mapped, siblings
+	}
+
+	schemaName := func(ref string) string {
+		return unescapePointerToken(strings.TrimPrefix(ref, componentsRefPrefix+"schemas/"))
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if discriminator, ok := v["discriminator"].(map[string]interface{}); ok {
+				mapping, _ := discriminator["mapping"].(map[string]interface{})
+				for _, target := range mapping {
+					// Mapping values are references or bare schema names
+					if target, ok := target.(string); ok {
+						mapped[schemaName(target)] = true
+					}
+				}
+			}
+			for _, key := range []string{"oneOf", "anyOf"} {
+				members, _ := v[key].([]interface{})
+				var names []string
+				for _, member := range members {
+					if member, ok := member.(map[string]interface{}); ok {
+						if ref, ok := member["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix+"schemas/") {
+							names = append(names, schemaName(ref))
+						}
+					}
+				}
+				for _, a := range names {
+					for _, b := range names {
+						if a == b {
+							continue
+						}
+						if siblings[a] == nil {
+							siblings[a] = make(map[string]bool)
+						}
+						siblings[a][b] = true
+					}
+				}
+			}
+			for key, child := range v {
+				if !literalKeys[key] {
+					walk(child)
+				}
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return mapped, siblings
+}
+
+// dedupeOrder returns the schema names shortest first, then alphabetically,
+// so the first of a group of equal schemas is the one kept.
+func dedupeOrder(schemas openapi3.Schemas) []string {
+	names := sortedKeys(schemas)
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) < len(names[j]) })
+	return names
+}
+
+// schemaKey returns the canonical JSON of a schema without its rename
+// annotations; equal schemas have equal keys.
+func schemaKey(schema *openapi3.SchemaRef) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+	value, ok := canonicalSchema(schema)
+	if !ok {
+		return "", false
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		delete(object, originalNameExtension)
+		delete(object, sourceExtension)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,148 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_DeduplicateSchemas(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	// Every service defines the same Pagination and a Page wrapping it;
+	// billing's Page has an extra property
+	spec := func(path, pageExtra string) string {
+		return fmt.Sprintf(`{
+			"openapi": "3.0.0",
+			"info": {"title": "API", "version": "1.0.0"},
+			"paths": {
+				%q: {"get": {"responses": {"200": {
+					"description": "OK",
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Page"}}}
+				}}}}
+			},
+			"components": {
+				"schemas": {
+					"Pagination": {
+						"type": "object",
+						"required": ["limit", "offset"],
+						"properties": {"limit": {"type": "integer"}, "offset": {"type": "integer"}}
+					},
+					"Page": {
+						"type": "object",
+						"properties": {"pagination": {"$ref": "#/components/schemas/Pagination"}%s}
+					}
+				}
+			}
+		}`, path, pageExtra)
+	}
+
+	var inputs []config.InputConfig
+	for i, s := range []struct{ path, prefix, extra string }{
+		{"/users", "Users_", ""},
+		{"/orders", "Orders_", ""},
+		{"/invoices", "Billing_", `, "currency": {"type": "string"}`},
+	} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec(s.path, s.extra)), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path, Dispute: &config.DisputeConfig{Prefix: s.prefix}})
+	}
+
+	cfg := &config.Config{
+		Inputs:             inputs,
+		Output:             filepath.Join(tempDir, "merged.json"),
+		DeduplicateSchemas: true,
+		AnnotateRenames:    true,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := m.master.Components.Schemas
+	assert.ElementsMatch(t, []string{"Users_Page", "Billing_Page", "Users_Pagination"}, sortedKeys(schemas))
+
+	pageRef := func(path string) string {
+		return m.master.Paths.Value(path).Get.Responses.Status(200).Value.Content["application/json"].Schema.Ref
+	}
+	assert.Equal(t, "#/components/schemas/Users_Page", pageRef("/users"))
+	assert.Equal(t, "#/components/schemas/Users_Page", pageRef("/orders"), "pages become equal once their pagination refs are rewritten")
+	assert.Equal(t, "#/components/schemas/Billing_Page", pageRef("/invoices"))
+	assert.Equal(t, "#/components/schemas/Users_Pagination", schemas["Billing_Page"].Value.Properties["pagination"].Ref)
+
+	// Off by default
+	cfg.DeduplicateSchemas = false
+	m = New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Len(t, m.master.Components.Schemas, 6)
+}
+
+func TestMerger_DeduplicateSchemasKeepsDiscriminatorTargets(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	// Cat and Dog have the same shape but are told apart by the discriminator
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Pets", "version": "1.0.0"},
+		"paths": {
+			"/pets": {"get": {"responses": {"200": {
+				"description": "OK",
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+			}}}},
+			"/kittens": {"get": {"responses": {"200": {
+				"description": "OK",
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Kitten"}}}
+			}}}},
+			"/search": {"get": {"responses": {"200": {
+				"description": "OK",
+				"content": {"application/json": {"schema": {"anyOf": [
+					{"$ref": "#/components/schemas/Owner"},
+					{"$ref": "#/components/schemas/Vet"}
+				]}}}
+			}}}}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {
+					"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}],
+					"discriminator": {"propertyName": "kind", "mapping": {"cat": "#/components/schemas/Cat", "dog": "Dog"}}
+				},
+				"Cat": {"type": "object", "properties": {"kind": {"type": "string"}}},
+				"Dog": {"type": "object", "properties": {"kind": {"type": "string"}}},
+				"Kitten": {"type": "object", "properties": {"kind": {"type": "string"}}},
+				"Owner": {"type": "object", "properties": {"name": {"type": "string"}}},
+				"Vet": {"type": "object", "properties": {"name": {"type": "string"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "pets.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:             []config.InputConfig{{InputFile: specPath}},
+		Output:             filepath.Join(tempDir, "merged.json"),
+		DeduplicateSchemas: true,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := m.master.Components.Schemas
+	assert.ElementsMatch(t, []string{"Pet", "Cat", "Dog", "Owner", "Vet"}, sortedKeys(schemas))
+
+	pet := schemas["Pet"].Value
+	assert.Equal(t, "#/components/schemas/Cat", pet.OneOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/Dog", pet.OneOf[1].Ref)
+	assert.Equal(t, "Dog", pet.Discriminator.Mapping["dog"])
+
+	// A duplicate outside the discriminator is still merged
+	kitten := m.master.Paths.Value("/kittens").Get.Responses.Status(200).Value.Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/Cat", kitten.Ref)
+}
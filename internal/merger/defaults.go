@@ -0,0 +1,70 @@
+package merger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// validateDefaults checks that every schema default in spec conforms to its
+// schema. Mismatches are reported as warnings, or as an error in strict mode.
+func (m *Merger) validateDefaults(spec *openapi3.T, source string) error {
+	var problems []string
+	check := func(location string) func(*openapi3.Schema) {
+		return func(schema *openapi3.Schema) {
+			if schema.Default == nil {
+				return
+			}
+			if err := schema.VisitJSON(schema.Default); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: default %s does not match schema: %s",
+					location, formatDefault(schema.Default), schemaErrorReason(err)))
+			}
+		}
+	}
+
+	if spec.Components != nil {
+		names := make([]string, 0, len(spec.Components.Schemas))
+		for name := range spec.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walkSchemaRef(spec.Components.Schemas[name], check("components.schemas."+name))
+		}
+	}
+
+	for _, path := range sortedPaths(spec.Paths) {
+		walkPathItemSchemas(spec.Paths.Value(path), check("paths."+path))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if m.cfg.Strict {
+		return fmt.Errorf("invalid schema defaults: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		m.warnf(source, "%s", problem)
+	}
+	return nil
+}
+
+// formatDefault renders a default value for messages, quoting strings.
+func formatDefault(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(value)
+}
+
+// schemaErrorReason returns the short reason of a schema validation error.
+func schemaErrorReason(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) && schemaErr.Reason != "" {
+		return schemaErr.Reason
+	}
+	return err.Error()
+}
@@ -0,0 +1,71 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_ValidateDefaults(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [
+						{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 20}}
+					],
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Page": {
+					"type": "object",
+					"properties": {
+						"size": {"type": "integer", "default": "ten"}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:           []config.InputConfig{{InputFile: specPath}},
+		Output:           filepath.Join(tempDir, "merged.json"),
+		ValidateDefaults: true,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	var messages []string
+	for _, w := range m.Warnings() {
+		if w.Source == specPath {
+			messages = append(messages, w.Message)
+		}
+	}
+	assert.Contains(t, messages, `components.schemas.Page: default "ten" does not match schema: value must be an integer`)
+	for _, msg := range messages {
+		assert.NotContains(t, msg, "paths./users")
+	}
+
+	// Strict mode fails the merge
+	cfg.Strict = true
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid schema defaults")
+}
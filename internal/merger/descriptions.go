@@ -0,0 +1,133 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// descriptionEllipsis is appended to truncated descriptions.
+const descriptionEllipsis = "…"
+
+// truncateDescriptions shortens every description longer than MaxDescriptionLength.
+func (m *Merger) truncateDescriptions() {
+	limit := m.cfg.MaxDescriptionLength
+	if limit <= 0 {
+		return
+	}
+
+	truncate := func(s *string) {
+		*s = truncateText(*s, limit)
+	}
+
+	if m.master.Info != nil {
+		truncate(&m.master.Info.Description)
+	}
+
+	for _, tag := range m.master.Tags {
+		truncate(&tag.Description)
+	}
+
+	if m.master.Components != nil {
+		for _, param := range m.master.Components.Parameters {
+			truncateParameterDescriptions(param, truncate)
+		}
+		for _, header := range m.master.Components.Headers {
+			truncateHeaderDescriptions(header, truncate)
+		}
+		for _, body := range m.master.Components.RequestBodies {
+			truncateRequestBodyDescriptions(body, truncate)
+		}
+		for _, resp := range m.master.Components.Responses {
+			truncateResponseDescriptions(resp, truncate)
+		}
+	}
+
+	if m.master.Paths != nil {
+		for _, pathItem := range m.master.Paths.Map() {
+			truncatePathItemDescriptions(pathItem, truncate)
+		}
+	}
+
+	walkSpecSchemas(m.master, func(schema *openapi3.Schema) {
+		truncate(&schema.Description)
+	})
+}
+
+func truncatePathItemDescriptions(pathItem *openapi3.PathItem, truncate func(*string)) {
+	if pathItem == nil {
+		return
+	}
+
+	truncate(&pathItem.Description)
+	for _, param := range pathItem.Parameters {
+		truncateParameterDescriptions(param, truncate)
+	}
+
+	for _, op := range getOperationsMap(pathItem) {
+		if op == nil {
+			continue
+		}
+		truncate(&op.Description)
+		for _, param := range op.Parameters {
+			truncateParameterDescriptions(param, truncate)
+		}
+		truncateRequestBodyDescriptions(op.RequestBody, truncate)
+		if op.Responses != nil {
+			for _, resp := range op.Responses.Map() {
+				truncateResponseDescriptions(resp, truncate)
+			}
+		}
+		for _, callback := range op.Callbacks {
+			if callback == nil || callback.Ref != "" || callback.Value == nil {
+				continue
+			}
+			for _, item := range callback.Value.Map() {
+				truncatePathItemDescriptions(item, truncate)
+			}
+		}
+	}
+}
+
+func truncateParameterDescriptions(paramRef *openapi3.ParameterRef, truncate func(*string)) {
+	if paramRef == nil || paramRef.Ref != "" || paramRef.Value == nil {
+		return
+	}
+	truncate(&paramRef.Value.Description)
+}
+
+func truncateHeaderDescriptions(headerRef *openapi3.HeaderRef, truncate func(*string)) {
+	if headerRef == nil || headerRef.Ref != "" || headerRef.Value == nil {
+		return
+	}
+	truncate(&headerRef.Value.Description)
+}
+
+func truncateRequestBodyDescriptions(bodyRef *openapi3.RequestBodyRef, truncate func(*string)) {
+	if bodyRef == nil || bodyRef.Ref != "" || bodyRef.Value == nil {
+		return
+	}
+	truncate(&bodyRef.Value.Description)
+}
+
+func truncateResponseDescriptions(respRef *openapi3.ResponseRef, truncate func(*string)) {
+	if respRef == nil || respRef.Ref != "" || respRef.Value == nil {
+		return
+	}
+	if respRef.Value.Description != nil {
+		truncate(respRef.Value.Description)
+	}
+	for _, header := range respRef.Value.Headers {
+		truncateHeaderDescriptions(header, truncate)
+	}
+}
+
+// truncateText shortens s to at most limit characters, ending with an ellipsis.
+func truncateText(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	if limit <= 1 {
+		return descriptionEllipsis
+	}
+	return string(runes[:limit-1]) + descriptionEllipsis
+}
@@ -0,0 +1,81 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_MaxDescriptionLength(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	long := strings.Repeat("a", 50)
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"tags": [{"name": "Users", "description": "` + long + `"}],
+		"paths": {
+			"/users": {
+				"get": {
+					"description": "` + long + `",
+					"parameters": [
+						{"name": "q", "in": "query", "description": "` + long + `", "schema": {"type": "string"}}
+					],
+					"responses": {"200": {"description": "Short"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {
+					"type": "object",
+					"description": "` + long + `",
+					"properties": {
+						"name": {"type": "string", "description": "` + long + `"}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath}},
+		Output: outputPath,
+		Info: &config.InfoConfig{
+			Description: long,
+		},
+		MaxDescriptionLength: 10,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	want := strings.Repeat("a", 9) + "…"
+	op := m.master.Paths.Find("/users").Get
+	assert.Equal(t, want, m.master.Info.Description)
+	assert.Equal(t, want, m.master.Tags[0].Description)
+	assert.Equal(t, want, op.Description)
+	assert.Equal(t, want, op.Parameters[0].Value.Description)
+	assert.Equal(t, "Short", *op.Responses.Value("200").Value.Description)
+	assert.Equal(t, want, m.master.Components.Schemas["User"].Value.Description)
+	assert.Equal(t, want, m.master.Components.Schemas["User"].Value.Properties["name"].Value.Description)
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "short", truncateText("short", 10))
+	assert.Equal(t, "exactly10!", truncateText("exactly10!", 10))
+	assert.Equal(t, "héllo…", truncateText("héllo wörld", 6))
+}
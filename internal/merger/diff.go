@@ -0,0 +1,222 @@
+package merger
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kinds of SpecChange.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// SpecChange is a difference between two specifications.
+type SpecChange struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+	Detail   string `json:"detail,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// String describes the change, e.g. "removed path /users".
+func (c SpecChange) String() string {
+	s := c.Kind + " " + c.Location
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// DiffSpecs compares the paths, operations and component schemas of two
+// specifications. Removed paths, operations and responses, and parameters
+// that are new or newly required, are breaking changes.
+func DiffSpecs(base, revision *openapi3.T) []SpecChange {
+	var changes []SpecChange
+	add := func(kind, location, detail string, breaking bool) {
+		changes = append(changes, SpecChange{Kind: kind, Location: location, Detail: detail, Breaking: breaking})
+	}
+
+	basePaths, revisionPaths := pathItems(base), pathItems(revision)
+	for _, path := range unionKeys(basePaths, revisionPaths) {
+		before, after := basePaths[path], revisionPaths[path]
+		switch {
+		case after == nil:
+			add(ChangeRemoved, "path "+path, "", true)
+			continue
+		case before == nil:
+			add(ChangeAdded, "path "+path, "", false)
+			continue
+		}
+
+		for _, method := range httpMethods {
+			oldOp, newOp := before.GetOperation(method), after.GetOperation(method)
+			location := method + " " + path
+			switch {
+			case oldOp == nil && newOp == nil:
+			case newOp == nil:
+				add(ChangeRemoved, "operation "+location, "", true)
+			case oldOp == nil:
+				add(ChangeAdded, "operation "+location, "", false)
+			default:
+				found := len(changes)
+				diffOperation(location, before, oldOp, after, newOp, add)
+				if len(changes) == found && !jsonEqual(oldOp, newOp) {
+					add(ChangeChanged, "operation "+location, "", false)
+				}
+			}
+		}
+	}
+
+	baseSchemas, revisionSchemas := componentSchemas(base), componentSchemas(revision)
+	for _, name := range unionKeys(baseSchemas, revisionSchemas) {
+		before, after := baseSchemas[name], revisionSchemas[name]
+		switch {
+		case after == nil:
+			add(ChangeRemoved, "schema "+name, "", false)
+		case before == nil:
+			add(ChangeAdded, "schema "+name, "", false)
+		case !jsonEqual(before, after):
+			add(ChangeChanged, "schema "+name, "", false)
+		}
+	}
+
+	return changes
+}
+
+// diffOperation compares the parameters and responses of an operation that
+// exists in both specifications.
+func diffOperation(location string, oldItem *openapi3.PathItem, oldOp *openapi3.Operation, newItem *openapi3.PathItem, newOp *openapi3.Operation, add func(kind, location, detail string, breaking bool)) {
+	oldParams, newParams := effectiveParameters(oldItem, oldOp), effectiveParameters(newItem, newOp)
+	for _, key := range unionKeys(oldParams, newParams) {
+		before, after := oldParams[key], newParams[key]
+		paramLocation := location + " parameter " + key
+		switch {
+		case after == nil:
+			add(ChangeRemoved, paramLocation, "", false)
+		case before == nil && after.Required:
+			add(ChangeAdded, paramLocation, "required", true)
+		case before == nil:
+			add(ChangeAdded, paramLocation, "", false)
+		case after.Required && !before.Required:
+			add(ChangeChanged, paramLocation, "now required", true)
+		case !jsonEqual(before, after):
+			add(ChangeChanged, paramLocation, "", false)
+		}
+	}
+
+	oldBody, newBody := requestBody(oldOp), requestBody(newOp)
+	switch {
+	case newBody != nil && newBody.Required && (oldBody == nil || !oldBody.Required):
+		add(ChangeChanged, location+" request body", "now required", true)
+	case oldBody != nil && newBody == nil:
+		add(ChangeRemoved, location+" request body", "", false)
+	case oldBody == nil && newBody != nil:
+		add(ChangeAdded, location+" request body", "", false)
+	case !jsonEqual(oldBody, newBody):
+		add(ChangeChanged, location+" request body", "", false)
+	}
+
+	oldResponses, newResponses := responseMap(oldOp), responseMap(newOp)
+	for _, status := range unionKeys(oldResponses, newResponses) {
+		before, after := oldResponses[status], newResponses[status]
+		responseLocation := location + " response " + status
+		switch {
+		case after == nil:
+			add(ChangeRemoved, responseLocation, "", true)
+		case before == nil:
+			add(ChangeAdded, responseLocation, "", false)
+		case !jsonEqual(before, after):
+			add(ChangeChanged, responseLocation, "", false)
+		}
+	}
+}
+
+// effectiveParameters returns the parameters of an operation, including
+// those of its path item that it does not override, keyed by "name (in)".
+func effectiveParameters(pathItem *openapi3.PathItem, op *openapi3.Operation) map[string]*openapi3.Parameter {
+	params := make(map[string]*openapi3.Parameter)
+	for _, list := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+		for _, ref := range list {
+			if ref != nil && ref.Value != nil {
+				params[fmt.Sprintf("%s (%s)", ref.Value.Name, ref.Value.In)] = ref.Value
+			}
+		}
+	}
+	return params
+}
+
+// requestBody returns the resolved request body of an operation, if any.
+func requestBody(op *openapi3.Operation) *openapi3.RequestBody {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Value
+}
+
+// responseMap returns the resolved responses of an operation by status.
+func responseMap(op *openapi3.Operation) map[string]*openapi3.Response {
+	responses := make(map[string]*openapi3.Response)
+	if op.Responses == nil {
+		return responses
+	}
+	for status, ref := range op.Responses.Map() {
+		if ref != nil && ref.Value != nil {
+			responses[status] = ref.Value
+		}
+	}
+	return responses
+}
+
+// pathItems returns the path items of a specification by path.
+func pathItems(spec *openapi3.T) map[string]*openapi3.PathItem {
+	items := make(map[string]*openapi3.PathItem)
+	if spec.Paths == nil {
+		return items
+	}
+	for path, item := range spec.Paths.Map() {
+		if item != nil {
+			items[path] = item
+		}
+	}
+	return items
+}
+
+// componentSchemas returns the resolved component schemas by name.
+func componentSchemas(spec *openapi3.T) map[string]*openapi3.Schema {
+	schemas := make(map[string]*openapi3.Schema)
+	if spec.Components == nil {
+		return schemas
+	}
+	for name, ref := range spec.Components.Schemas {
+		if ref != nil && ref.Value != nil {
+			schemas[name] = ref.Value
+		}
+	}
+	return schemas
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys[V any](a, b map[string]V) []string {
+	union := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		union[k] = true
+	}
+	for k := range b {
+		union[k] = true
+	}
+	return sortedKeys(union)
+}
+
+// BreakingChanges returns the breaking changes among changes.
+func BreakingChanges(changes []SpecChange) []SpecChange {
+	var breaking []SpecChange
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
@@ -0,0 +1,102 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSpecs(t *testing.T) {
+	load := func(data string) *openapi3.T {
+		spec, err := openapi3.NewLoader().LoadFromData([]byte(data))
+		require.NoError(t, err)
+		return spec
+	}
+
+	base := load(`{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/legacy": {"get": {"responses": {"200": {"description": "OK"}}}},
+			"/users": {
+				"get": {
+					"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}],
+					"responses": {"200": {"description": "OK"}, "404": {"description": "Not found"}}
+				},
+				"delete": {"responses": {"204": {"description": "Deleted"}}}
+			},
+			"/orders": {
+				"get": {"summary": "List orders", "responses": {"200": {"description": "OK"}}}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object"},
+				"Legacy": {"type": "object"}
+			}
+		}
+	}`)
+	revision := load(`{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "2.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [
+						{"name": "limit", "in": "query", "required": true, "schema": {"type": "integer"}},
+						{"name": "tenant", "in": "header", "required": true, "schema": {"type": "string"}},
+						{"name": "sort", "in": "query", "schema": {"type": "string"}}
+					],
+					"responses": {"200": {"description": "OK"}, "400": {"description": "Bad request"}}
+				}
+			},
+			"/orders": {
+				"get": {"summary": "List all orders", "responses": {"200": {"description": "OK"}}}
+			},
+			"/invoices": {"get": {"responses": {"200": {"description": "OK"}}}}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"id": {"type": "string"}}},
+				"Invoice": {"type": "object"}
+			}
+		}
+	}`)
+
+	changes := DiffSpecs(base, revision)
+
+	var described []string
+	for _, c := range changes {
+		described = append(described, c.String())
+	}
+	assert.Equal(t, []string{
+		"added path /invoices",
+		"removed path /legacy",
+		"changed operation GET /orders",
+		"changed GET /users parameter limit (query): now required",
+		"added GET /users parameter sort (query)",
+		"added GET /users parameter tenant (header): required",
+		"added GET /users response 400",
+		"removed GET /users response 404",
+		"removed operation DELETE /users",
+		"added schema Invoice",
+		"removed schema Legacy",
+		"changed schema User",
+	}, described)
+
+	var breaking []string
+	for _, c := range BreakingChanges(changes) {
+		breaking = append(breaking, c.String())
+	}
+	assert.Equal(t, []string{
+		"removed path /legacy",
+		"changed GET /users parameter limit (query): now required",
+		"added GET /users parameter tenant (header): required",
+		"removed GET /users response 404",
+		"removed operation DELETE /users",
+	}, breaking)
+
+	assert.Empty(t, DiffSpecs(base, base))
+}
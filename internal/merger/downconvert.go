@@ -0,0 +1,35 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// downconvertNullableTypes rewrites OpenAPI 3.1 type arrays containing "null"
+// into the OpenAPI 3.0 nullable form:
+//
+//	type: ["string", "null"]  ->  type: string, nullable: true
+//	type: ["null"]            ->  nullable: true (no type)
+func downconvertNullableTypes(spec *openapi3.T) {
+	walkSpecSchemas(spec, downconvertNullableSchema)
+}
+
+// downconvertNullableSchema applies the nullable rewrite to a single schema.
+func downconvertNullableSchema(schema *openapi3.Schema) {
+	if schema.Type == nil || !schema.Type.Includes(openapi3.TypeNull) {
+		return
+	}
+
+	remaining := make(openapi3.Types, 0, len(*schema.Type))
+	for _, t := range *schema.Type {
+		if t != openapi3.TypeNull {
+			remaining = append(remaining, t)
+		}
+	}
+
+	schema.Nullable = true
+	if len(remaining) == 0 {
+		schema.Type = nil
+	} else {
+		schema.Type = &remaining
+	}
+}
@@ -0,0 +1,102 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownconvertNullableSchema(t *testing.T) {
+	tests := []struct {
+		name         string
+		types        *openapi3.Types
+		wantTypes    *openapi3.Types
+		wantNullable bool
+	}{
+		{"string and null", &openapi3.Types{"string", "null"}, &openapi3.Types{"string"}, true},
+		{"null first", &openapi3.Types{"null", "integer"}, &openapi3.Types{"integer"}, true},
+		{"only null", &openapi3.Types{"null"}, nil, true},
+		{"no null", &openapi3.Types{"string"}, &openapi3.Types{"string"}, false},
+		{"multiple without null", &openapi3.Types{"string", "integer"}, &openapi3.Types{"string", "integer"}, false},
+		{"no type", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &openapi3.Schema{Type: tt.types}
+			downconvertNullableSchema(schema)
+			assert.Equal(t, tt.wantTypes, schema.Type)
+			assert.Equal(t, tt.wantNullable, schema.Nullable)
+		})
+	}
+}
+
+func TestMerger_DownconvertNullableTypes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.1.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [
+						{"name": "q", "in": "query", "schema": {"type": ["string", "null"]}}
+					],
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {
+					"type": "object",
+					"properties": {
+						"nickname": {"type": ["string", "null"]},
+						"tags": {
+							"type": "array",
+							"items": {"type": ["integer", "null"]}
+						},
+						"nothing": {"type": ["null"]}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath}},
+		Output: outputPath,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	props := m.master.Components.Schemas["User"].Value.Properties
+	assert.Equal(t, &openapi3.Types{"string"}, props["nickname"].Value.Type)
+	assert.True(t, props["nickname"].Value.Nullable)
+	assert.Equal(t, &openapi3.Types{"integer"}, props["tags"].Value.Items.Value.Type)
+	assert.True(t, props["tags"].Value.Items.Value.Nullable)
+	assert.Nil(t, props["nothing"].Value.Type)
+	assert.True(t, props["nothing"].Value.Nullable)
+
+	param := m.master.Paths.Find("/users").Get.Parameters[0].Value
+	assert.Equal(t, &openapi3.Types{"string"}, param.Schema.Value.Type)
+	assert.True(t, param.Schema.Value.Nullable)
+
+	outputData, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(outputData), `"null"`)
+}
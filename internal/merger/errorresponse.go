@@ -0,0 +1,75 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	defaultErrorDescription = "Unexpected error"
+	defaultErrorContentType = "application/problem+json"
+)
+
+// injectDefaultErrorResponse adds a default response referencing the
+// configured error schema to every operation that has none. The schema is
+// added to components from the config when no input defines it.
+func (m *Merger) injectDefaultErrorResponse() error {
+	cfg := m.cfg.DefaultErrorResponse
+	name := cfg.SchemaName()
+
+	if _, exists := m.master.Components.Schemas[name]; !exists {
+		if cfg.Schema == nil {
+			return fmt.Errorf("defaultErrorResponse: schema %q is not defined by any input and no schema is configured", name)
+		}
+		schema, err := decodeConfigSchema(cfg.Schema)
+		if err != nil {
+			return fmt.Errorf("defaultErrorResponse: invalid schema: %w", err)
+		}
+		m.master.Components.Schemas[name] = schema
+	}
+
+	description := cfg.Description
+	if description == "" {
+		description = defaultErrorDescription
+	}
+	contentType := cfg.ContentType
+	if contentType == "" {
+		contentType = defaultErrorContentType
+	}
+
+	count := 0
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.Responses == nil {
+			op.Responses = openapi3.NewResponsesWithCapacity(1)
+		}
+		if op.Responses.Default() != nil {
+			return
+		}
+		response := openapi3.NewResponse().
+			WithDescription(description).
+			WithContent(openapi3.NewContentWithSchemaRef(openapi3.NewSchemaRef(cfg.SchemaRef, nil), []string{contentType}))
+		op.Responses.Set("default", &openapi3.ResponseRef{Value: response})
+		count++
+	})
+
+	if m.verbose && count > 0 {
+		fmt.Fprintf(m.log, "Added a default error response to %d operations\n", count)
+	}
+	return nil
+}
+
+// decodeConfigSchema converts a schema written in the config file, inline or
+// as a $ref, into an OpenAPI schema.
+func decodeConfigSchema(v interface{}) (*openapi3.SchemaRef, error) {
+	data, err := json.Marshal(stringKeys(v))
+	if err != nil {
+		return nil, err
+	}
+	var schema openapi3.SchemaRef
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
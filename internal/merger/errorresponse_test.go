@@ -0,0 +1,97 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_DefaultErrorResponse(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {
+					"responses": {
+						"201": {"description": "Created"},
+						"default": {"description": "Own error"}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	problem := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"title", "status"},
+		"properties": map[string]interface{}{
+			"type":   map[string]interface{}{"type": "string", "format": "uri"},
+			"title":  map[string]interface{}{"type": "string"},
+			"status": map[string]interface{}{"type": "integer"},
+		},
+	}
+
+	t.Run("injects the response and the schema", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			DefaultErrorResponse: &config.DefaultErrorResponseConfig{
+				SchemaRef: "#/components/schemas/Problem",
+				Schema:    problem,
+			},
+		}
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		orders := m.master.Paths.Value("/orders")
+		resp := orders.Get.Responses.Default()
+		require.NotNil(t, resp)
+		assert.Equal(t, "Unexpected error", *resp.Value.Description)
+		media := resp.Value.Content.Get("application/problem+json")
+		require.NotNil(t, media)
+		assert.Equal(t, "#/components/schemas/Problem", media.Schema.Ref)
+
+		// Operations that declare their own default keep it
+		assert.Equal(t, "Own error", *orders.Post.Responses.Default().Value.Description)
+
+		schema := m.master.Components.Schemas["Problem"]
+		require.NotNil(t, schema)
+		assert.Equal(t, []string{"title", "status"}, schema.Value.Required)
+		assert.Equal(t, "uri", schema.Value.Properties["type"].Value.Format)
+	})
+
+	t.Run("schema missing", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs:               []config.InputConfig{{InputFile: specPath}},
+			Output:               filepath.Join(tempDir, "merged.json"),
+			DefaultErrorResponse: &config.DefaultErrorResponseConfig{SchemaRef: "#/components/schemas/Problem"},
+		}
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `schema "Problem" is not defined by any input`)
+	})
+}
+
+func TestConfig_ValidateDefaultErrorResponse(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:               []config.InputConfig{{InputFile: "api.json"}},
+		Output:               "merged.json",
+		DefaultErrorResponse: &config.DefaultErrorResponseConfig{SchemaRef: "Problem"},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "defaultErrorResponse.schemaRef")
+}
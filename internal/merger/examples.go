@@ -0,0 +1,148 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// invalidComponentNameChars matches characters not allowed in component names.
+var invalidComponentNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// exampleUse is one inline example, identified by its map and key.
+type exampleUse struct {
+	examples openapi3.Examples
+	key      string
+}
+
+// hoistExamples moves inline examples that occur more than once across
+// operations into components.examples and replaces every occurrence with a
+// $ref. Examples that are already references are left alone.
+func (m *Merger) hoistExamples() {
+	if m.master.Paths == nil {
+		return
+	}
+
+	// Group inline examples by their serialized value, in walk order
+	groups := make(map[string][]exampleUse)
+	var order []string
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		walkOperationExamples(op, func(examples openapi3.Examples) {
+			for _, key := range sortedKeys(examples) {
+				ex := examples[key]
+				if ex == nil || ex.Ref != "" || ex.Value == nil {
+					continue
+				}
+				data, err := json.Marshal(ex.Value)
+				if err != nil {
+					continue
+				}
+				if _, seen := groups[string(data)]; !seen {
+					order = append(order, string(data))
+				}
+				groups[string(data)] = append(groups[string(data)], exampleUse{examples, key})
+			}
+		})
+	})
+
+	if m.master.Components.Examples == nil {
+		m.master.Components.Examples = make(openapi3.Examples)
+	}
+
+	hoisted := 0
+	for _, value := range order {
+		uses := groups[value]
+		if len(uses) < 2 {
+			continue
+		}
+
+		first := uses[0].examples[uses[0].key].Value
+		name := m.exampleComponentName(uses[0].key, value)
+		m.master.Components.Examples[name] = &openapi3.ExampleRef{Value: first}
+
+		ref := componentsRefPrefix + "examples/" + name
+		for _, use := range uses {
+			use.examples[use.key] = &openapi3.ExampleRef{Ref: ref, Value: first}
+		}
+		hoisted++
+	}
+
+	if m.verbose && hoisted > 0 {
+		fmt.Fprintf(m.log, "Hoisted %d repeated examples into components\n", hoisted)
+	}
+}
+
+// exampleComponentName derives a component name from the example key. An
+// existing component with the same value is reused; otherwise a numeric
+// suffix avoids clashes.
+func (m *Merger) exampleComponentName(key, value string) string {
+	base := invalidComponentNameChars.ReplaceAllString(key, "_")
+	name := base
+	for n := 2; ; n++ {
+		existing, ok := m.master.Components.Examples[name]
+		if !ok {
+			return name
+		}
+		if existing != nil && existing.Value != nil {
+			if data, err := json.Marshal(existing.Value); err == nil && string(data) == value {
+				return name
+			}
+		}
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+}
+
+// walkOperationExamples calls fn for every inline examples map in an
+// operation's parameters, request body and responses, in a stable order.
+func walkOperationExamples(op *openapi3.Operation, fn func(openapi3.Examples)) {
+	for _, param := range op.Parameters {
+		if param != nil && param.Ref == "" && param.Value != nil {
+			fn(param.Value.Examples)
+			walkContentExamples(param.Value.Content, fn)
+		}
+	}
+
+	if body := op.RequestBody; body != nil && body.Ref == "" && body.Value != nil {
+		walkContentExamples(body.Value.Content, fn)
+	}
+
+	if op.Responses == nil {
+		return
+	}
+	responses := op.Responses.Map()
+	for _, code := range sortedKeys(responses) {
+		resp := responses[code]
+		if resp == nil || resp.Ref != "" || resp.Value == nil {
+			continue
+		}
+		walkContentExamples(resp.Value.Content, fn)
+		for _, name := range sortedKeys(resp.Value.Headers) {
+			header := resp.Value.Headers[name]
+			if header != nil && header.Ref == "" && header.Value != nil {
+				fn(header.Value.Examples)
+				walkContentExamples(header.Value.Content, fn)
+			}
+		}
+	}
+}
+
+func walkContentExamples(content openapi3.Content, fn func(openapi3.Examples)) {
+	for _, mediaType := range sortedKeys(content) {
+		if mt := content[mediaType]; mt != nil {
+			fn(mt.Examples)
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_HoistExamples(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {
+								"application/json": {
+									"examples": {
+										"alice": {"summary": "A user", "value": {"id": 1, "name": "Alice"}},
+										"empty": {"value": []}
+									}
+								}
+							}
+						}
+					}
+				}
+			},
+			"/users/{id}": {
+				"get": {
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {
+								"application/json": {
+									"examples": {
+										"user": {"summary": "A user", "value": {"id": 1, "name": "Alice"}},
+										"shared": {"$ref": "#/components/examples/Shared"}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"examples": {
+				"Shared": {"value": {"id": 2}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:        []config.InputConfig{{InputFile: specPath}},
+		Output:        filepath.Join(tempDir, "merged.json"),
+		HoistExamples: true,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	// The two identical examples become one component named after the first use
+	examples := m.master.Components.Examples
+	assert.Len(t, examples, 2)
+	require.Contains(t, examples, "alice")
+	assert.Equal(t, "A user", examples["alice"].Value.Summary)
+
+	list := m.master.Paths.Value("/users").Get.Responses.Value("200").Value.Content["application/json"].Examples
+	get := m.master.Paths.Value("/users/{id}").Get.Responses.Value("200").Value.Content["application/json"].Examples
+	assert.Equal(t, "#/components/examples/alice", list["alice"].Ref)
+	assert.Equal(t, "#/components/examples/alice", get["user"].Ref)
+
+	// Unique and referenced examples are left alone
+	assert.Empty(t, list["empty"].Ref)
+	assert.Equal(t, "#/components/examples/Shared", get["shared"].Ref)
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$ref": "#/components/examples/alice"`)
+}
@@ -0,0 +1,119 @@
+package merger
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// tagGroupsExtension lists Redoc's navigation groups of tags.
+const tagGroupsExtension = "x-tagGroups"
+
+// extensionSet collects the x- extensions of one object (the root or info)
+// across inputs.
+type extensionSet struct {
+	object  string
+	values  map[string]interface{}
+	sources map[string]string
+}
+
+func newExtensionSet(object string) *extensionSet {
+	return &extensionSet{
+		object:  object,
+		values:  make(map[string]interface{}),
+		sources: make(map[string]string),
+	}
+}
+
+// Values returns the collected extensions, or nil if there are none.
+func (s *extensionSet) Values() map[string]interface{} {
+	if len(s.values) == 0 {
+		return nil
+	}
+	return maps.Clone(s.values)
+}
+
+// mergeExtensions adds an input's x- extensions to set. x-tagGroups are
+// concatenated; other extensions the inputs define differently are resolved
+// by ExtensionConflict. Keys without the x- prefix are ignored.
+func (m *Merger) mergeExtensions(set *extensionSet, extensions map[string]interface{}, source string) error {
+	for _, key := range sortedKeys(extensions) {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		value := extensions[key]
+
+		existing, ok := set.values[key]
+		if !ok {
+			set.values[key] = value
+			set.sources[key] = source
+			continue
+		}
+		if key == tagGroupsExtension {
+			set.values[key] = mergeTagGroups(existing, value)
+			continue
+		}
+		if jsonEqual(existing, value) {
+			continue
+		}
+
+		switch m.cfg.ExtensionConflict {
+		case config.ExtensionConflictLast:
+			set.values[key] = value
+			set.sources[key] = source
+		case config.ExtensionConflictError:
+			return fmt.Errorf("%s extension %s is defined differently by %s and %s", set.object, key, set.sources[key], source)
+		}
+	}
+	return nil
+}
+
+// mergeTagGroups appends the x-tagGroups of a later input to the collected
+// ones. Groups with the same name are combined, keeping each tag once.
+// Values that are not lists of groups leave the collected value unchanged.
+func mergeTagGroups(existing, value interface{}) interface{} {
+	groups, ok := existing.([]interface{})
+	more, moreOK := value.([]interface{})
+	if !ok || !moreOK {
+		return existing
+	}
+
+	// Copy so that the inputs' groups are never modified
+	merged := make([]interface{}, 0, len(groups)+len(more))
+	byName := make(map[string]map[string]interface{})
+	add := func(group interface{}) {
+		g, ok := group.(map[string]interface{})
+		if !ok {
+			merged = append(merged, group)
+			return
+		}
+		name, _ := g["name"].(string)
+		tags, _ := g["tags"].([]interface{})
+		if target, ok := byName[name]; ok && name != "" {
+			targetTags, _ := target["tags"].([]interface{})
+			for _, tag := range tags {
+				if !slices.Contains(targetTags, tag) {
+					targetTags = append(targetTags, tag)
+				}
+			}
+			target["tags"] = targetTags
+			return
+		}
+		copied := maps.Clone(g)
+		if tags != nil {
+			copied["tags"] = slices.Clone(tags)
+		}
+		byName[name] = copied
+		merged = append(merged, copied)
+	}
+	for _, group := range groups {
+		add(group)
+	}
+	for _, group := range more {
+		add(group)
+	}
+	return merged
+}
@@ -0,0 +1,76 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_RootAndInfoExtensions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0", "x-logo": {"url": "https://example.com/logo.png"}},
+		"x-tagGroups": [{"name": "Accounts", "tags": ["Users"]}],
+		"x-audience": "public",
+		"paths": {}
+	}`
+	billing := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Billing", "version": "1.0.0", "x-logo": {"url": "https://example.com/billing.png"}},
+		"x-tagGroups": [
+			{"name": "Accounts", "tags": ["Users", "Customers"]},
+			{"name": "Money", "tags": ["Invoices"]}
+		],
+		"x-audience": "partner",
+		"paths": {}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{users, billing} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+
+	merge := func(conflict string) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs:            inputs,
+			Output:            filepath.Join(tempDir, "merged.json"),
+			ExtensionConflict: conflict,
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	m, err := merge("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"url": "https://example.com/logo.png"}, m.master.Info.Extensions["x-logo"])
+	assert.Equal(t, "public", m.master.Extensions["x-audience"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Accounts", "tags": []interface{}{"Users", "Customers"}},
+		map[string]interface{}{"name": "Money", "tags": []interface{}{"Invoices"}},
+	}, m.master.Extensions["x-tagGroups"])
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "merged.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"x-logo"`)
+
+	m, err = merge(config.ExtensionConflictLast)
+	require.NoError(t, err)
+	assert.Equal(t, "partner", m.master.Extensions["x-audience"])
+
+	_, err = merge(config.ExtensionConflictError)
+	assert.ErrorContains(t, err, "root extension x-audience is defined differently by")
+}
@@ -0,0 +1,203 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// keepExternalRefs makes the loader leave external references unresolved.
+// The loader cannot skip them, so it is given stand-in documents holding a
+// minimal valid object at every referenced pointer; the $ref strings
+// themselves are kept verbatim and end up unchanged in the output.
+func keepExternalRefs(loader *openapi3.Loader, raw map[string]interface{}) error {
+	stubs, err := externalRefStubs(raw)
+	if err != nil {
+		return err
+	}
+
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		for file, doc := range stubs {
+			if refFileMatches(file, location) {
+				return json.Marshal(doc)
+			}
+		}
+		return nil, fmt.Errorf("unexpected external reference to %s", location)
+	}
+	return nil
+}
+
+// danglingRefsFile is the stand-in document that dangling local references
+// point to while an input is loaded.
+const danglingRefsFile = "openapi-merge-dangling.json"
+
+// stubDanglingRefs lets the loader load a document whose local references in
+// dangling do not resolve. Like keepExternalRefs, it points them at stand-in
+// objects, served from danglingRefsFile; restoreDanglingRefs returns them to
+// their original form once the spec is loaded, so they reach the output as
+// written.
+func stubDanglingRefs(loader *openapi3.Loader, data []byte, dangling map[string]bool) ([]byte, error) {
+	// Decode without resolving references so they can be rewritten
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to scan dangling references: %w", err)
+	}
+	data, err := json.Marshal(stringKeys(doc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan dangling references: %w", err)
+	}
+	var spec openapi3.T
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to scan dangling references: %w", err)
+	}
+
+	stubs := make(map[string]interface{})
+	visitRefs(&spec, func(kind string, ref *string) {
+		if dangling[*ref] {
+			setStub(stubs, strings.TrimPrefix(*ref, "#"), stubValue(kind, *ref))
+			*ref = danglingRefsFile + *ref
+		}
+	})
+
+	read := loader.ReadFromURIFunc
+	if read == nil {
+		read = openapi3.DefaultReadFromURI
+	}
+	loader.ReadFromURIFunc = func(l *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if refFileMatches(danglingRefsFile, location) {
+			return json.Marshal(stubs)
+		}
+		return read(l, location)
+	}
+	return json.Marshal(&spec)
+}
+
+// restoreDanglingRefs undoes stubDanglingRefs on the loaded spec.
+func restoreDanglingRefs(spec *openapi3.T) {
+	visitRefs(spec, func(_ string, ref *string) {
+		*ref = strings.TrimPrefix(*ref, danglingRefsFile)
+	})
+}
+
+// externalRefStubs builds a stand-in document for every external file
+// referenced in the parsed spec, keyed by the file part of the reference.
+func externalRefStubs(raw map[string]interface{}) (map[string]map[string]interface{}, error) {
+	// Decode without resolving references to learn what each one must be
+	data, err := json.Marshal(stringKeys(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan external references: %w", err)
+	}
+	var spec openapi3.T
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to scan external references: %w", err)
+	}
+
+	stubs := make(map[string]map[string]interface{})
+	visitRefs(&spec, func(kind string, ref *string) {
+		if strings.HasPrefix(*ref, "#") {
+			return
+		}
+		file, pointer, _ := strings.Cut(*ref, "#")
+		doc, ok := stubs[file]
+		if !ok {
+			doc = make(map[string]interface{})
+			stubs[file] = doc
+		}
+		setStub(doc, pointer, stubValue(kind, *ref))
+	})
+
+	return stubs, nil
+}
+
+// stubValue returns the smallest object that is valid for the component kind.
+func stubValue(kind, ref string) map[string]interface{} {
+	switch kind {
+	case "parameters":
+		return map[string]interface{}{"name": ref, "in": "query", "schema": map[string]interface{}{}}
+	case "headers":
+		return map[string]interface{}{"schema": map[string]interface{}{}}
+	case "responses":
+		return map[string]interface{}{"description": ""}
+	case "requestBodies":
+		return map[string]interface{}{"content": map[string]interface{}{"application/json": map[string]interface{}{}}}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// setStub stores value at the JSON pointer in doc, creating objects along
+// the way. An empty pointer refers to the whole document.
+func setStub(doc map[string]interface{}, pointer string, value map[string]interface{}) {
+	tokens := strings.Split(strings.Trim(pointer, "/"), "/")
+	if pointer == "" || len(tokens) == 0 || tokens[0] == "" {
+		for k, v := range value {
+			doc[k] = v
+		}
+		return
+	}
+
+	current := doc
+	for i, token := range tokens {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if i == len(tokens)-1 {
+			current[token] = value
+			return
+		}
+		next, ok := current[token].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[token] = next
+		}
+		current = next
+	}
+}
+
+// refFileMatches reports whether the file part of a reference resolves to location.
+func refFileMatches(file string, location *url.URL) bool {
+	if location.String() == file {
+		return true
+	}
+
+	u, err := url.Parse(file)
+	if err != nil {
+		return false
+	}
+	rel := path.Clean(u.Path)
+	for strings.HasPrefix(rel, "../") {
+		rel = strings.TrimPrefix(rel, "../")
+	}
+	return location.Path == rel || strings.HasSuffix(location.Path, "/"+rel)
+}
+
+// stringKeys converts YAML maps with non-string keys (such as unquoted
+// status codes) into JSON-compatible maps.
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			out[k] = stringKeys(child)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			out[fmt.Sprint(k)] = stringKeys(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = stringKeys(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
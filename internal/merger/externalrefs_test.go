@@ -0,0 +1,71 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_KeepExternalRefs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	// The referenced files do not exist; they are published separately
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "../shared/parameters.yaml#/PageSize"}],
+					"responses": {
+						"200": {
+							"description": "Success",
+							"content": {
+								"application/json": {
+									"schema": {"$ref": "#/components/schemas/UserList"}
+								}
+							}
+						},
+						"default": {"$ref": "https://specs.example.com/common.yaml#/components/responses/Error"}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"UserList": {
+					"type": "array",
+					"items": {"$ref": "./models/user.yaml"}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:           []config.InputConfig{{InputFile: specPath}},
+		Output:           outputPath,
+		KeepExternalRefs: true,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Empty(t, m.Warnings())
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	output := string(data)
+
+	assert.Contains(t, output, `"$ref": "../shared/parameters.yaml#/PageSize"`)
+	assert.Contains(t, output, `"$ref": "https://specs.example.com/common.yaml#/components/responses/Error"`)
+	assert.Contains(t, output, `"$ref": "./models/user.yaml"`)
+	assert.Contains(t, output, `"$ref": "#/components/schemas/UserList"`)
+}
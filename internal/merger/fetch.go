@@ -0,0 +1,83 @@
+package merger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// retryableError marks a failed attempt that may succeed when repeated: a
+// network error or a 5xx response.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// getWithRetries fetches url with the configured timeout, retrying network
+// errors and 5xx responses with exponential backoff. Other failures, such as
+// a 4xx response, are returned at once.
+func (m *Merger) getWithRetries(url string, headers map[string]string) ([]byte, error) {
+	client := m.httpClient()
+	attempts := m.cfg.HTTP.Attempts()
+
+	for attempt := 1; ; attempt++ {
+		data, err := m.get(client, url, headers)
+		if err == nil {
+			return data, nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt == attempts {
+			if attempt > 1 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return nil, err
+		}
+
+		backoff := m.cfg.HTTP.Backoff(attempt)
+		if m.verbose {
+			fmt.Fprintf(m.log, "  Attempt %d failed (%v), retrying in %s\n", attempt, err, backoff)
+		}
+		m.sleep(backoff)
+	}
+}
+
+// httpClient returns a client bounded by the configured request timeout.
+func (m *Merger) httpClient() *http.Client {
+	return &http.Client{Timeout: m.cfg.HTTP.Timeout()}
+}
+
+// get makes a single GET request and returns the response body.
+func (m *Merger) get(client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	req, err := m.newRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &retryableError{fmt.Errorf("failed to fetch URL: %w", err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, resp.Status)
+		if resp.StatusCode >= 500 {
+			return nil, &retryableError{err}
+		}
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &retryableError{fmt.Errorf("failed to read response body: %w", err)}
+	}
+	return data, nil
+}
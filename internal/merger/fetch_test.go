@@ -0,0 +1,176 @@
+package merger
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_FetchRetries(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Remote", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+
+	// Each server answers with the given statuses in turn, then with the spec
+	serve := func(t *testing.T, statuses ...int) (*httptest.Server, *int) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= len(statuses) {
+				w.WriteHeader(statuses[requests-1])
+				return
+			}
+			_, _ = w.Write([]byte(spec))
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	merge := func(url string, httpCfg *config.HTTPConfig) ([]time.Duration, error) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: url + "/openapi.json"}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			HTTP:   httpCfg,
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		var waits []time.Duration
+		m := New(cfg, false)
+		m.sleep = func(d time.Duration) { waits = append(waits, d) }
+		err := m.Merge()
+		return waits, err
+	}
+
+	t.Run("5xx is retried with exponential backoff", func(t *testing.T) {
+		server, requests := serve(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+		waits, err := merge(server.URL, &config.HTTPConfig{Retries: 3, RetryBackoffMs: 100})
+		require.NoError(t, err)
+		assert.Equal(t, 3, *requests)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, waits)
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		server, requests := serve(t, 500, 500, 500)
+		_, err := merge(server.URL, &config.HTTPConfig{Retries: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 500")
+		assert.Contains(t, err.Error(), "after 2 attempts")
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("4xx fails without retrying", func(t *testing.T) {
+		server, requests := serve(t, http.StatusNotFound)
+		waits, err := merge(server.URL, &config.HTTPConfig{Retries: 3})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 404")
+		assert.NotContains(t, err.Error(), "attempts")
+		assert.Equal(t, 1, *requests)
+		assert.Empty(t, waits)
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		server, requests := serve(t, http.StatusServiceUnavailable)
+		_, err := merge(server.URL, nil)
+		require.Error(t, err)
+		assert.Equal(t, 1, *requests)
+	})
+
+	t.Run("negative values are rejected", func(t *testing.T) {
+		_, err := merge("https://example.com", &config.HTTPConfig{Retries: -1})
+		assert.ErrorContains(t, err, "must not be negative")
+	})
+}
+
+func TestHTTPConfig_Defaults(t *testing.T) {
+	var unset *config.HTTPConfig
+	assert.Equal(t, 30*time.Second, unset.Timeout())
+	assert.Equal(t, 1, unset.Attempts())
+	assert.Equal(t, time.Second, unset.Backoff(2))
+
+	cfg := &config.HTTPConfig{TimeoutSeconds: 5, Retries: 2, RetryBackoffMs: 50}
+	assert.Equal(t, 5*time.Second, cfg.Timeout())
+	assert.Equal(t, 3, cfg.Attempts())
+	assert.Equal(t, 200*time.Millisecond, cfg.Backoff(3))
+	assert.Equal(t, config.MaxHTTPRetryBackoff, cfg.Backoff(100))
+
+	slow := &config.HTTPConfig{RetryBackoffMs: math.MaxInt}
+	assert.Equal(t, config.MaxHTTPRetryBackoff, slow.Backoff(1))
+}
+
+func TestMerger_InputHeaders(t *testing.T) {
+	t.Setenv("REGISTRY_TOKEN", "s3cret")
+
+	root := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Registry", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {
+			"description": "OK",
+			"content": {"application/json": {"schema": {"$ref": "common.json#/User"}}}
+		}}}}}
+	}`
+	common := `{"User": {"type": "object"}}`
+
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.URL.Path+" "+r.Header.Get("Authorization")+" "+r.Header.Get("X-Team"))
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/openapi.json":
+			_, _ = w.Write([]byte(root))
+		case "/common.json":
+			_, _ = w.Write([]byte(common))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	input := config.InputConfig{InputFile: server.URL + "/openapi.json"}
+	cfg := &config.Config{
+		Inputs:             []config.InputConfig{input},
+		Output:             filepath.Join(tempDir, "merged.json"),
+		BundleExternalRefs: true,
+	}
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+
+	seen = nil
+	cfg.Inputs[0].Headers = map[string]string{"Authorization": "Bearer ${REGISTRY_TOKEN}", "X-Team": "payments"}
+	require.NoError(t, cfg.Validate())
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Contains(t, m.master.Components.Schemas, "User")
+	assert.Equal(t, []string{
+		"/openapi.json Bearer s3cret payments",
+		"/common.json Bearer s3cret payments",
+	}, seen)
+
+	local := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: "spec.json", Headers: map[string]string{"X-Team": "payments"}}},
+		Output: "merged.json",
+	}
+	assert.EqualError(t, local.Validate(), "input[0]: headers require a URL inputFile")
+}
@@ -0,0 +1,69 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// formDataNameExtension marks the schema properties that the Swagger 2.0
+// converter built from formData parameters.
+const formDataNameExtension = "x-formData-name"
+
+// formDataMediaTypes maps each FormDataMode to its media type.
+var formDataMediaTypes = map[string]string{
+	config.FormDataModeURLEncoded: "application/x-www-form-urlencoded",
+	config.FormDataModeMultipart:  "multipart/form-data",
+}
+
+// applyFormDataMode replaces the media types of request bodies converted from
+// formData parameters with the one selected by FormDataMode. The converter
+// uses the operation's consumes, which may be empty or list unrelated types.
+func (m *Merger) applyFormDataMode(spec *openapi3.T, source string) {
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.RequestBody == nil || op.RequestBody.Ref != "" || op.RequestBody.Value == nil {
+			return
+		}
+		body := op.RequestBody.Value
+		schema := formDataSchema(body)
+		if schema == nil {
+			return
+		}
+
+		mode := m.cfg.FormDataMode
+		if mode == config.FormDataModeURLEncoded && hasBinaryProperty(schema.Value) {
+			m.warnf(source, "%s %s uploads files, which cannot be sent urlencoded; using multipart/form-data", method, path)
+			mode = config.FormDataModeMultipart
+		}
+		body.Content = openapi3.NewContentWithSchemaRef(schema, []string{formDataMediaTypes[mode]})
+	})
+}
+
+// formDataSchema returns the schema of a request body built from formData
+// parameters, or nil if the body was a regular body parameter.
+func formDataSchema(body *openapi3.RequestBody) *openapi3.SchemaRef {
+	for _, mediaType := range sortedKeys(body.Content) {
+		media := body.Content[mediaType]
+		if media == nil || media.Schema == nil || media.Schema.Value == nil {
+			continue
+		}
+		for _, property := range media.Schema.Value.Properties {
+			if property == nil || property.Value == nil {
+				continue
+			}
+			if _, ok := property.Value.Extensions[formDataNameExtension]; ok {
+				return media.Schema
+			}
+		}
+	}
+	return nil
+}
+
+// hasBinaryProperty reports whether a form schema has a file property.
+func hasBinaryProperty(schema *openapi3.Schema) bool {
+	for _, property := range schema.Properties {
+		if property != nil && property.Value != nil && property.Value.Format == "binary" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_FormDataMode(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"swagger": "2.0",
+		"info": {"title": "Legacy", "version": "1.0.0"},
+		"paths": {
+			"/login": {
+				"post": {
+					"consumes": ["application/json"],
+					"parameters": [
+						{"name": "username", "in": "formData", "type": "string", "required": true},
+						{"name": "password", "in": "formData", "type": "string", "required": true}
+					],
+					"responses": {"200": {"description": "OK"}}
+				}
+			},
+			"/avatar": {
+				"post": {
+					"parameters": [
+						{"name": "file", "in": "formData", "type": "file"}
+					],
+					"responses": {"200": {"description": "OK"}}
+				}
+			},
+			"/users": {
+				"post": {
+					"consumes": ["application/json"],
+					"parameters": [
+						{"name": "body", "in": "body", "schema": {"type": "object"}}
+					],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "legacy.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	tests := []struct {
+		name      string
+		mode      string
+		login     string
+		avatar    string
+		warnCount int
+	}{
+		{"multipart", config.FormDataModeMultipart, "multipart/form-data", "multipart/form-data", 0},
+		{"urlencoded", config.FormDataModeURLEncoded, "application/x-www-form-urlencoded", "multipart/form-data", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Inputs:       []config.InputConfig{{InputFile: specPath}},
+				Output:       filepath.Join(tempDir, "merged.json"),
+				FormDataMode: tt.mode,
+			}
+			m := New(cfg, false)
+			require.NoError(t, m.Merge())
+
+			login := m.master.Paths.Value("/login").Post.RequestBody.Value
+			require.Len(t, login.Content, 1)
+			media := login.Content.Get(tt.login)
+			require.NotNil(t, media)
+			assert.Contains(t, media.Schema.Value.Properties, "username")
+			assert.ElementsMatch(t, []string{"username", "password"}, media.Schema.Value.Required)
+
+			avatar := m.master.Paths.Value("/avatar").Post.RequestBody.Value
+			require.Len(t, avatar.Content, 1)
+			assert.NotNil(t, avatar.Content.Get(tt.avatar))
+			assert.Len(t, m.Warnings(), tt.warnCount)
+
+			// Regular body parameters keep their media type
+			users := m.master.Paths.Value("/users").Post.RequestBody.Value
+			assert.NotNil(t, users.Content.Get("application/json"))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package merger
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// globalResponseContentType is the media type of a global response body when
+// none is configured.
+const globalResponseContentType = "application/json"
+
+// applyGlobalResponses adds the configured global responses to every
+// operation that does not define the same status code, so operation-specific
+// responses are never replaced.
+func (m *Merger) applyGlobalResponses() error {
+	if m.master.Paths == nil {
+		return nil
+	}
+
+	statuses := sortedKeys(m.cfg.GlobalResponses)
+	count := 0
+	for _, path := range sortedPaths(m.master.Paths) {
+		pathItem := m.master.Paths.Value(path)
+		for _, method := range httpMethods {
+			op := pathItem.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			if op.Responses == nil {
+				op.Responses = openapi3.NewResponsesWithCapacity(len(statuses))
+			}
+			for _, status := range statuses {
+				key := config.NormalizeStatusCode(status)
+				if op.Responses.Value(key) != nil {
+					continue
+				}
+				response, err := m.globalResponse(m.cfg.GlobalResponses[status])
+				if err != nil {
+					return fmt.Errorf("globalResponses[%s]: %w", status, err)
+				}
+				op.Responses.Set(key, response)
+				count++
+			}
+		}
+	}
+
+	if m.verbose && count > 0 {
+		fmt.Fprintf(m.log, "Added %d global responses\n", count)
+	}
+	return nil
+}
+
+// globalResponse builds the response to inject: a reference to a component
+// response of the merged spec, or an inline response.
+func (m *Merger) globalResponse(cfg config.ResponseConfig) (*openapi3.ResponseRef, error) {
+	if cfg.Ref != "" {
+		var component *openapi3.ResponseRef
+		if m.master.Components != nil {
+			component = m.master.Components.Responses[cfg.ComponentName()]
+		}
+		if component == nil {
+			return nil, fmt.Errorf("response %s is not defined", cfg.Ref)
+		}
+		return &openapi3.ResponseRef{Ref: cfg.Ref, Value: component.Value}, nil
+	}
+
+	response := openapi3.NewResponse().WithDescription(cfg.Description)
+	if cfg.Schema != nil {
+		schema, err := decodeConfigSchema(cfg.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		contentType := cfg.ContentType
+		if contentType == "" {
+			contentType = globalResponseContentType
+		}
+		response.WithContent(openapi3.NewContentWithSchemaRef(schema, []string{contentType}))
+	}
+	return &openapi3.ResponseRef{Value: response}, nil
+}
@@ -0,0 +1,91 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_GlobalResponses(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {
+					"200": {"description": "OK"},
+					"401": {"description": "Missing users:read scope"}
+				}}
+			}
+		},
+		"components": {
+			"responses": {"TooManyRequests": {"description": "Rate limit exceeded"}}
+		}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {"post": {"responses": {"201": {"description": "Created"}}}}
+		}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{users, orders} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+
+	cfg := &config.Config{
+		Inputs: inputs,
+		Output: filepath.Join(tempDir, "merged.json"),
+		GlobalResponses: map[string]config.ResponseConfig{
+			"401": {
+				Description: "Unauthorized",
+				Schema:      map[string]interface{}{"$ref": "#/components/schemas/Problem"},
+				ContentType: "application/problem+json",
+			},
+			"429": {Ref: "#/components/responses/TooManyRequests"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	get := m.master.Paths.Value("/users").Get.Responses
+	assert.Equal(t, "Missing users:read scope", *get.Value("401").Value.Description, "operation responses are kept")
+	assert.Equal(t, "#/components/responses/TooManyRequests", get.Value("429").Ref)
+
+	post := m.master.Paths.Value("/orders").Post.Responses
+	assert.Equal(t, "Created", *post.Value("201").Value.Description)
+	assert.Equal(t, "Unauthorized", *post.Value("401").Value.Description)
+	assert.Equal(t, "#/components/schemas/Problem", post.Value("401").Value.Content["application/problem+json"].Schema.Ref)
+	assert.Equal(t, "#/components/responses/TooManyRequests", post.Value("429").Ref)
+
+	t.Run("undefined component response", func(t *testing.T) {
+		cfg.GlobalResponses = map[string]config.ResponseConfig{"503": {Ref: "#/components/responses/Unavailable"}}
+		err := New(cfg, false).Merge()
+		assert.ErrorContains(t, err, "globalResponses[503]: response #/components/responses/Unavailable is not defined")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		cfg.GlobalResponses = map[string]config.ResponseConfig{"42": {Description: "Nope"}}
+		assert.EqualError(t, cfg.Validate(), `globalResponses: invalid status code "42" (expected default, 1XX-5XX or a code from 100 to 599)`)
+
+		cfg.GlobalResponses = map[string]config.ResponseConfig{"4XX": {}}
+		assert.EqualError(t, cfg.Validate(), "globalResponses[4XX]: description is required")
+
+		cfg.GlobalResponses = map[string]config.ResponseConfig{"default": {Ref: "#/components/schemas/Problem"}}
+		assert.EqualError(t, cfg.Validate(), `globalResponses[default]: ref "#/components/schemas/Problem" must point to #/components/responses/<name>`)
+	})
+}
@@ -0,0 +1,99 @@
+package merger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OperationIndexEntry describes a single operation in the merged spec.
+type OperationIndexEntry struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+// OperationIndex returns one entry per operation in the merged spec,
+// ordered by path and then by HTTP method.
+func (m *Merger) OperationIndex() []OperationIndexEntry {
+	if m.master == nil || m.master.Paths == nil {
+		return nil
+	}
+
+	entries := make([]OperationIndexEntry, 0)
+	for _, path := range sortedPaths(m.master.Paths) {
+		operations := getOperationsMap(m.master.Paths.Value(path))
+		for _, method := range httpMethods {
+			op := operations[method]
+			if op == nil {
+				continue
+			}
+			entries = append(entries, OperationIndexEntry{
+				Method:      method,
+				Path:        path,
+				OperationID: op.OperationID,
+				Tags:        op.Tags,
+				Summary:     op.Summary,
+				Source:      m.sources[op],
+			})
+		}
+	}
+
+	return entries
+}
+
+// writeOperationIndex writes the operation index to disk.
+// The format is CSV for .csv files and JSON otherwise.
+func (m *Merger) writeOperationIndex(path string) error {
+	entries := m.OperationIndex()
+
+	var data []byte
+	var err error
+	if strings.ToLower(filepath.Ext(path)) == ".csv" {
+		data, err = marshalOperationIndexCSV(entries)
+	} else {
+		data, err = marshalJSONIndent(entries, "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation index: %w", err)
+	}
+
+	if err := m.ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write operation index: %w", err)
+	}
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Wrote operation index with %d entries to %s\n", len(entries), path)
+	}
+
+	return nil
+}
+
+// marshalOperationIndexCSV renders the index as CSV with a header row.
+// Multiple tags are joined with ";".
+func marshalOperationIndexCSV(entries []OperationIndexEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"method", "path", "operationId", "tags", "summary", "source"}); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		record := []string{e.Method, e.Path, e.OperationID, strings.Join(e.Tags, ";"), e.Summary, e.Source}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,98 @@
+package merger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_OperationIndex(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"operationId": "listUsers",
+					"summary": "List users",
+					"tags": ["Users"],
+					"responses": {"200": {"description": "Success"}}
+				},
+				"post": {
+					"operationId": "createUser",
+					"tags": ["Users", "Admin"],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {
+					"operationId": "listOrders",
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "users.json")
+	spec2Path := filepath.Join(tempDir, "orders.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	jsonIndexPath := filepath.Join(tempDir, "index.json")
+	csvIndexPath := filepath.Join(tempDir, "index.csv")
+
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: spec1Path},
+			{InputFile: spec2Path},
+		},
+		Output:         outputPath,
+		BasePath:       "/api",
+		OperationIndex: jsonIndexPath,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	data, err := os.ReadFile(jsonIndexPath)
+	require.NoError(t, err)
+
+	var entries []OperationIndexEntry
+	require.NoError(t, json.Unmarshal(data, &entries))
+
+	assert.Equal(t, []OperationIndexEntry{
+		{Method: "GET", Path: "/api/orders", OperationID: "listOrders", Source: spec2Path},
+		{Method: "GET", Path: "/api/users", OperationID: "listUsers", Tags: []string{"Users"}, Summary: "List users", Source: spec1Path},
+		{Method: "POST", Path: "/api/users", OperationID: "createUser", Tags: []string{"Users", "Admin"}, Source: spec1Path},
+	}, entries)
+
+	// CSV output
+	cfg.OperationIndex = csvIndexPath
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err = os.ReadFile(csvIndexPath)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"method,path,operationId,tags,summary,source\n"+
+			"GET,/api/orders,listOrders,,,"+spec2Path+"\n"+
+			"GET,/api/users,listUsers,Users,List users,"+spec1Path+"\n"+
+			"POST,/api/users,createUser,Users;Admin,,"+spec1Path+"\n",
+		string(data))
+}
@@ -0,0 +1,131 @@
+package merger
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// extractDeepSchemas moves inline object schemas nested deeper than
+// MaxInlineDepth into components.schemas and references them instead. The
+// properties of a top-level schema are at depth 1. Each extracted schema is
+// named after its location, e.g. OrderCustomerAddress for the address property
+// of an Order's customer, and counts as a new top-level schema for its own
+// nested schemas. Names are deterministic; a numeric suffix avoids clashes.
+func (m *Merger) extractDeepSchemas() {
+	x := &schemaExtractor{
+		maxDepth: m.cfg.MaxInlineDepth,
+		schemas:  m.master.Components.Schemas,
+	}
+
+	for _, name := range sortedKeys(m.master.Components.Schemas) {
+		x.visit(m.master.Components.Schemas[name], name, 0)
+	}
+	for _, name := range sortedKeys(m.master.Components.RequestBodies) {
+		if body := m.master.Components.RequestBodies[name]; body != nil && body.Ref == "" && body.Value != nil {
+			x.visitContent(body.Value.Content, name)
+		}
+	}
+	for _, name := range sortedKeys(m.master.Components.Responses) {
+		if resp := m.master.Components.Responses[name]; resp != nil && resp.Ref == "" && resp.Value != nil {
+			x.visitContent(resp.Value.Content, name)
+		}
+	}
+
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		name := op.OperationID
+		if name == "" {
+			name = strings.ToLower(method) + "/" + path
+		}
+		name = schemaNamePart(name)
+
+		for _, param := range op.Parameters {
+			if param != nil && param.Ref == "" && param.Value != nil {
+				x.visit(param.Value.Schema, name+schemaNamePart(param.Value.Name), 0)
+			}
+		}
+		if body := op.RequestBody; body != nil && body.Ref == "" && body.Value != nil {
+			x.visitContent(body.Value.Content, name+"Request")
+		}
+		if op.Responses != nil {
+			for _, code := range sortedKeys(op.Responses.Map()) {
+				if resp := op.Responses.Value(code); resp != nil && resp.Ref == "" && resp.Value != nil {
+					x.visitContent(resp.Value.Content, name+schemaNamePart(code)+"Response")
+				}
+			}
+		}
+	})
+
+	if m.verbose && len(x.extracted) > 0 {
+		fmt.Fprintf(m.log, "Extracted %d deeply nested schemas: %s\n", len(x.extracted), strings.Join(x.extracted, ", "))
+	}
+}
+
+// schemaExtractor carries the state of extractDeepSchemas.
+type schemaExtractor struct {
+	maxDepth  int
+	schemas   openapi3.Schemas
+	extracted []string
+}
+
+// visitContent visits the schema of every media type, sharing one base name.
+func (x *schemaExtractor) visitContent(content openapi3.Content, name string) {
+	for _, mediaType := range sortedKeys(content) {
+		if media := content[mediaType]; media != nil {
+			x.visit(media.Schema, name, 0)
+		}
+	}
+}
+
+// visit walks an inline schema at the given depth, extracting the object
+// schemas found beyond the maximum depth.
+func (x *schemaExtractor) visit(ref *openapi3.SchemaRef, name string, depth int) {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return
+	}
+	schema := ref.Value
+
+	if depth > x.maxDepth && len(schema.Properties) > 0 {
+		name = x.uniqueName(name)
+		x.schemas[name] = &openapi3.SchemaRef{Value: schema}
+		ref.Ref = componentsRefPrefix + "schemas/" + name
+		x.extracted = append(x.extracted, name)
+		depth = 0
+	}
+
+	for _, property := range sortedKeys(schema.Properties) {
+		x.visit(schema.Properties[property], name+schemaNamePart(property), depth+1)
+	}
+	x.visit(schema.Items, name+"Item", depth+1)
+	x.visit(schema.AdditionalProperties.Schema, name+"Value", depth+1)
+
+	// Composition members sit at the level of the schema they compose
+	for _, members := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, member := range members {
+			x.visit(member, name, depth)
+		}
+	}
+}
+
+// uniqueName returns name, numbered if a component schema already uses it.
+func (x *schemaExtractor) uniqueName(name string) string {
+	candidate := name
+	for n := 2; x.schemas[candidate] != nil; n++ {
+		candidate = fmt.Sprintf("%s%d", name, n)
+	}
+	return candidate
+}
+
+// schemaNamePart converts a property name, path or status code into a
+// PascalCase part of a schema name, e.g. "shipping_address" to ShippingAddress.
+func schemaNamePart(s string) string {
+	r := []rune(formatOperationID(s, config.OperationIDStyleCamel))
+	if len(r) == 0 {
+		return ""
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
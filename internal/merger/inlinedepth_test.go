@@ -0,0 +1,108 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_MaxInlineDepth(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"post": {
+					"operationId": "createOrder",
+					"requestBody": {
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"properties": {
+										"lines": {
+											"type": "array",
+											"items": {
+												"type": "object",
+												"properties": {
+													"sku": {"type": "string"},
+													"price": {"type": "object", "properties": {"amount": {"type": "number"}}}
+												}
+											}
+										}
+									}
+								}
+							}
+						}
+					},
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Order": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "string"},
+						"customer": {
+							"type": "object",
+							"properties": {
+								"name": {"type": "string"},
+								"shipping_address": {
+									"type": "object",
+									"properties": {
+										"city": {"type": "string"},
+										"geo": {
+											"type": "object",
+											"properties": {"lat": {"type": "number"}, "lng": {"type": "number"}}
+										}
+									}
+								}
+							}
+						}
+					}
+				},
+				"OrderCustomerShippingAddress": {"type": "string"}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:         []config.InputConfig{{InputFile: specPath}},
+		Output:         filepath.Join(tempDir, "merged.json"),
+		MaxInlineDepth: 1,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := m.master.Components.Schemas
+
+	// Depth 1 stays inline, depth 2 is extracted, with a numbered name on a clash
+	customer := schemas["Order"].Value.Properties["customer"]
+	assert.Empty(t, customer.Ref)
+	address := customer.Value.Properties["shipping_address"]
+	require.NotNil(t, address)
+	assert.Equal(t, "#/components/schemas/OrderCustomerShippingAddress2", address.Ref)
+	require.Contains(t, schemas, "OrderCustomerShippingAddress2")
+
+	// The extracted schema is a new top level: geo is at depth 1 there
+	extracted := schemas["OrderCustomerShippingAddress2"].Value
+	assert.Empty(t, extracted.Properties["geo"].Ref)
+
+	// Inline request bodies are named after the operation
+	item := m.master.Paths.Value("/orders").Post.RequestBody.Value.Content.Get("application/json").Schema.Value.Properties["lines"].Value.Items
+	assert.Equal(t, "#/components/schemas/CreateOrderRequestLinesItem", item.Ref)
+	assert.Empty(t, schemas["CreateOrderRequestLinesItem"].Value.Properties["price"].Ref)
+}
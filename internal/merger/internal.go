@@ -0,0 +1,129 @@
+package merger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// internalExtension marks operations, parameters, schemas and properties that
+// StripInternal removes from the output.
+const internalExtension = "x-internal"
+
+// isInternal reports whether extensions mark a node as internal. Both true and
+// the string "true" count.
+func isInternal(extensions map[string]interface{}) bool {
+	switch v := extensions[internalExtension].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+// stripInternal removes every operation, parameter, component schema and
+// schema property marked x-internal, along with properties and parameters
+// whose referenced schema or parameter is internal. Removed properties are
+// also dropped from required. References left pointing at a removed
+// component are reported as warnings, or as an error in strict mode.
+func (m *Merger) stripInternal() error {
+	removed := make(map[string]bool)
+
+	if m.master.Paths != nil {
+		for _, path := range sortedPaths(m.master.Paths) {
+			pathItem := m.master.Paths.Value(path)
+			if isInternal(pathItem.Extensions) {
+				m.master.Paths.Delete(path)
+				continue
+			}
+			pathItem.Parameters = withoutInternalParameters(pathItem.Parameters)
+			for method, op := range getOperationsMap(pathItem) {
+				if op == nil {
+					continue
+				}
+				if isInternal(op.Extensions) {
+					removeOperation(pathItem, method)
+					continue
+				}
+				op.Parameters = withoutInternalParameters(op.Parameters)
+			}
+			if isPathItemEmpty(pathItem) {
+				m.master.Paths.Delete(path)
+			}
+		}
+	}
+
+	if c := m.master.Components; c != nil {
+		for _, name := range sortedKeys(c.Schemas) {
+			if schema := c.Schemas[name]; schema.Value != nil && isInternal(schema.Value.Extensions) {
+				delete(c.Schemas, name)
+				removed[componentsRefPrefix+"schemas/"+name] = true
+			}
+		}
+		for _, name := range sortedKeys(c.Parameters) {
+			if param := c.Parameters[name]; param.Value != nil && isInternal(param.Value.Extensions) {
+				delete(c.Parameters, name)
+				removed[componentsRefPrefix+"parameters/"+name] = true
+			}
+		}
+	}
+
+	walkSpecSchemas(m.master, stripInternalProperties)
+
+	// Anything still referencing a removed component was not marked itself
+	var dangling []string
+	seen := make(map[string]bool)
+	visitRefs(m.master, func(kind string, ref *string) {
+		if removed[*ref] && !seen[*ref] {
+			seen[*ref] = true
+			dangling = append(dangling, *ref)
+		}
+	})
+	if len(dangling) == 0 {
+		return nil
+	}
+	sort.Strings(dangling)
+
+	if m.cfg.Strict {
+		return fmt.Errorf("internal components are still referenced: %s", strings.Join(dangling, ", "))
+	}
+	for _, ref := range dangling {
+		m.warnf("", "internal component %s was removed but is still referenced", ref)
+	}
+	return nil
+}
+
+// withoutInternalParameters returns params without the internal ones.
+func withoutInternalParameters(params openapi3.Parameters) openapi3.Parameters {
+	kept := params[:0]
+	for _, param := range params {
+		if param != nil && param.Value != nil && isInternal(param.Value.Extensions) {
+			continue
+		}
+		kept = append(kept, param)
+	}
+	return kept
+}
+
+// stripInternalProperties removes the internal properties of schema and drops
+// them from its required list.
+func stripInternalProperties(schema *openapi3.Schema) {
+	for name, prop := range schema.Properties {
+		if prop == nil || prop.Value == nil || !isInternal(prop.Value.Extensions) {
+			continue
+		}
+		delete(schema.Properties, name)
+
+		required := schema.Required[:0]
+		for _, r := range schema.Required {
+			if r != name {
+				required = append(required, r)
+			}
+		}
+		schema.Required = required
+	}
+}
@@ -0,0 +1,137 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const internalSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Users", "version": "1.0.0"},
+	"paths": {
+		"/users": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "schema": {"type": "integer"}},
+					{"name": "debug", "in": "query", "x-internal": true, "schema": {"type": "boolean"}}
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+					}
+				}
+			},
+			"delete": {
+				"x-internal": true,
+				"responses": {"204": {"description": "Purged"}}
+			}
+		},
+		"/admin/reindex": {
+			"post": {
+				"x-internal": "true",
+				"responses": {"202": {"description": "Accepted"}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"User": {
+				"type": "object",
+				"required": ["id", "passwordHash"],
+				"properties": {
+					"id": {"type": "string"},
+					"passwordHash": {"type": "string", "x-internal": true},
+					"audit": {"$ref": "#/components/schemas/AuditInfo"}
+				}
+			},
+			"AuditInfo": {"type": "object", "x-internal": true}
+		}
+	}
+}`
+
+func TestMerger_StripInternal(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(internalSpec), 0644))
+
+	cfg := &config.Config{
+		Inputs:        []config.InputConfig{{InputFile: specPath}},
+		Output:        filepath.Join(tempDir, "merged.json"),
+		StripInternal: true,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	users := m.master.Paths.Value("/users")
+	require.NotNil(t, users)
+	require.NotNil(t, users.Get)
+	assert.Nil(t, users.Delete, "internal operation is removed")
+	assert.Nil(t, m.master.Paths.Value("/admin/reindex"), "path left without operations is removed")
+
+	require.Len(t, users.Get.Parameters, 1)
+	assert.Equal(t, "limit", users.Get.Parameters[0].Value.Name)
+
+	user := m.master.Components.Schemas["User"].Value
+	assert.Contains(t, user.Properties, "id")
+	assert.NotContains(t, user.Properties, "passwordHash", "internal property is removed")
+	assert.NotContains(t, user.Properties, "audit", "property referencing an internal schema is removed")
+	assert.Equal(t, []string{"id"}, user.Required)
+	assert.NotContains(t, m.master.Components.Schemas, "AuditInfo")
+	assert.Empty(t, m.Warnings())
+}
+
+func TestMerger_StripInternalDanglingRef(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/audit": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/AuditInfo"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"AuditInfo": {"type": "object", "x-internal": true}
+			}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "audit.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:        []config.InputConfig{{InputFile: specPath}},
+		Output:        filepath.Join(tempDir, "merged.json"),
+		StripInternal: true,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	require.Len(t, m.Warnings(), 1)
+	assert.Contains(t, m.Warnings()[0].Message, "#/components/schemas/AuditInfo was removed but is still referenced")
+
+	cfg.Strict = true
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "internal components are still referenced: #/components/schemas/AuditInfo")
+}
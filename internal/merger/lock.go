@@ -0,0 +1,146 @@
+package merger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lockFile pins the content of remote inputs between merges.
+type lockFile struct {
+	Inputs []lockEntry `yaml:"inputs"`
+}
+
+// lockEntry records the content hash of one remote input.
+type lockEntry struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// checkLock verifies fetched remote content against the lock file, recording
+// it when the URL is not locked yet or when the lock is being updated.
+func (m *Merger) checkLock(url string, data []byte) error {
+	if m.cfg.LockFile == "" {
+		return nil
+	}
+
+	if err := m.loadLock(); err != nil {
+		return err
+	}
+	if m.lockFetched == nil {
+		m.lockFetched = make(map[string]bool)
+	}
+	m.lockFetched[url] = true
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	locked, ok := m.locked[url]
+	switch {
+	case ok && locked == hash:
+		return nil
+	case ok && !m.cfg.UpdateLock:
+		return fmt.Errorf("content of %s does not match %s (locked sha256 %s, fetched %s); rerun with --update-lock to accept it",
+			url, m.cfg.LockFile, locked, hash)
+	}
+
+	m.locked[url] = hash
+	m.lockChanged = true
+	return nil
+}
+
+// loadLock reads the lock file into m.locked on first use.
+func (m *Merger) loadLock() error {
+	if m.locked != nil {
+		return nil
+	}
+	locked, err := readLockFile(m.cfg.LockFile)
+	if err != nil {
+		return err
+	}
+	m.locked = locked
+	return nil
+}
+
+// pruneLock drops the entries of URLs not fetched in this run, so an updated
+// lock file only pins the current remote inputs.
+func (m *Merger) pruneLock() error {
+	if err := m.loadLock(); err != nil {
+		return err
+	}
+	for url := range m.locked {
+		if !m.lockFetched[url] {
+			delete(m.locked, url)
+			m.lockChanged = true
+		}
+	}
+	return nil
+}
+
+// readLockFile returns the locked hashes by URL. A missing file is empty.
+func readLockFile(path string) (map[string]string, error) {
+	locked := make(map[string]string)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return locked, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var lock lockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	for _, entry := range lock.Inputs {
+		locked[entry.URL] = entry.SHA256
+	}
+
+	return locked, nil
+}
+
+// writeLockFile saves the lock file if any entry was added or updated. With
+// UpdateLock, entries for URLs not fetched in this run are dropped.
+func (m *Merger) writeLockFile() error {
+	if m.cfg.LockFile != "" && m.cfg.UpdateLock {
+		if err := m.pruneLock(); err != nil {
+			return err
+		}
+	}
+	if !m.lockChanged {
+		return nil
+	}
+
+	urls := make([]string, 0, len(m.locked))
+	for url := range m.locked {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	var lock lockFile
+	for _, url := range urls {
+		lock.Inputs = append(lock.Inputs, lockEntry{URL: url, SHA256: m.locked[url]})
+	}
+
+	data, err := yaml.Marshal(&lock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	header := "# Generated by openapi-merge. Pins the content of remote inputs.\n"
+	if err := os.WriteFile(m.cfg.LockFile, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Updated lock file %s\n", m.cfg.LockFile)
+	}
+
+	return nil
+}
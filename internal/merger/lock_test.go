@@ -0,0 +1,115 @@
+package merger
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_LockFile(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Remote", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(spec))
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	url := server.URL + "/openapi.json"
+	lockPath := filepath.Join(tempDir, "openapi-merge.lock")
+	cfg := &config.Config{
+		Inputs:   []config.InputConfig{{InputFile: url}},
+		Output:   filepath.Join(tempDir, "merged.json"),
+		LockFile: lockPath,
+	}
+
+	t.Run("first run creates the lock", func(t *testing.T) {
+		require.NoError(t, New(cfg, false).Merge())
+
+		locked, err := readLockFile(lockPath)
+		require.NoError(t, err)
+		require.Contains(t, locked, url)
+		assert.Len(t, locked[url], 64)
+	})
+
+	t.Run("unchanged content verifies", func(t *testing.T) {
+		before, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+
+		require.NoError(t, New(cfg, false).Merge())
+
+		after, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	spec = `{
+		"openapi": "3.0.0",
+		"info": {"title": "Remote", "version": "1.1.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+
+	t.Run("changed content is rejected", func(t *testing.T) {
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match")
+		assert.Contains(t, err.Error(), "--update-lock")
+	})
+
+	t.Run("update-lock accepts changed content", func(t *testing.T) {
+		before, err := readLockFile(lockPath)
+		require.NoError(t, err)
+
+		updateCfg := *cfg
+		updateCfg.UpdateLock = true
+		require.NoError(t, New(&updateCfg, false).Merge())
+
+		after, err := readLockFile(lockPath)
+		require.NoError(t, err)
+		assert.NotEqual(t, before[url], after[url])
+
+		// Later runs verify against the updated hash
+		require.NoError(t, New(cfg, false).Merge())
+	})
+
+	t.Run("update-lock drops inputs no longer fetched", func(t *testing.T) {
+		stale := "https://example.com/removed.json"
+		data, err := os.ReadFile(lockPath)
+		require.NoError(t, err)
+		data = append(data, []byte("    - url: "+stale+"\n      sha256: "+strings.Repeat("0", 64)+"\n")...)
+		require.NoError(t, os.WriteFile(lockPath, data, 0644))
+
+		locked, err := readLockFile(lockPath)
+		require.NoError(t, err)
+		require.Contains(t, locked, stale)
+
+		// A plain merge leaves entries it did not fetch alone
+		require.NoError(t, New(cfg, false).Merge())
+		locked, err = readLockFile(lockPath)
+		require.NoError(t, err)
+		assert.Contains(t, locked, stale)
+
+		updateCfg := *cfg
+		updateCfg.UpdateLock = true
+		require.NoError(t, New(&updateCfg, false).Merge())
+
+		locked, err = readLockFile(lockPath)
+		require.NoError(t, err)
+		assert.Equal(t, []string{url}, slices.Sorted(maps.Keys(locked)))
+	})
+}
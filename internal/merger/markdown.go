@@ -0,0 +1,146 @@
+package merger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExportMarkdown runs the merge without writing output and summarizes the
+// result as a Markdown document.
+func (m *Merger) ExportMarkdown() (string, error) {
+	if err := m.build(); err != nil {
+		return "", err
+	}
+	return MarkdownFor(m.master), nil
+}
+
+// MarkdownFor summarizes a specification as Markdown: a section per tag, in
+// the order of the root tags, with a table row per operation, followed by the
+// untagged operations and a catalog of the component schemas. Operations with
+// several tags are listed under each of them.
+func MarkdownFor(spec *openapi3.T) string {
+	var b strings.Builder
+
+	if spec.Info != nil {
+		fmt.Fprintf(&b, "# %s\n\n", markdownLine(spec.Info.Title))
+		if spec.Info.Version != "" {
+			fmt.Fprintf(&b, "Version: %s\n\n", markdownLine(spec.Info.Version))
+		}
+		if spec.Info.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(spec.Info.Description))
+		}
+	}
+
+	// Sections follow the root tags, then any undeclared tags as first seen
+	type section struct {
+		description string
+		rows        []string
+	}
+	sections := make(map[string]*section)
+	var order []string
+	addSection := func(name, description string) *section {
+		if s, ok := sections[name]; ok {
+			return s
+		}
+		s := &section{description: description}
+		sections[name] = s
+		order = append(order, name)
+		return s
+	}
+	for _, tag := range spec.Tags {
+		if tag != nil {
+			addSection(tag.Name, tag.Description)
+		}
+	}
+
+	var untagged []string
+	if spec.Paths != nil {
+		forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+			row := markdownOperationRow(path, method, op)
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, row)
+				return
+			}
+			for _, tag := range op.Tags {
+				s := addSection(tag, "")
+				s.rows = append(s.rows, row)
+			}
+		})
+	}
+
+	writeOperations := func(title, description string, rows []string) {
+		fmt.Fprintf(&b, "## %s\n\n", markdownLine(title))
+		if description != "" {
+			fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(description))
+		}
+		b.WriteString("| Method | Path | Summary |\n|--------|------|---------|\n")
+		for _, row := range rows {
+			b.WriteString(row)
+		}
+		b.WriteString("\n")
+	}
+	for _, name := range order {
+		if s := sections[name]; len(s.rows) > 0 {
+			writeOperations(name, s.description, s.rows)
+		}
+	}
+	if len(untagged) > 0 {
+		writeOperations("Untagged", "", untagged)
+	}
+
+	if spec.Components != nil && len(spec.Components.Schemas) > 0 {
+		b.WriteString("## Schemas\n\n| Schema | Type | Description |\n|--------|------|-------------|\n")
+		for _, name := range sortedKeys(spec.Components.Schemas) {
+			schemaType, description := "", ""
+			if schema := spec.Components.Schemas[name]; schema != nil && schema.Value != nil {
+				schemaType = markdownSchemaType(schema.Value)
+				description = schema.Value.Description
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", name, schemaType, markdownCell(description))
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// markdownOperationRow returns the table row for an operation.
+func markdownOperationRow(path, method string, op *openapi3.Operation) string {
+	summary := op.Summary
+	if summary == "" {
+		summary = op.OperationID
+	}
+	summary = markdownCell(summary)
+	if op.Deprecated {
+		summary = strings.TrimSpace(summary + " (deprecated)")
+	}
+	return fmt.Sprintf("| `%s` | `%s` | %s |\n", method, path, summary)
+}
+
+// markdownSchemaType describes the type of a schema for the schema catalog.
+func markdownSchemaType(schema *openapi3.Schema) string {
+	switch {
+	case schema.Type != nil && len(*schema.Type) > 0:
+		return strings.Join(*schema.Type, ", ")
+	case len(schema.AllOf) > 0:
+		return "allOf"
+	case len(schema.OneOf) > 0:
+		return "oneOf"
+	case len(schema.AnyOf) > 0:
+		return "anyOf"
+	default:
+		return ""
+	}
+}
+
+// markdownLine collapses text onto a single line.
+func markdownLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownCell makes text safe to use in a table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownLine(s), "|", `\|`)
+}
@@ -0,0 +1,74 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_ExportMarkdown(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0.0"},
+		"tags": [{"name": "Users", "description": "User operations"}, {"name": "Orders"}],
+		"paths": {
+			"/users": {
+				"get": {"tags": ["Users"], "summary": "List users", "responses": {"200": {"description": "OK"}}},
+				"post": {"tags": ["Users"], "operationId": "createUser", "responses": {"201": {"description": "Created"}}}
+			},
+			"/orders": {
+				"get": {"tags": ["Orders"], "summary": "List orders | paged", "deprecated": true, "responses": {"200": {"description": "OK"}}}
+			},
+			"/health": {
+				"get": {"summary": "Health check", "responses": {"200": {"description": "OK"}}}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "description": "A registered user"},
+				"Pet": {"oneOf": [{"$ref": "#/components/schemas/User"}]}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "shop.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:   []config.InputConfig{{InputFile: specPath}},
+		Output:   filepath.Join(tempDir, "merged.json"),
+		TagOrder: []string{"Orders", "Users"},
+	}
+	markdown, err := New(cfg, false).ExportMarkdown()
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Merged API\n\n"+
+		"Version: 1.0.0\n\n"+
+		"## Orders\n\n"+
+		"| Method | Path | Summary |\n|--------|------|---------|\n"+
+		"| `GET` | `/orders` | List orders \\| paged (deprecated) |\n\n"+
+		"## Users\n\n"+
+		"User operations\n\n"+
+		"| Method | Path | Summary |\n|--------|------|---------|\n"+
+		"| `GET` | `/users` | List users |\n"+
+		"| `POST` | `/users` | createUser |\n\n"+
+		"## Untagged\n\n"+
+		"| Method | Path | Summary |\n|--------|------|---------|\n"+
+		"| `GET` | `/health` | Health check |\n\n"+
+		"## Schemas\n\n"+
+		"| Schema | Type | Description |\n|--------|------|-------------|\n"+
+		"| `Pet` | oneOf |  |\n"+
+		"| `User` | object | A registered user |\n", markdown)
+
+	// The merged spec is not written
+	_, err = os.Stat(cfg.Output)
+	assert.True(t, os.IsNotExist(err))
+}
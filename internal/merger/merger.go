@@ -2,43 +2,214 @@
 package merger
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/rperez95/openapi-merge/internal/config"
+	"github.com/rperez95/openapi-merge/pkg/config"
 	"gopkg.in/yaml.v3"
 )
 
+// Version is the tool version, used in the default User-Agent for URL fetches
+// and in the output header comment.
+var Version = "dev"
+
 // Merger handles the merging of OpenAPI specifications.
 type Merger struct {
-	cfg     *config.Config
-	verbose bool
-	master  *openapi3.T
+	cfg      *config.Config
+	verbose  bool
+	master   *openapi3.T
+	warnings []Warning
+
+	// sources records which input file each merged operation came from
+	sources map[*openapi3.Operation]string
+
+	// renames records the components merged under a new name
+	renames []ComponentRename
+
+	// inputs summarizes what each input contributed; skipped records the
+	// operations dropped by filters
+	inputs  []InputSummary
+	skipped []SkippedOperation
+
+	// pathRewrites maps input paths to the paths they were merged under
+	pathRewrites []PathRewrite
+
+	// webhooks collects the OpenAPI 3.1 webhooks of the inputs by name
+	webhooks map[string]*openapi3.PathItem
+
+	// componentSources records which input file first defined each schema
+	// and parameter, keyed by "<kind>/<name>"
+	componentSources map[string]string
+
+	// collectConflicts records component collisions in conflicts instead of
+	// failing the merge
+	collectConflicts bool
+	conflicts        []Conflict
+
+	// tagNames indexes the names in master.Tags for constant-time dedup
+	tagNames map[string]bool
+
+	// rootExtensions and infoExtensions collect the x- extensions of the
+	// inputs' root and info objects
+	rootExtensions *extensionSet
+	infoExtensions *extensionSet
+
+	// locked holds the lock file hashes by remote input URL; lockChanged is
+	// set when an entry was added or updated. lockFetched records the URLs
+	// fetched in this run.
+	locked      map[string]string
+	lockChanged bool
+	lockFetched map[string]bool
+
+	// stdout receives the merged spec when the output is "-"
+	stdout io.Writer
+
+	// log receives verbose progress messages. It is standard error when the
+	// merged spec goes to standard output, so the two never mix.
+	log io.Writer
+
+	// sleep waits between attempts to fetch a remote input
+	sleep func(time.Duration)
+
+	// command is the command line recorded in the output header
+	command string
 }
 
 // New creates a new Merger instance.
 func New(cfg *config.Config, verbose bool) *Merger {
+	log := io.Writer(os.Stdout)
+	if cfg.WritesToStdout() {
+		log = os.Stderr
+	}
 	return &Merger{
 		cfg:     cfg,
 		verbose: verbose,
+		stdout:  os.Stdout,
+		log:     log,
+		sleep:   time.Sleep,
 	}
 }
 
+// SetCommand sets the command line recorded in the output header. The
+// header has no command line when it is empty.
+func (m *Merger) SetCommand(command string) {
+	m.command = command
+}
+
 // Merge executes the merge operation.
 func (m *Merger) Merge() error {
+	if err := m.build(); err != nil {
+		return err
+	}
+
+	if m.cfg.StrictRefs {
+		if err := m.verifyRefs(); err != nil {
+			return err
+		}
+	}
+
+	// Write output
+	if err := m.writeOutput(); err != nil {
+		return err
+	}
+
+	// Write operation index
+	if m.cfg.OperationIndex != "" {
+		if err := m.writeOperationIndex(m.cfg.OperationIndex); err != nil {
+			return err
+		}
+	}
+
+	// Write the merge report
+	if m.cfg.ReportFile != "" {
+		if err := m.writeReport(m.cfg.ReportFile); err != nil {
+			return err
+		}
+	}
+
+	// Record remote input hashes
+	if err := m.writeLockFile(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MergeToDocument runs the merge, including reference verification, and
+// returns the merged document instead of writing it. The operation index and
+// report are not written either.
+func (m *Merger) MergeToDocument() (*openapi3.T, error) {
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+
+	if m.cfg.StrictRefs {
+		if err := m.verifyRefs(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.master, nil
+}
+
+// MergeToBytes runs the merge like MergeToDocument and returns the merged
+// document serialized as it would be written, in the given format (json or
+// yaml).
+func (m *Merger) MergeToBytes(format string) ([]byte, error) {
+	var isYAML bool
+	switch format {
+	case config.OutputFormatJSON:
+	case config.OutputFormatYAML:
+		isYAML = true
+	default:
+		return nil, fmt.Errorf("invalid format %q (expected %s or %s)", format, config.OutputFormatJSON, config.OutputFormatYAML)
+	}
+
+	if _, err := m.MergeToDocument(); err != nil {
+		return nil, err
+	}
+	return m.marshalOutput(isYAML)
+}
+
+// build runs the merge pipeline and post-processing, leaving the result in
+// m.master without writing anything.
+func (m *Merger) build() error {
+	m.warnings = nil
+	m.conflicts = nil
+	m.sources = make(map[*openapi3.Operation]string)
+	m.componentSources = make(map[string]string)
+	m.renames = nil
+	m.inputs = nil
+	m.skipped = nil
+	m.pathRewrites = nil
+	m.webhooks = make(map[string]*openapi3.PathItem)
+	m.tagNames = make(map[string]bool)
+	m.rootExtensions = newExtensionSet("root")
+	m.infoExtensions = newExtensionSet("info")
+	m.locked = nil
+	m.lockChanged = false
+	m.lockFetched = nil
+
 	// Initialize master spec
 	m.master = &openapi3.T{
-		OpenAPI: "3.0.3",
+		OpenAPI: m.cfg.OutputOpenAPIVersion(),
 		Info: &openapi3.Info{
 			Title:       "Merged API",
 			Description: "",
@@ -64,35 +235,77 @@ func (m *Merger) Merge() error {
 	// Track merged descriptions for appending
 	var mergedDescriptions []string
 
+	// Info from the input selected by infoMode, if any
+	var baseInfo *openapi3.Info
+
 	// Process each input file
 	for i, input := range m.cfg.Inputs {
 		if m.verbose {
-			fmt.Printf("Processing input %d: %s\n", i+1, input.InputFile)
+			fmt.Fprintf(m.log, "Processing input %d: %s\n", i+1, input.InputFile)
 		}
 
 		// Load and parse the spec
-		spec, err := m.loadSpec(input.InputFile)
+		spec, err := m.loadSpec(input.InputFile, input.Headers)
 		if err != nil {
-			return fmt.Errorf("failed to load %s: %w", input.InputFile, err)
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to load %s: %w", input.InputFile, err)}
+		}
+
+		if err := m.checkRefKinds(spec, input.InputFile); err != nil {
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to validate %s: %w", input.InputFile, err)}
+		}
+
+		if m.cfg.ValidateDefaults {
+			if err := m.validateDefaults(spec, input.InputFile); err != nil {
+				return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to validate %s: %w", input.InputFile, err)}
+			}
+		}
+
+		if spec.Info != nil && m.isBaseInfoInput(i, &input) {
+			info := *spec.Info
+			baseInfo = &info
 		}
 
 		// Apply operation selection filters
 		spec = m.filterOperations(spec, &input)
 
+		// Generate or require operationIds
+		spec, err = m.ensureOperationIDs(spec, &input)
+		if err != nil {
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to filter operations of %s: %w", input.InputFile, err)}
+		}
+
+		// Apply component selection
+		spec, err = m.filterComponents(spec, &input)
+		if err != nil {
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to filter components of %s: %w", input.InputFile, err)}
+		}
+
 		// Apply path modifications
-		spec = m.modifyPaths(spec, &input)
+		spec, err = m.modifyPaths(spec, &input)
+		if err != nil {
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to modify paths of %s: %w", input.InputFile, err)}
+		}
 
 		// Apply parameter modifications
-		spec = m.modifyParameters(spec, &input)
+		spec, err = m.modifyParameters(spec, &input)
+		if err != nil {
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to modify parameters of %s: %w", input.InputFile, err)}
+		}
+
+		// Inject response headers
+		spec = m.injectResponseHeaders(spec, &input)
+
+		// Rename path variables to their canonical names
+		spec = m.normalizePathVariables(spec, input.InputFile)
 
 		// Handle conflicts with dispute prefix
-		if input.Dispute != nil && input.Dispute.Prefix != "" {
-			spec = m.applyDisputePrefix(spec, input.Dispute.Prefix)
+		if input.Dispute.Active() {
+			spec = m.applyDispute(spec, &input)
 		}
 
 		// Merge into master
 		if err := m.mergeSpec(spec, &input); err != nil {
-			return fmt.Errorf("failed to merge %s: %w", input.InputFile, err)
+			return &InputError{Source: input.InputFile, Err: fmt.Errorf("failed to merge %s: %w", input.InputFile, err)}
 		}
 
 		// Handle description appending
@@ -105,22 +318,131 @@ func (m *Merger) Merge() error {
 	}
 
 	// Apply post-processing
-	m.applyOverrides(mergedDescriptions)
+	if baseInfo != nil {
+		m.master.Info = baseInfo
+	}
+	m.master.Extensions = m.rootExtensions.Values()
+	if len(m.webhooks) > 0 {
+		if m.master.Extensions == nil {
+			m.master.Extensions = make(map[string]interface{})
+		}
+		m.master.Extensions[webhooksKey] = m.webhooks
+	}
+	m.master.Info.Extensions = m.infoExtensions.Values()
+	if err := m.applyOverrides(mergedDescriptions); err != nil {
+		return err
+	}
+	m.applySecuritySchemeAliases()
+
+	if m.cfg.StripInternal {
+		if err := m.stripInternal(); err != nil {
+			return err
+		}
+	}
+
+	if m.cfg.DefaultErrorResponse != nil {
+		if err := m.injectDefaultErrorResponse(); err != nil {
+			return err
+		}
+	}
+
+	m.applyResponseView()
+
+	if err := m.checkUndeclaredTags(); err != nil {
+		return err
+	}
+
+	if m.cfg.AnnotateTagCounts {
+		m.annotateTagCounts()
+	}
+
+	m.generateMissingOperationIDs()
+
+	if err := m.applyOperationIDStyle(); err != nil {
+		return err
+	}
+
+	m.applyOperationPolicies()
+
+	if err := m.checkCoverage(); err != nil {
+		return err
+	}
+
+	// Bring schemas in line with the output version
+	if m.targetsOpenAPI31() {
+		upgradeSchemasTo31(m.master)
+	} else {
+		downconvertNullableTypes(m.master)
+	}
+
+	if m.cfg.DefaultAdditionalProperties != nil {
+		applyDefaultAdditionalProperties(m.master, *m.cfg.DefaultAdditionalProperties)
+	}
+
+	if m.cfg.HoistExamples {
+		m.hoistExamples()
+	}
+
+	if m.cfg.MaxInlineDepth > 0 {
+		m.extractDeepSchemas()
+	}
+
+	m.truncateDescriptions()
+
+	if m.cfg.DeduplicateSchemas {
+		m.deduplicateSchemas()
+	}
+
+	if err := m.applyRefRewrites(); err != nil {
+		return err
+	}
+
+	// Prune last, once every reference is final
+	if m.cfg.PruneUnusedComponents {
+		removed, err := pruneUnusedComponents(m.master)
+		if err != nil {
+			return err
+		}
+		if m.verbose && len(removed) > 0 {
+			fmt.Fprintf(m.log, "Pruned %d unused components: %s\n", len(removed), strings.Join(removed, ", "))
+		}
+	}
+
 	m.sortOutput()
 
-	// Write output
-	return m.writeOutput()
+	return nil
+}
+
+// isBaseInfoInput reports whether the input at index i provides the base Info
+// according to the configured infoMode.
+func (m *Merger) isBaseInfoInput(i int, input *config.InputConfig) bool {
+	switch m.cfg.InfoMode {
+	case config.InfoModeFirst:
+		return i == 0
+	case config.InfoModeLast:
+		return i == len(m.cfg.Inputs)-1
+	case config.InfoModePrimary:
+		return input.Primary
+	default:
+		return false
+	}
 }
 
 // loadSpec loads and parses an OpenAPI specification, converting OAS2 to OAS3 if needed.
-// Supports both local files and HTTP/HTTPS URLs.
-func (m *Merger) loadSpec(filePath string) (*openapi3.T, error) {
+// Supports both local files and HTTP/HTTPS URLs; headers are added to the
+// requests for a URL.
+func (m *Merger) loadSpec(filePath string, headers map[string]string) (*openapi3.T, error) {
 	var data []byte
 	var err error
 	var ext string
 
 	if config.IsURL(filePath) {
-		data, ext, err = m.fetchFromURL(filePath)
+		data, ext, err = m.fetchFromURL(filePath, headers)
+		if err == nil {
+			if err := m.checkLock(filePath, data); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		data, err = os.ReadFile(filePath)
 		ext = strings.ToLower(filepath.Ext(filePath))
@@ -134,6 +456,10 @@ func (m *Merger) loadSpec(filePath string) (*openapi3.T, error) {
 	var raw map[string]interface{}
 
 	if ext == ".yaml" || ext == ".yml" {
+		// Hand the loader expanded content rather than anchors and merge keys
+		if data, err = expandYAMLAliases(data); err != nil {
+			return nil, err
+		}
 		if err := yaml.Unmarshal(data, &raw); err != nil {
 			return nil, fmt.Errorf("failed to parse YAML: %w", err)
 		}
@@ -143,28 +469,73 @@ func (m *Merger) loadSpec(filePath string) (*openapi3.T, error) {
 		}
 	}
 
+	// Report every broken internal reference at its source; the loader would
+	// only name the first one. Outside strict mode they are kept as written.
+	var dangling map[string]bool
+	if problems := unresolvedLocalRefs(stringKeys(raw)); len(problems) > 0 {
+		if m.cfg.Strict {
+			return nil, fmt.Errorf("dangling references: %s", strings.Join(problems, ", "))
+		}
+		m.warnf(filePath, "dangling references: %s", strings.Join(problems, ", "))
+		dangling = make(map[string]bool)
+		for _, use := range unresolvedLocalRefUses(stringKeys(raw)) {
+			dangling[use.Ref] = true
+		}
+	}
+
+	// The loader only understands the OpenAPI 3.0 boolean exclusive bounds
+	if isOpenAPI31(raw) {
+		lowerExclusiveBounds(raw)
+		if data, err = json.Marshal(stringKeys(raw)); err != nil {
+			return nil, fmt.Errorf("failed to normalize OpenAPI 3.1 document: %w", err)
+		}
+	}
+
 	// Check for Swagger 2.0
 	if swagger, ok := raw["swagger"].(string); ok && strings.HasPrefix(swagger, "2.") {
 		if m.verbose {
-			fmt.Printf("  Detected Swagger 2.0, converting to OpenAPI 3.0\n")
+			fmt.Fprintf(m.log, "  Detected Swagger 2.0, converting to OpenAPI 3.0\n")
+		}
+		return m.convertSwagger2ToOpenAPI3(filePath, data, ext)
+	}
+
+	// Copy externally referenced components into the input
+	if m.cfg.BundleExternalRefs {
+		if data, err = m.bundleExternalRefs(filePath, headers, raw); err != nil {
+			return nil, err
 		}
-		return m.convertSwagger2ToOpenAPI3(data, ext)
 	}
 
 	// Load as OpenAPI 3.x
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
+	if m.cfg.KeepExternalRefs {
+		if err := keepExternalRefs(loader, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dangling) > 0 {
+		if data, err = stubDanglingRefs(loader, data, dangling); err != nil {
+			return nil, err
+		}
+	}
 
 	spec, err := loader.LoadFromData(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
 	}
+	if len(dangling) > 0 {
+		restoreDanglingRefs(spec)
+	}
 
-	// Validate the spec
+	// Validate the spec. The validator only knows OpenAPI 3.0, so 3.1 inputs
+	// kept as 3.1 would be flagged for every type array.
+	if isOpenAPI31(raw) && m.targetsOpenAPI31() {
+		return spec, nil
+	}
 	if err := spec.Validate(context.Background()); err != nil {
-		if m.verbose {
-			fmt.Printf("  Warning: Validation issues: %v\n", err)
-		}
+		m.validationWarnf(filePath, "validation issues: %v", err)
 	}
 
 	return spec, nil
@@ -172,54 +543,87 @@ func (m *Merger) loadSpec(filePath string) (*openapi3.T, error) {
 
 // fetchFromURL fetches data from an HTTP/HTTPS URL.
 // Automatically converts GitHub blob URLs to raw URLs.
-// Uses GITHUB_TOKEN environment variable for authentication with GitHub URLs.
-func (m *Merger) fetchFromURL(url string) ([]byte, string, error) {
+// Uses the GitHub token (see githubToken) for authentication with GitHub URLs
+// unless headers set Authorization; header values expand ${ENV} variables.
+func (m *Merger) fetchFromURL(url string, headers map[string]string) ([]byte, string, error) {
 	// Convert GitHub blob URLs to raw URLs
 	url = convertGitHubURL(url)
 
 	if m.verbose {
-		fmt.Printf("  Fetching from URL: %s\n", url)
+		fmt.Fprintf(m.log, "  Fetching from URL: %s\n", url)
+	}
+
+	data, err := m.getWithRetries(url, headers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Determine extension from URL
+	ext := strings.ToLower(filepath.Ext(url))
+	// Handle URLs with query params
+	if idx := strings.Index(ext, "?"); idx != -1 {
+		ext = ext[:idx]
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	return data, ext, nil
+}
+
+// newRequest creates an HTTP request with the User-Agent header and, for
+// GitHub URLs, GitHub token authentication.
+func (m *Merger) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
+	req.Header.Set("User-Agent", m.userAgent())
+
 	// Add GitHub token authentication if available and URL is GitHub
 	if isGitHubURL(url) {
-		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		token, source, err := m.githubToken()
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
 			req.Header.Set("Authorization", "token "+token)
 			if m.verbose {
-				fmt.Printf("  Using GITHUB_TOKEN for authentication\n")
+				fmt.Fprintf(m.log, "  Using %s for authentication\n", source)
 			}
 		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch URL: %w", err)
+	return req, nil
+}
+
+// githubToken returns the GitHub token and where it came from: the
+// GITHUB_TOKEN variable, else the file named by GITHUB_TOKEN_FILE, else the
+// configured auth.tokenFile. Token files are trimmed of surrounding whitespace.
+func (m *Merger) githubToken() (string, string, error) {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token, "GITHUB_TOKEN", nil
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, resp.Status)
+	path, source := os.Getenv("GITHUB_TOKEN_FILE"), "GITHUB_TOKEN_FILE"
+	if path == "" && m.cfg.Auth != nil {
+		path, source = m.cfg.Auth.TokenFile, "auth.tokenFile"
+	}
+	if path == "" {
+		return "", "", nil
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+		return "", "", fmt.Errorf("failed to read token file from %s: %w", source, err)
 	}
+	return strings.TrimSpace(string(data)), source, nil
+}
 
-	// Determine extension from URL
-	ext := strings.ToLower(filepath.Ext(url))
-	// Handle URLs with query params
-	if idx := strings.Index(ext, "?"); idx != -1 {
-		ext = ext[:idx]
+// userAgent returns the User-Agent header value for URL fetches.
+func (m *Merger) userAgent() string {
+	if m.cfg.Fetch != nil && m.cfg.Fetch.UserAgent != "" {
+		return m.cfg.Fetch.UserAgent
 	}
-
-	return data, ext, nil
+	return "openapi-merge/" + Version
 }
 
 // isGitHubURL checks if a URL is a GitHub URL that can use token auth.
@@ -256,7 +660,7 @@ func convertGitHubURL(url string) string {
 }
 
 // convertSwagger2ToOpenAPI3 converts a Swagger 2.0 spec to OpenAPI 3.0.
-func (m *Merger) convertSwagger2ToOpenAPI3(data []byte, ext string) (*openapi3.T, error) {
+func (m *Merger) convertSwagger2ToOpenAPI3(filePath string, data []byte, ext string) (*openapi3.T, error) {
 	// Parse Swagger 2.0 spec
 	var swagger2Doc openapi2.T
 
@@ -279,6 +683,18 @@ func (m *Merger) convertSwagger2ToOpenAPI3(data []byte, ext string) (*openapi3.T
 	// Ensure OpenAPI version is set to 3.0
 	spec.OpenAPI = "3.0.3"
 
+	// Drop servers synthesized from host/basePath/schemes if requested
+	if m.cfg.StripConvertedServers && len(spec.Servers) > 0 {
+		if m.verbose {
+			fmt.Fprintf(m.log, "  Stripping %d server(s) derived from Swagger 2.0 host\n", len(spec.Servers))
+		}
+		spec.Servers = nil
+	}
+
+	if m.cfg.FormDataMode != "" {
+		m.applyFormDataMode(spec, filePath)
+	}
+
 	return spec, nil
 }
 
@@ -295,14 +711,14 @@ func (m *Merger) filterOperations(spec *openapi3.T, input *config.InputConfig) *
 
 	pathsToRemove := make([]string, 0)
 
-	for path, pathItem := range spec.Paths.Map() {
+	for _, path := range sortedPaths(spec.Paths) {
+		pathItem := spec.Paths.Value(path)
 		if pathItem == nil {
 			continue
 		}
 
-		operations := getOperationsMap(pathItem)
-
-		for method, op := range operations {
+		for _, method := range httpMethods {
+			op := pathItem.GetOperation(method)
 			if op == nil {
 				continue
 			}
@@ -312,6 +728,7 @@ func (m *Merger) filterOperations(spec *openapi3.T, input *config.InputConfig) *
 			if !shouldInclude {
 				// Remove the operation
 				removeOperation(pathItem, method)
+				m.recordSkipped(input.InputFile, method, path, "operationSelection")
 			}
 		}
 
@@ -384,25 +801,107 @@ func (m *Merger) shouldIncludeOperation(path, method string, op *openapi3.Operat
 		}
 	}
 
+	// Check includeOperationIds; an operation without an operationId never matches
+	if len(sel.IncludeOperationIDs) > 0 {
+		matched := false
+		for _, pattern := range sel.IncludeOperationIDs {
+			if op.OperationID != "" && matchGlob(pattern, op.OperationID) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	// Check excludeOperationIds
+	if len(sel.ExcludeOperationIDs) > 0 && op.OperationID != "" {
+		for _, pattern := range sel.ExcludeOperationIDs {
+			if matchGlob(pattern, op.OperationID) {
+				return false
+			}
+		}
+	}
+
+	// Check includeByExtension
+	if len(sel.IncludeByExtension) > 0 {
+		matched := false
+		for _, filter := range sel.IncludeByExtension {
+			if matchExtensionFilter(op.Extensions, filter) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	// Check excludeByExtension
+	if len(sel.ExcludeByExtension) > 0 {
+		for _, filter := range sel.ExcludeByExtension {
+			if matchExtensionFilter(op.Extensions, filter) {
+				return false
+			}
+		}
+	}
+
+	// Check deprecation
+	if sel.ExcludeDeprecated && op.Deprecated {
+		return false
+	}
+	if sel.IncludeOnlyDeprecated && !op.Deprecated {
+		return false
+	}
+
 	return true
 }
 
 // modifyPaths applies path modifications (stripStart, prepend).
-func (m *Merger) modifyPaths(spec *openapi3.T, input *config.InputConfig) *openapi3.T {
+func (m *Merger) modifyPaths(spec *openapi3.T, input *config.InputConfig) (*openapi3.T, error) {
 	if input.PathModification == nil {
-		return spec
+		return spec, nil
 	}
 
 	if spec.Paths == nil {
-		return spec
+		return spec, nil
 	}
 
 	mod := input.PathModification
+	patterns := make([]*regexp.Regexp, len(mod.Replace))
+	for i, rule := range mod.Replace {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathModification.replace[%d] regex: %w", i, err)
+		}
+		patterns[i] = re
+	}
+
 	newPaths := openapi3.NewPaths()
+	newPaths.Extensions = spec.Paths.Extensions
 
-	for path, pathItem := range spec.Paths.Map() {
+	for _, path := range sortedPaths(spec.Paths) {
+		pathItem := spec.Paths.Value(path)
 		newPath := path
 
+		// Apply regex replacements
+		if len(patterns) > 0 {
+			replaced := path
+			for i, re := range patterns {
+				replaced = re.ReplaceAllString(replaced, mod.Replace[i].Replacement)
+			}
+			if slices.Equal(pathVariables(replaced), pathVariables(path)) {
+				newPath = replaced
+			} else {
+				msg := fmt.Sprintf("pathModification.replace rewrites %s to %s, which changes its path parameters", path, replaced)
+				if m.cfg.Strict {
+					return nil, errors.New(msg)
+				}
+				m.warnf(input.InputFile, "%s; keeping %s", msg, path)
+			}
+		}
+
 		// Apply stripStart
 		if mod.StripStart != "" && strings.HasPrefix(newPath, mod.StripStart) {
 			newPath = strings.TrimPrefix(newPath, mod.StripStart)
@@ -418,18 +917,27 @@ func (m *Merger) modifyPaths(spec *openapi3.T, input *config.InputConfig) *opena
 			newPath = "/" + newPath
 		}
 
-		// Update refs in pathItem to reflect path change
+		m.recordPathRewrite(input.InputFile, path, newPath)
+
+		// Two paths of the same input may now coincide
+		if existing := newPaths.Value(newPath); existing != nil {
+			mergePathItem(existing, pathItem)
+			continue
+		}
+
+		// Callback expressions inside the path item resolve to URLs rather
+		// than server paths, so they are deliberately left unchanged
 		newPaths.Set(newPath, pathItem)
 	}
 
 	spec.Paths = newPaths
-	return spec
+	return spec, nil
 }
 
 // modifyParameters applies parameter modifications (include/exclude).
-func (m *Merger) modifyParameters(spec *openapi3.T, input *config.InputConfig) *openapi3.T {
+func (m *Merger) modifyParameters(spec *openapi3.T, input *config.InputConfig) (*openapi3.T, error) {
 	if spec.Paths == nil {
-		return spec
+		return spec, nil
 	}
 
 	for _, pathItem := range spec.Paths.Map() {
@@ -439,30 +947,22 @@ func (m *Merger) modifyParameters(spec *openapi3.T, input *config.InputConfig) *
 
 		operations := getOperationsMap(pathItem)
 
-		for _, op := range operations {
+		for method, op := range operations {
 			if op == nil {
 				continue
 			}
 
 			// Inject extra parameters
-			if len(input.IncludeExtraParameters) > 0 {
-				for _, paramCfg := range input.IncludeExtraParameters {
-					param := paramCfg.ToOpenAPI3Parameter()
-					// Check if parameter already exists
-					exists := false
-					for _, existingParam := range op.Parameters {
-						if existingParam.Value != nil &&
-							existingParam.Value.Name == param.Name &&
-							existingParam.Value.In == param.In {
-							exists = true
-							break
-						}
-					}
-					if !exists {
-						op.Parameters = append(op.Parameters, &openapi3.ParameterRef{
-							Value: param,
-						})
-					}
+			for _, paramCfg := range input.IncludeExtraParameters {
+				if !paramCfg.AppliesTo(method) {
+					continue
+				}
+				param, err := injectedParameter(paramCfg, spec.Components, m.master.Components)
+				if err != nil {
+					return nil, err
+				}
+				if !hasParameter(op.Parameters, param.Value.Name, param.Value.In) {
+					op.Parameters = append(op.Parameters, param)
 				}
 			}
 
@@ -493,11 +993,80 @@ func (m *Merger) modifyParameters(spec *openapi3.T, input *config.InputConfig) *
 		}
 	}
 
+	return spec, nil
+}
+
+// injectResponseHeaders adds configured headers to matching operation responses.
+// Headers already defined on a response are left untouched. A referenced
+// response gets them in its component, once, so every operation using it
+// shows them.
+func (m *Merger) injectResponseHeaders(spec *openapi3.T, input *config.InputConfig) *openapi3.T {
+	if len(input.IncludeResponseHeaders) == 0 || spec.Paths == nil {
+		return spec
+	}
+
+	for _, pathItem := range spec.Paths.Map() {
+		if pathItem == nil {
+			continue
+		}
+
+		for _, op := range getOperationsMap(pathItem) {
+			if op == nil || op.Responses == nil {
+				continue
+			}
+
+			for code, respRef := range op.Responses.Map() {
+				resp := headerTarget(spec, respRef)
+				if resp == nil {
+					continue
+				}
+
+				for _, headerCfg := range input.IncludeResponseHeaders {
+					if !matchStatusCodes(headerCfg.StatusCodes, code) {
+						continue
+					}
+					if _, exists := resp.Headers[headerCfg.Name]; exists {
+						continue
+					}
+					if resp.Headers == nil {
+						resp.Headers = make(openapi3.Headers)
+					}
+					resp.Headers[headerCfg.Name] = &openapi3.HeaderRef{
+						Value: headerCfg.ToOpenAPI3Header(),
+					}
+				}
+			}
+		}
+	}
+
 	return spec
 }
 
-// applyDisputePrefix applies prefix to all component names and updates refs.
-func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T {
+// headerTarget returns the response that injected headers for respRef go
+// into: the response itself, or the component it references. References to
+// other documents are left alone.
+func headerTarget(spec *openapi3.T, respRef *openapi3.ResponseRef) *openapi3.Response {
+	if respRef == nil {
+		return nil
+	}
+	if respRef.Ref == "" {
+		return respRef.Value
+	}
+	name, ok := strings.CutPrefix(respRef.Ref, componentsRefPrefix+"responses/")
+	if !ok || spec.Components == nil {
+		return nil
+	}
+	component := spec.Components.Responses[unescapePointerToken(name)]
+	if component == nil || component.Ref != "" {
+		return nil
+	}
+	return component.Value
+}
+
+// applyDispute renames all components with the dispute prefix and suffix and
+// updates refs.
+func (m *Merger) applyDispute(spec *openapi3.T, input *config.InputConfig) *openapi3.T {
+	dispute := input.Dispute
 	if spec.Components == nil {
 		return spec
 	}
@@ -509,10 +1078,11 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 	if len(spec.Components.Schemas) > 0 {
 		newSchemas := make(openapi3.Schemas)
 		for name, schema := range spec.Components.Schemas {
-			newName := prefix + name
+			newName := dispute.Rename(name)
 			renames["#/components/schemas/"+name] = "#/components/schemas/" + newName
 			renames["#/definitions/"+name] = "#/components/schemas/" + newName
 			newSchemas[newName] = schema
+			m.recordRename("schemas", schema, name, newName, input.InputFile)
 		}
 		spec.Components.Schemas = newSchemas
 	}
@@ -521,9 +1091,10 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 	if len(spec.Components.Responses) > 0 {
 		newResponses := make(openapi3.ResponseBodies)
 		for name, resp := range spec.Components.Responses {
-			newName := prefix + name
+			newName := dispute.Rename(name)
 			renames["#/components/responses/"+name] = "#/components/responses/" + newName
 			newResponses[newName] = resp
+			m.recordRename("responses", resp, name, newName, input.InputFile)
 		}
 		spec.Components.Responses = newResponses
 	}
@@ -532,9 +1103,10 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 	if len(spec.Components.Parameters) > 0 {
 		newParams := make(openapi3.ParametersMap)
 		for name, param := range spec.Components.Parameters {
-			newName := prefix + name
+			newName := dispute.Rename(name)
 			renames["#/components/parameters/"+name] = "#/components/parameters/" + newName
 			newParams[newName] = param
+			m.recordRename("parameters", param, name, newName, input.InputFile)
 		}
 		spec.Components.Parameters = newParams
 	}
@@ -543,9 +1115,10 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 	if len(spec.Components.SecuritySchemes) > 0 {
 		newSchemes := make(openapi3.SecuritySchemes)
 		for name, scheme := range spec.Components.SecuritySchemes {
-			newName := prefix + name
+			newName := dispute.Rename(name)
 			renames["#/components/securitySchemes/"+name] = "#/components/securitySchemes/" + newName
 			newSchemes[newName] = scheme
+			m.recordRename("securitySchemes", scheme, name, newName, input.InputFile)
 		}
 		spec.Components.SecuritySchemes = newSchemes
 	}
@@ -554,13 +1127,26 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 	if len(spec.Components.RequestBodies) > 0 {
 		newBodies := make(openapi3.RequestBodies)
 		for name, body := range spec.Components.RequestBodies {
-			newName := prefix + name
+			newName := dispute.Rename(name)
 			renames["#/components/requestBodies/"+name] = "#/components/requestBodies/" + newName
 			newBodies[newName] = body
+			m.recordRename("requestBodies", body, name, newName, input.InputFile)
 		}
 		spec.Components.RequestBodies = newBodies
 	}
 
+	// Rename path items (OpenAPI 3.1)
+	if pathItems := componentPathItems(spec.Components); len(pathItems) > 0 {
+		newPathItems := make(map[string]*openapi3.PathItem)
+		for name, pathItem := range pathItems {
+			newName := dispute.Rename(name)
+			renames["#/components/pathItems/"+name] = "#/components/pathItems/" + newName
+			newPathItems[newName] = pathItem
+			m.recordRename("pathItems", pathItem, name, newName, input.InputFile)
+		}
+		setComponentPathItems(spec.Components, newPathItems)
+	}
+
 	// Update all $ref references
 	updateRefs(spec, renames)
 
@@ -569,115 +1155,242 @@ func (m *Merger) applyDisputePrefix(spec *openapi3.T, prefix string) *openapi3.T
 
 // mergeSpec merges a processed spec into the master spec.
 func (m *Merger) mergeSpec(spec *openapi3.T, input *config.InputConfig) error {
+	// Drop operations from earlier inputs before adding this input's own
+	if len(input.RemovePaths) > 0 {
+		m.removePaths(input)
+	}
+
+	if input.OperationIDPrefix != "" {
+		prefixOperationIDs(spec, input.OperationIDPrefix)
+	}
+
+	m.recordInput(spec, input.InputFile)
+
 	// Merge paths
-	if spec.Paths != nil {
-		for path, pathItem := range spec.Paths.Map() {
+	if spec.Paths != nil && input.OverlayOnly {
+		m.overlayPaths(spec.Paths)
+	} else if spec.Paths != nil {
+		for _, path := range sortedPaths(spec.Paths) {
+			pathItem := spec.Paths.Value(path)
+
+			// Record provenance before merging; operations dropped by
+			// first-wins never reach the master, so this is harmless for them
+			for _, op := range getOperationsMap(pathItem) {
+				if op != nil {
+					m.sources[op] = input.InputFile
+				}
+			}
+
 			existingPath := m.master.Paths.Find(path)
 			if existingPath != nil {
-				// Merge operations into existing path
-				mergePathItem(existingPath, pathItem)
+				// Merge operations into existing path, resolving duplicates that drifted
+				for _, method := range mergePathItem(existingPath, pathItem) {
+					if err := m.resolvePathConflict(path, method, existingPath, pathItem, input); err != nil {
+						return err
+					}
+				}
 			} else {
 				m.master.Paths.Set(path, pathItem)
 			}
 		}
 	}
 
+	if err := m.mergeWebhooks(spec, input); err != nil {
+		return err
+	}
+
 	// Merge components
 	if spec.Components != nil {
-		if err := m.mergeComponents(spec.Components, input); err != nil {
+		if err := m.mergeComponents(spec, input); err != nil {
 			return err
 		}
 	}
 
-	// Merge tags
-	if len(spec.Tags) > 0 {
-		for _, tag := range spec.Tags {
-			if !m.hasTag(tag.Name) {
-				m.master.Tags = append(m.master.Tags, tag)
+	// Collect servers
+	if m.cfg.ServersMode == config.ServersModeUnion {
+		for _, server := range spec.Servers {
+			if server == nil {
+				continue
+			}
+			existing := m.findServer(server.URL)
+			if existing == nil {
+				m.master.Servers = append(m.master.Servers, server)
+				continue
+			}
+			if err := m.mergeServerVariables(existing, server, input.InputFile); err != nil {
+				return err
 			}
 		}
 	}
 
-	return nil
-}
-
-// mergeComponents merges components from spec into master.
-func (m *Merger) mergeComponents(components *openapi3.Components, input *config.InputConfig) error {
-	hasDisputePrefix := input.Dispute != nil && input.Dispute.Prefix != ""
+	// Collect global security
+	if m.cfg.SecurityMergeStrategy == config.SecurityMergeStrategyMerge {
+		m.master.Security = appendSecurityRequirements(m.master.Security, inputSecurity(spec, input))
+	}
 
-	// Merge schemas
-	for name, schema := range components.Schemas {
-		if existing, ok := m.master.Components.Schemas[name]; ok {
-			if !schemasEqual(existing, schema) && !hasDisputePrefix {
-				return fmt.Errorf("schema collision for '%s' without dispute prefix", name)
-			}
-			// Skip if exact match or has dispute prefix (already renamed)
-			continue
+	// Collect root and info extensions
+	if err := m.mergeExtensions(m.rootExtensions, spec.Extensions, input.InputFile); err != nil {
+		return err
+	}
+	if spec.Info != nil {
+		if err := m.mergeExtensions(m.infoExtensions, spec.Info.Extensions, input.InputFile); err != nil {
+			return err
 		}
-		m.master.Components.Schemas[name] = schema
 	}
 
-	// Merge responses
-	for name, resp := range components.Responses {
-		if _, ok := m.master.Components.Responses[name]; !ok {
-			m.master.Components.Responses[name] = resp
+	// Merge tags by name according to tagMergeStrategy
+	for _, tag := range spec.Tags {
+		if tag != nil {
+			m.mergeTag(tag)
 		}
 	}
 
-	// Merge parameters
-	for name, param := range components.Parameters {
-		if _, ok := m.master.Components.Parameters[name]; !ok {
-			m.master.Components.Parameters[name] = param
+	return nil
+}
+
+// removePaths deletes the operations of the accumulated master that match the
+// input's removePaths filters. Paths left without operations are dropped, and
+// filters that match nothing are reported as warnings.
+func (m *Merger) removePaths(input *config.InputConfig) {
+	matched := make([]bool, len(input.RemovePaths))
+	for _, path := range sortedPaths(m.master.Paths) {
+		pathItem := m.master.Paths.Value(path)
+		for _, method := range httpMethods {
+			op := pathItem.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			for i, filter := range input.RemovePaths {
+				if !matchPathFilter(path, method, filter) {
+					continue
+				}
+				matched[i] = true
+				removeOperation(pathItem, method)
+				delete(m.sources, op)
+				if m.verbose {
+					fmt.Fprintf(m.log, "  Removed %s %s\n", method, path)
+				}
+				break
+			}
+		}
+		if isPathItemEmpty(pathItem) {
+			m.master.Paths.Delete(path)
 		}
 	}
 
-	// Merge security schemes
-	for name, scheme := range components.SecuritySchemes {
-		if _, ok := m.master.Components.SecuritySchemes[name]; !ok {
-			m.master.Components.SecuritySchemes[name] = scheme
+	for i, filter := range input.RemovePaths {
+		if !matched[i] {
+			m.warnf(input.InputFile, "removePaths entry %q matched no merged operation", strings.TrimSpace(filter.Method+" "+filter.Path))
 		}
 	}
+}
 
-	// Merge request bodies
-	for name, body := range components.RequestBodies {
-		if _, ok := m.master.Components.RequestBodies[name]; !ok {
-			m.master.Components.RequestBodies[name] = body
+// overlayPaths patches operations already in the master with the parameters
+// and responses of an overlay input. Paths and operations that are not yet
+// present are skipped.
+func (m *Merger) overlayPaths(paths *openapi3.Paths) {
+	for _, path := range sortedPaths(paths) {
+		existingPath := m.master.Paths.Find(path)
+		if existingPath == nil {
+			if m.verbose {
+				fmt.Fprintf(m.log, "  Overlay: skipping path %s (not in earlier inputs)\n", path)
+			}
+			continue
 		}
-	}
 
-	// Merge examples
-	for name, example := range components.Examples {
-		if _, ok := m.master.Components.Examples[name]; !ok {
-			m.master.Components.Examples[name] = example
+		pathItem := paths.Value(path)
+		existingOps := getOperationsMap(existingPath)
+		for _, method := range httpMethods {
+			op := getOperationsMap(pathItem)[method]
+			if op == nil {
+				continue
+			}
+			if existingOps[method] == nil {
+				if m.verbose {
+					fmt.Fprintf(m.log, "  Overlay: skipping %s %s (not in earlier inputs)\n", method, path)
+				}
+				continue
+			}
+			overlayOperation(existingOps[method], op)
 		}
+
+		mergePathItemParameters(existingPath, pathItem)
 	}
+}
 
-	// Merge headers
-	for name, header := range components.Headers {
-		if _, ok := m.master.Components.Headers[name]; !ok {
-			m.master.Components.Headers[name] = header
+// mergeComponents merges the components of spec into master, resolving
+// same-named components that differ by the per-type conflict policy.
+func (m *Merger) mergeComponents(spec *openapi3.T, input *config.InputConfig) error {
+	components := spec.Components
+	// Union conflicting scalar enums first; the rest go through the policy
+	schemas := maps.Clone(components.Schemas)
+	if m.cfg.SchemaConflict == config.SchemaConflictMergeEnums && !input.Dispute.Active() {
+		for _, name := range sortedKeys(schemas) {
+			existing, schema := m.master.Components.Schemas[name], schemas[name]
+			if existing == nil || schemasEqual(existing, schema) || !isScalarEnum(existing) || !isScalarEnum(schema) {
+				continue
+			}
+			if err := mergeEnums(existing.Value, schema.Value); err != nil {
+				return fmt.Errorf("cannot merge enum schema '%s': %w", name, err)
+			}
+			delete(schemas, name)
 		}
 	}
 
-	// Merge links
-	for name, link := range components.Links {
-		if _, ok := m.master.Components.Links[name]; !ok {
-			m.master.Components.Links[name] = link
-		}
+	renames := make(map[string]string)
+	collect := func(r map[string]string, err error) error {
+		maps.Copy(renames, r)
+		return err
 	}
 
-	// Merge callbacks
-	for name, callback := range components.Callbacks {
-		if _, ok := m.master.Components.Callbacks[name]; !ok {
-			m.master.Components.Callbacks[name] = callback
+	c := m.master.Components
+	if err := collect(mergeComponentMap(m, "schemas", c.Schemas, schemas, schemasEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "responses", c.Responses, components.Responses, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "parameters", c.Parameters, components.Parameters, parametersEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "securitySchemes", c.SecuritySchemes, components.SecuritySchemes, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "requestBodies", c.RequestBodies, components.RequestBodies, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "examples", c.Examples, components.Examples, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "headers", c.Headers, components.Headers, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "links", c.Links, components.Links, jsonEqual, input)); err != nil {
+		return err
+	}
+	if err := collect(mergeComponentMap(m, "callbacks", c.Callbacks, components.Callbacks, jsonEqual, input)); err != nil {
+		return err
+	}
+	if pathItems := componentPathItems(components); len(pathItems) > 0 {
+		dest := componentPathItems(c)
+		if dest == nil {
+			dest = make(map[string]*openapi3.PathItem)
+			setComponentPathItems(c, dest)
+		}
+		if err := collect(mergeComponentMap(m, "pathItems", dest, pathItems, jsonEqual, input)); err != nil {
+			return err
 		}
 	}
 
+	// Point the input's references at components renamed by the prefix policy
+	updateRefs(spec, renames)
+	renameSecuritySchemes(spec, renames)
+
 	return nil
 }
 
 // applyOverrides applies configuration overrides to the master spec.
-func (m *Merger) applyOverrides(mergedDescriptions []string) {
+func (m *Merger) applyOverrides(mergedDescriptions []string) error {
 	// Apply global basePath to all paths
 	if m.cfg.BasePath != "" {
 		m.applyBasePath()
@@ -719,7 +1432,20 @@ func (m *Merger) applyOverrides(mergedDescriptions []string) {
 
 	// Apply servers override
 	if len(m.cfg.Servers) > 0 {
-		m.master.Servers = config.ToOpenAPI3Servers(m.cfg.Servers)
+		servers := config.ToOpenAPI3Servers(m.cfg.Servers)
+		if m.cfg.ServersMode == config.ServersModeUnion {
+			// Config servers come first, followed by input servers
+			for _, server := range m.master.Servers {
+				if !containsServer(servers, server.URL) {
+					servers = append(servers, server)
+				}
+			}
+		}
+		m.master.Servers = servers
+	}
+
+	if m.cfg.BasePath != "" {
+		m.reconcileBasePathServers()
 	}
 
 	// Apply security schemes (components.securitySchemes)
@@ -737,9 +1463,91 @@ func (m *Merger) applyOverrides(mergedDescriptions []string) {
 	}
 
 	// Apply security requirements (global security)
-	if len(m.cfg.Security) > 0 {
+	if m.cfg.SecurityMergeStrategy == config.SecurityMergeStrategyMerge {
+		m.master.Security = appendSecurityRequirements(config.ToOpenAPI3Security(m.cfg.Security), m.master.Security)
+	} else if len(m.cfg.Security) > 0 {
 		m.master.Security = config.ToOpenAPI3Security(m.cfg.Security)
 	}
+
+	if len(m.cfg.GlobalParameters) > 0 {
+		if err := m.applyGlobalParameters(); err != nil {
+			return err
+		}
+	}
+
+	if len(m.cfg.GlobalResponses) > 0 {
+		if err := m.applyGlobalResponses(); err != nil {
+			return err
+		}
+	}
+
+	if len(m.cfg.Patches) > 0 {
+		if err := m.applyPatches(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyGlobalParameters adds the configured global parameters to every
+// operation. Operations that already have a parameter with the same name and
+// location, directly or through their path item, keep their own.
+func (m *Merger) applyGlobalParameters() error {
+	if m.master.Paths == nil {
+		return nil
+	}
+	for _, path := range sortedPaths(m.master.Paths) {
+		pathItem := m.master.Paths.Value(path)
+		for _, method := range httpMethods {
+			op := pathItem.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			for _, paramCfg := range m.cfg.GlobalParameters {
+				if !paramCfg.AppliesTo(method) {
+					continue
+				}
+				param, err := injectedParameter(paramCfg, m.master.Components)
+				if err != nil {
+					return fmt.Errorf("globalParameters: %w", err)
+				}
+				if hasParameter(pathItem.Parameters, param.Value.Name, param.Value.In) || hasParameter(op.Parameters, param.Value.Name, param.Value.In) {
+					continue
+				}
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+	}
+	return nil
+}
+
+// injectedParameter returns the parameter to inject for paramCfg: an inline
+// parameter, or a reference to the component parameter named by its ref,
+// looked up in each of components in turn.
+func injectedParameter(paramCfg config.ParameterConfig, components ...*openapi3.Components) (*openapi3.ParameterRef, error) {
+	if paramCfg.Ref == "" {
+		return &openapi3.ParameterRef{Value: paramCfg.ToOpenAPI3Parameter()}, nil
+	}
+	for _, c := range components {
+		if c == nil {
+			continue
+		}
+		if component := c.Parameters[paramCfg.ComponentName()]; component != nil && component.Value != nil {
+			return &openapi3.ParameterRef{Ref: paramCfg.Ref, Value: component.Value}, nil
+		}
+	}
+	return nil, fmt.Errorf("parameter %s is not defined", paramCfg.Ref)
+}
+
+// hasParameter reports whether params contain a parameter with the given
+// name and location.
+func hasParameter(params openapi3.Parameters, name, in string) bool {
+	for _, p := range params {
+		if p != nil && p.Value != nil && p.Value.Name == name && p.Value.In == in {
+			return true
+		}
+	}
+	return false
 }
 
 // applyBasePath prepends the global basePath to all paths.
@@ -748,23 +1556,31 @@ func (m *Merger) applyBasePath() {
 		return
 	}
 
-	basePath := m.cfg.BasePath
-	// Ensure basePath starts with / and doesn't end with /
-	if !strings.HasPrefix(basePath, "/") {
-		basePath = "/" + basePath
-	}
-	basePath = strings.TrimSuffix(basePath, "/")
+	basePath := normalizedBasePath(m.cfg.BasePath)
 
 	newPaths := openapi3.NewPaths()
-	for path, pathItem := range m.master.Paths.Map() {
+	newPaths.Extensions = m.master.Paths.Extensions
+	for _, path := range sortedPaths(m.master.Paths) {
+		pathItem := m.master.Paths.Value(path)
 		newPath := basePath + path
+		for _, source := range m.pathItemSources(pathItem) {
+			m.recordPathRewrite(source, path, newPath)
+		}
 		newPaths.Set(newPath, pathItem)
 	}
 	m.master.Paths = newPaths
 
 	if m.verbose {
-		fmt.Printf("Applied global basePath: %s\n", basePath)
+		fmt.Fprintf(m.log, "Applied global basePath: %s\n", basePath)
+	}
+}
+
+// normalizedBasePath returns basePath starting with / and not ending with /.
+func normalizedBasePath(basePath string) string {
+	if !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
 	}
+	return strings.TrimSuffix(basePath, "/")
 }
 
 // sortOutput sorts tags and paths according to configuration.
@@ -810,50 +1626,157 @@ func (m *Merger) sortTags() {
 	m.master.Tags = sortedTags
 }
 
-// writeOutput serializes and writes the master spec to disk.
+// writeOutput serializes the master spec and writes it to the output file, or
+// to standard output when the output is "-".
 func (m *Merger) writeOutput() error {
-	// Create output directory if needed
-	outputDir := filepath.Dir(m.cfg.Output)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	isYAML := m.cfg.OutputIsYAML()
+	data, err := m.marshalOutput(isYAML)
+	if err != nil {
+		return err
+	}
+
+	if m.cfg.WritesToStdout() {
+		if _, err := m.stdout.Write(data); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	} else {
+		// Create output directory if needed
+		if err := m.ensureOutputDir(filepath.Dir(m.cfg.Output)); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(m.cfg.Output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	if m.cfg.Checksum {
+		if err := m.writeChecksum(m.cfg.Output, data); err != nil {
+			return err
+		}
+	}
+
+	if m.cfg.OutputURL != "" {
+		return m.publishOutput(data, isYAML)
+	}
+
+	return nil
+}
+
+// marshalOutput serializes the merged spec as YAML or JSON, the way it is
+// written to the output.
+func (m *Merger) marshalOutput(isYAML bool) ([]byte, error) {
+	if m.targetsOpenAPI31() {
+		if err := m.checkOpenAPI31Output(); err != nil {
+			return nil, err
+		}
 	}
 
-	// Determine output format
-	ext := strings.ToLower(filepath.Ext(m.cfg.Output))
 	var data []byte
 	var err error
 
-	if ext == ".yaml" || ext == ".yml" {
+	if isYAML {
 		data, err = m.marshalYAML()
+		if err == nil && m.cfg.OutputHeader {
+			data = append([]byte(outputHeader(m.command, time.Now())), data...)
+		}
 	} else {
 		data, err = m.marshalJSON()
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to marshal output: %w", err)
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
 	}
 
-	if err := os.WriteFile(m.cfg.Output, data, 0644); err != nil {
-		return fmt.Errorf("failed to write output file: %w", err)
+	return normalizeOutput(data, m.cfg.TrailingNewline()), nil
+}
+
+// ensureOutputDir makes sure dir exists. Missing directories are created with
+// the process umask applied, unless CreateOutputDir is disabled.
+func (m *Merger) ensureOutputDir(dir string) error {
+	if !m.cfg.ShouldCreateOutputDir() {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("output directory %s does not exist and createOutputDir is disabled: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("output directory %s is not a directory", dir)
+		}
+		return nil
 	}
 
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
 	return nil
 }
 
+// outputHeader returns the comment block prepended to generated YAML output.
+// The command line is left out when it is empty.
+func outputHeader(command string, now time.Time) string {
+	header := fmt.Sprintf("# Code generated by openapi-merge %s. DO NOT EDIT.\n", Version)
+	if command != "" {
+		header += fmt.Sprintf("# Command: %s\n", command)
+	}
+	return header + fmt.Sprintf("# Generated at: %s\n\n", now.UTC().Format(time.RFC3339))
+}
+
+// utf8BOM is the UTF-8 byte order mark.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// normalizeOutput strips any byte order mark and trailing newlines, then
+// adds exactly one newline if requested.
+func normalizeOutput(data []byte, trailingNewline bool) []byte {
+	data = bytes.TrimRight(bytes.TrimPrefix(data, utf8BOM), "\n")
+	if trailingNewline {
+		data = append(data, '\n')
+	}
+	return data
+}
+
 // marshalJSON marshals the spec to JSON with sorted paths.
 func (m *Merger) marshalJSON() ([]byte, error) {
 	// Sort paths for deterministic output
 	sortedSpec := m.createSortedSpec()
-	return json.MarshalIndent(sortedSpec, "", "  ")
+	return marshalJSONIndent(sortedSpec, m.cfg.OutputIndent())
+}
+
+// marshalJSONIndent is json.MarshalIndent without HTML escaping, so that
+// characters like <, > and & in descriptions stay readable.
+func marshalJSONIndent(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
 // marshalYAML marshals the spec to YAML with sorted paths.
 func (m *Merger) marshalYAML() ([]byte, error) {
 	sortedSpec := m.createSortedSpec()
-	return yaml.Marshal(sortedSpec)
+
+	indent := m.cfg.YAMLIndent()
+	if indent == 0 {
+		return yaml.Marshal(sortedSpec)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(sortedSpec); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-// createSortedSpec creates a copy of the spec with sorted paths.
+// createSortedSpec creates a copy of the spec with sorted paths, components
+// and responses, so the output does not depend on map iteration order.
 func (m *Merger) createSortedSpec() map[string]interface{} {
 	// Convert to map for custom ordering
 	data, _ := json.Marshal(m.master)
@@ -862,65 +1785,54 @@ func (m *Merger) createSortedSpec() map[string]interface{} {
 
 	// Sort paths
 	if paths, ok := result["paths"].(map[string]interface{}); ok {
+		for _, item := range paths {
+			orderResponses(item)
+		}
 		sortedPaths := m.sortPaths(paths)
 		result["paths"] = sortedPaths
 	}
 
+	// Sort components
+	if components, ok := result["components"]; ok {
+		result["components"] = sortComponents(components)
+	}
+
 	return result
 }
 
-// sortPaths sorts paths according to pathsOrder configuration.
-func (m *Merger) sortPaths(paths map[string]interface{}) map[string]interface{} {
+// sortPaths sorts paths according to pathsOrder configuration, then by
+// x-order, then alphabetically. Operations are ordered by x-order as well.
+func (m *Merger) sortPaths(paths map[string]interface{}) *orderedMap {
 	// Create ordered map
-	orderedPaths := make(map[string]interface{})
-
-	// Get all path keys
-	allPaths := make([]string, 0, len(paths))
-	for path := range paths {
-		allPaths = append(allPaths, path)
-	}
-
-	// Sort: priority paths first, then alphabetically
-	sortedPaths := make([]string, 0, len(allPaths))
-
-	// Add priority paths first
-	for _, priorityPath := range m.cfg.PathsOrder {
-		for _, path := range allPaths {
-			if path == priorityPath {
-				sortedPaths = append(sortedPaths, path)
-			}
+	orderedPaths := newOrderedMap()
+
+	// Add priority paths first, ignoring duplicates and paths that do not exist
+	sortedPaths := make([]string, 0, len(paths))
+	isPriority := make(map[string]bool, len(m.cfg.PathsOrder))
+	for _, path := range m.cfg.PathsOrder {
+		if _, ok := paths[path]; ok && !isPriority[path] {
+			isPriority[path] = true
+			sortedPaths = append(sortedPaths, path)
 		}
 	}
 
-	// Add remaining paths alphabetically
-	remainingPaths := make([]string, 0)
-	for _, path := range allPaths {
-		isPriority := false
-		for _, priorityPath := range m.cfg.PathsOrder {
-			if path == priorityPath {
-				isPriority = true
-				break
-			}
-		}
-		if !isPriority {
+	// Add remaining paths by x-order, then alphabetically
+	remainingPaths := make([]string, 0, len(paths)-len(sortedPaths))
+	for path := range paths {
+		if !isPriority[path] {
 			remainingPaths = append(remainingPaths, path)
 		}
 	}
-
-	// Sort remaining paths
-	for i := 0; i < len(remainingPaths); i++ {
-		for j := i + 1; j < len(remainingPaths); j++ {
-			if remainingPaths[i] > remainingPaths[j] {
-				remainingPaths[i], remainingPaths[j] = remainingPaths[j], remainingPaths[i]
-			}
-		}
-	}
+	sort.Slice(remainingPaths, func(i, j int) bool {
+		a, b := remainingPaths[i], remainingPaths[j]
+		return xOrderLess(a, paths[a], b, paths[b])
+	})
 
 	sortedPaths = append(sortedPaths, remainingPaths...)
 
 	// Build ordered map
 	for _, path := range sortedPaths {
-		orderedPaths[path] = paths[path]
+		orderedPaths.Set(path, orderOperations(paths[path]))
 	}
 
 	return orderedPaths
@@ -946,28 +1858,83 @@ func (m *Merger) formatDescription(desc string, cfg *config.DescriptionConfig) s
 
 // hasTag checks if a tag with the given name already exists.
 func (m *Merger) hasTag(name string) bool {
-	for _, tag := range m.master.Tags {
-		if tag.Name == name {
+	return m.tagNames[name]
+}
+
+// addTag appends tag to the master tags unless one with the same name exists.
+// Tags keep the order in which they were first seen.
+func (m *Merger) addTag(tag *openapi3.Tag) {
+	if m.tagNames[tag.Name] {
+		return
+	}
+	m.tagNames[tag.Name] = true
+	m.master.Tags = append(m.master.Tags, tag)
+}
+
+// findServer returns the collected server with the given URL, or nil.
+func (m *Merger) findServer(url string) *openapi3.Server {
+	for _, server := range m.master.Servers {
+		if server.URL == url {
+			return server
+		}
+	}
+	return nil
+}
+
+// containsServer checks if servers contains a server with the given URL.
+func containsServer(servers openapi3.Servers, url string) bool {
+	for _, server := range servers {
+		if server.URL == url {
 			return true
 		}
 	}
 	return false
 }
 
-// schemasEqual compares two schema refs for equality (simple comparison).
-func schemasEqual(a, b *openapi3.SchemaRef) bool {
+// isScalarEnum checks if a schema ref is an inline enum of a single scalar type.
+func isScalarEnum(s *openapi3.SchemaRef) bool {
+	if s == nil || s.Ref != "" || s.Value == nil || len(s.Value.Enum) == 0 {
+		return false
+	}
+	t := s.Value.Type
+	if t == nil || len(*t) != 1 {
+		return false
+	}
+	return !t.Is(openapi3.TypeObject) && !t.Is(openapi3.TypeArray)
+}
+
+// mergeEnums appends the enum values of src that dest doesn't already have.
+func mergeEnums(dest, src *openapi3.Schema) error {
+	if (*dest.Type)[0] != (*src.Type)[0] {
+		return fmt.Errorf("enum base types differ (%s vs %s)", (*dest.Type)[0], (*src.Type)[0])
+	}
+
+	for _, value := range src.Enum {
+		exists := false
+		for _, existing := range dest.Enum {
+			if reflect.DeepEqual(existing, value) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			dest.Enum = append(dest.Enum, value)
+		}
+	}
+
+	return nil
+}
+
+// parametersEqual compares two parameter refs for equality (simple comparison).
+func parametersEqual(a, b *openapi3.ParameterRef) bool {
 	if a == nil && b == nil {
 		return true
 	}
 	if a == nil || b == nil {
 		return false
 	}
-	// Simple reference comparison
 	if a.Ref != "" && b.Ref != "" {
 		return a.Ref == b.Ref
 	}
-	// For value comparison, we do a simple JSON comparison
-	aJSON, _ := json.Marshal(a)
-	bJSON, _ := json.Marshal(b)
-	return string(aJSON) == string(bJSON)
+	return jsonEqual(a, b)
 }
@@ -1,11 +1,19 @@
 package merger
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
-	"github.com/rperez95/openapi-merge/internal/config"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -172,6 +180,146 @@ func TestMerger_PathModification(t *testing.T) {
 	assert.NotContains(t, string(outputData), "/v1/users")
 }
 
+func TestMerger_PathItemFieldsSurviveRekeying(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	specs := []string{`{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/v1/users": {
+				"summary": "Users",
+				"description": "User collection",
+				"x-internal": true,
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}}
+			},
+			"/v2/users": {
+				"x-owner": "identity",
+				"post": {"operationId": "createUser", "responses": {"201": {"description": "Created"}}}
+			}
+		}
+	}`, `{
+		"openapi": "3.0.0",
+		"info": {"title": "Admin", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"summary": "Ignored, the first summary wins",
+				"x-audited": true,
+				"delete": {"operationId": "deleteUsers", "responses": {"204": {"description": "Deleted"}}}
+			}
+		}
+	}`}
+
+	var inputs []config.InputConfig
+	for i, spec := range specs {
+		specPath := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+	inputs[0].PathModification = &config.PathModificationConfig{
+		Replace: []config.PathReplaceConfig{{Pattern: `^/v\d+`, Replacement: ""}},
+	}
+
+	outputPath := filepath.Join(tempDir, "merged.json")
+	cfg := &config.Config{Inputs: inputs, Output: outputPath, BasePath: "/api"}
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var out struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	require.Len(t, out.Paths, 1)
+	item := out.Paths["/api/users"]
+	require.NotNil(t, item)
+	assert.Equal(t, "Users", item["summary"])
+	assert.Equal(t, "User collection", item["description"])
+	assert.Equal(t, true, item["x-internal"])
+	assert.Equal(t, "identity", item["x-owner"])
+	assert.Equal(t, true, item["x-audited"])
+	assert.Contains(t, item, "get")
+	assert.Contains(t, item, "post")
+	assert.Contains(t, item, "delete")
+}
+
+func TestMerger_PathModificationReplace(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/v1/internal/users": {
+				"get": {"responses": {"200": {"description": "Success"}}}
+			},
+			"/v1/internal/users/{id}": {
+				"get": {
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "Success"}}
+				}
+			},
+			"/v2/orders/{orderId}/lines": {
+				"get": {
+					"parameters": [{"name": "orderId", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	merge := func(strict bool, rules ...config.PathReplaceConfig) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{
+				InputFile: specPath,
+				PathModification: &config.PathModificationConfig{
+					Replace: rules,
+					Prepend: "/api",
+				},
+			}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			Strict: strict,
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	m, err := merge(false,
+		// Collapse the internal prefix
+		config.PathReplaceConfig{Pattern: `^/v1/internal(/.*)$`, Replacement: "$1"},
+		// Inject a segment in the middle, keeping the version and parameter
+		config.PathReplaceConfig{Pattern: `^/(v\d+)/orders/(\{[^}]+\})`, Replacement: "/orders/$2/${1}"},
+	)
+	require.NoError(t, err)
+	assert.Empty(t, m.Warnings())
+	assert.Equal(t, []string{"/api/orders/{orderId}/v2/lines", "/api/users", "/api/users/{id}"}, sortedPaths(m.master.Paths))
+
+	// Rewrites that lose a path parameter are skipped, or fail in strict mode
+	dropID := config.PathReplaceConfig{Pattern: `/\{id\}$`, Replacement: "/me"}
+	m, err = merge(false, dropID)
+	require.NoError(t, err)
+	require.Len(t, m.Warnings(), 1)
+	assert.Contains(t, m.Warnings()[0].Message, "changes its path parameters; keeping /v1/internal/users/{id}")
+	assert.NotNil(t, m.master.Paths.Value("/api/v1/internal/users/{id}"))
+
+	_, err = merge(true, dropID)
+	assert.ErrorContains(t, err, "changes its path parameters")
+
+	_, err = merge(false, config.PathReplaceConfig{Pattern: "(", Replacement: ""})
+	assert.ErrorContains(t, err, "invalid regex")
+}
+
 func TestMerger_DisputePrefix(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
 	require.NoError(t, err)
@@ -265,6 +413,121 @@ func TestMerger_DisputePrefix(t *testing.T) {
 	assert.Contains(t, string(outputData), "API2_Item")
 }
 
+func TestMerger_DisputeSuffix(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/items": {
+				"post": {
+					"requestBody": {"$ref": "#/components/requestBodies/ItemBody"},
+					"responses": {
+						"201": {"$ref": "#/components/responses/Created"}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Base": {"type": "object", "properties": {"id": {"type": "string"}}},
+				"Item": {
+					"allOf": [
+						{"$ref": "#/components/schemas/Base"},
+						{"type": "object", "properties": {"tags": {"type": "array", "items": {"$ref": "#/components/schemas/Base"}}}}
+					]
+				}
+			},
+			"requestBodies": {
+				"ItemBody": {
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}
+				}
+			},
+			"responses": {
+				"Created": {
+					"description": "Created",
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}
+				}
+			}
+		}
+	}`
+
+	tests := []struct {
+		name    string
+		dispute *config.DisputeConfig
+		base    string
+		item    string
+		body    string
+		created string
+	}{
+		{
+			name:    "suffix only",
+			dispute: &config.DisputeConfig{Suffix: "V2"},
+			base:    "BaseV2",
+			item:    "ItemV2",
+			body:    "ItemBodyV2",
+			created: "CreatedV2",
+		},
+		{
+			name:    "prefix and suffix",
+			dispute: &config.DisputeConfig{Prefix: "Orders", Suffix: "V2"},
+			base:    "OrdersBaseV2",
+			item:    "OrdersItemV2",
+			body:    "OrdersItemBodyV2",
+			created: "OrdersCreatedV2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specPath := filepath.Join(tempDir, "spec.json")
+			outputPath := filepath.Join(tempDir, "merged.json")
+			require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+			cfg := &config.Config{
+				Inputs: []config.InputConfig{{InputFile: specPath, Dispute: tt.dispute}},
+				Output: outputPath,
+			}
+			require.NoError(t, New(cfg, false).Merge())
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			var out map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &out))
+
+			components := out["components"].(map[string]interface{})
+			schemas := components["schemas"].(map[string]interface{})
+			assert.Contains(t, schemas, tt.base)
+			assert.NotContains(t, schemas, "Base")
+
+			// Nested allOf references follow the rename
+			allOf := schemas[tt.item].(map[string]interface{})["allOf"].([]interface{})
+			assert.Equal(t, "#/components/schemas/"+tt.base, allOf[0].(map[string]interface{})["$ref"])
+			items := allOf[1].(map[string]interface{})["properties"].(map[string]interface{})["tags"].(map[string]interface{})["items"].(map[string]interface{})
+			assert.Equal(t, "#/components/schemas/"+tt.base, items["$ref"])
+
+			// References inside request bodies and responses are rewritten
+			body := components["requestBodies"].(map[string]interface{})[tt.body].(map[string]interface{})
+			bodyJSON, err := json.Marshal(body)
+			require.NoError(t, err)
+			assert.Contains(t, string(bodyJSON), "#/components/schemas/"+tt.item)
+			created := components["responses"].(map[string]interface{})[tt.created].(map[string]interface{})
+			createdJSON, err := json.Marshal(created)
+			require.NoError(t, err)
+			assert.Contains(t, string(createdJSON), "#/components/schemas/"+tt.item)
+
+			// Operations point at the renamed components
+			post := out["paths"].(map[string]interface{})["/items"].(map[string]interface{})["post"].(map[string]interface{})
+			assert.Equal(t, "#/components/requestBodies/"+tt.body, post["requestBody"].(map[string]interface{})["$ref"])
+			resp := post["responses"].(map[string]interface{})["201"].(map[string]interface{})
+			assert.Equal(t, "#/components/responses/"+tt.created, resp["$ref"])
+		})
+	}
+}
+
 func TestMerger_OperationSelection(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
 	require.NoError(t, err)
@@ -318,6 +581,103 @@ func TestMerger_OperationSelection(t *testing.T) {
 	assert.NotContains(t, string(outputData), "/admin")
 }
 
+func TestMerger_OperationSelectionDeprecated(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"put": {"deprecated": true, "responses": {"200": {"description": "OK"}}}
+			},
+			"/v1/users": {
+				"get": {"deprecated": true, "responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	merge := func(sel *config.OperationSelectionConfig) []string {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath, OperationSelection: sel}},
+			Output: filepath.Join(tempDir, "merged.json"),
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		var ops []string
+		forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+			ops = append(ops, method+" "+path)
+		})
+		sort.Strings(ops)
+		return ops
+	}
+
+	assert.Equal(t, []string{"GET /users"}, merge(&config.OperationSelectionConfig{ExcludeDeprecated: true}))
+	assert.Equal(t, []string{"GET /v1/users", "PUT /users"}, merge(&config.OperationSelectionConfig{IncludeOnlyDeprecated: true}))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath, OperationSelection: &config.OperationSelectionConfig{
+			ExcludeDeprecated:     true,
+			IncludeOnlyDeprecated: true,
+		}}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	assert.EqualError(t, cfg.Validate(), "input[0]: operationSelection: excludeDeprecated and includeOnlyDeprecated cannot both be set")
+}
+
+func TestMerger_OperationSelectionByOperationID(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}},
+				"post": {"operationId": "internal_createUser", "responses": {"200": {"description": "OK"}}}
+			},
+			"/health": {
+				"get": {"operationId": "internal_health", "responses": {"200": {"description": "OK"}}}
+			},
+			"/ping": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	merge := func(sel *config.OperationSelectionConfig) []string {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath, OperationSelection: sel}},
+			Output: filepath.Join(tempDir, "merged.json"),
+		}
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		var ops []string
+		forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+			ops = append(ops, method+" "+path)
+		})
+		sort.Strings(ops)
+		return ops
+	}
+
+	assert.Equal(t, []string{"GET /health", "POST /users"},
+		merge(&config.OperationSelectionConfig{IncludeOperationIDs: []string{"internal_*"}}))
+	assert.Equal(t, []string{"GET /ping", "GET /users"},
+		merge(&config.OperationSelectionConfig{ExcludeOperationIDs: []string{"internal_*"}}))
+	assert.Equal(t, []string{"GET /users"},
+		merge(&config.OperationSelectionConfig{IncludeOperationIDs: []string{"*"}, ExcludeOperationIDs: []string{"*_health", "*create*"}}))
+}
+
 func TestMatchGlob(t *testing.T) {
 	tests := []struct {
 		pattern string
@@ -378,6 +738,26 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "multiple primary inputs",
+			cfg: &config.Config{
+				Inputs: []config.InputConfig{
+					{InputFile: "a.json", Primary: true},
+					{InputFile: "b.json", Primary: true},
+				},
+				Output: "output.json",
+			},
+			wantErr: true,
+		},
+		{
+			name: "primary infoMode without primary input",
+			cfg: &config.Config{
+				Inputs:   []config.InputConfig{{InputFile: "a.json"}},
+				Output:   "output.json",
+				InfoMode: config.InfoModePrimary,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -391,3 +771,1445 @@ func TestConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMerger_IncludeResponseHeaders(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"responses": {
+						"200": {"description": "Success"},
+						"404": {"description": "Not found"}
+					}
+				},
+				"post": {
+					"responses": {
+						"201": {
+							"description": "Created",
+							"headers": {
+								"X-RateLimit-Remaining": {
+									"description": "Existing",
+									"schema": {"type": "string"}
+								}
+							}
+						}
+					}
+				}
+			},
+			"/orders": {
+				"get": {"responses": {"200": {"$ref": "#/components/responses/Ok"}}}
+			},
+			"/orders/{id}": {
+				"get": {"responses": {"200": {"$ref": "#/components/responses/Ok"}}}
+			}
+		},
+		"components": {
+			"responses": {"Ok": {"description": "Success"}}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{
+				InputFile: specPath,
+				IncludeResponseHeaders: []config.ResponseHeaderConfig{
+					{
+						Name:        "X-RateLimit-Remaining",
+						Description: "Requests left in the window",
+						Schema:      map[string]interface{}{"type": "integer"},
+						StatusCodes: []string{"2XX"},
+					},
+				},
+			},
+		},
+		Output: outputPath,
+	}
+
+	m := New(cfg, false)
+	err = m.Merge()
+	require.NoError(t, err)
+
+	getResponses := m.master.Paths.Find("/users").Get.Responses
+	header := getResponses.Value("200").Value.Headers["X-RateLimit-Remaining"]
+	require.NotNil(t, header)
+	assert.Equal(t, "Requests left in the window", header.Value.Description)
+	assert.True(t, header.Value.Schema.Value.Type.Is("integer"))
+	assert.Empty(t, getResponses.Value("404").Value.Headers)
+
+	// Existing headers are not overwritten
+	postHeader := m.master.Paths.Find("/users").Post.Responses.Value("201").Value.Headers["X-RateLimit-Remaining"]
+	require.NotNil(t, postHeader)
+	assert.Equal(t, "Existing", postHeader.Value.Description)
+
+	// Referenced responses get the header once, in their component
+	ok := m.master.Components.Responses["Ok"]
+	require.NotNil(t, ok)
+	assert.Len(t, ok.Value.Headers, 1)
+	assert.Contains(t, ok.Value.Headers, "X-RateLimit-Remaining")
+	assert.Equal(t, "#/components/responses/Ok", m.master.Paths.Find("/orders").Get.Responses.Value("200").Ref)
+}
+
+func TestMerger_GlobalParameters(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {
+					"parameters": [{"name": "X-Request-ID", "in": "header", "description": "Own", "schema": {"type": "string"}}],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			},
+			"/orders/{id}": {
+				"parameters": [{"name": "X-Request-ID", "in": "header", "schema": {"type": "string"}}],
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{users, orders} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+
+	cfg := &config.Config{
+		Inputs: inputs,
+		Output: filepath.Join(tempDir, "merged.json"),
+		GlobalParameters: []config.ParameterConfig{
+			{Name: "X-Request-ID", In: "header", Description: "Request tracking ID", Schema: map[string]interface{}{"type": "string"}},
+			{Name: "X-Request-ID", In: "query", Schema: map[string]interface{}{"type": "string"}},
+		},
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	params := func(path, method string) []string {
+		var names []string
+		for _, p := range m.master.Paths.Value(path).GetOperation(method).Parameters {
+			names = append(names, p.Value.In+":"+p.Value.Name+":"+p.Value.Description)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"header:X-Request-ID:Request tracking ID", "query:X-Request-ID:"}, params("/users", "GET"))
+	assert.Equal(t, []string{"header:X-Request-ID:Request tracking ID", "query:X-Request-ID:"}, params("/orders", "GET"))
+	assert.Equal(t, []string{"header:X-Request-ID:Own", "query:X-Request-ID:"}, params("/users", "POST"), "operation parameters win")
+	assert.Equal(t, []string{"query:X-Request-ID:"}, params("/orders/{id}", "GET"), "path item parameters win")
+}
+
+func TestMerger_ParameterRefs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	shared := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Shared", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"parameters": {
+				"TenantId": {"name": "X-Tenant-ID", "in": "header", "required": true, "schema": {"type": "string"}},
+				"RequestId": {"name": "X-Request-ID", "in": "header", "schema": {"type": "string"}}
+			}
+		}
+	}`
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {
+					"parameters": [{"name": "X-Tenant-ID", "in": "header", "schema": {"type": "string"}}],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{shared, users} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+	inputs[1].IncludeExtraParameters = []config.ParameterConfig{{Ref: "#/components/parameters/TenantId"}}
+
+	cfg := &config.Config{
+		Inputs:           inputs,
+		Output:           filepath.Join(tempDir, "merged.json"),
+		GlobalParameters: []config.ParameterConfig{{Ref: "#/components/parameters/RequestId"}},
+	}
+	require.NoError(t, cfg.Validate())
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	refs := func(method string) []string {
+		var refs []string
+		for _, p := range m.master.Paths.Value("/users").GetOperation(method).Parameters {
+			refs = append(refs, p.Ref)
+		}
+		return refs
+	}
+	assert.Equal(t, []string{"#/components/parameters/TenantId", "#/components/parameters/RequestId"}, refs("GET"))
+	assert.Equal(t, []string{"", "#/components/parameters/RequestId"}, refs("POST"), "the inline X-Tenant-ID header is kept")
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$ref": "#/components/parameters/TenantId"`)
+
+	t.Run("undefined component", func(t *testing.T) {
+		cfg.GlobalParameters = []config.ParameterConfig{{Ref: "#/components/parameters/Missing"}}
+		err := New(cfg, false).Merge()
+		assert.ErrorContains(t, err, "globalParameters: parameter #/components/parameters/Missing is not defined")
+
+		cfg.GlobalParameters = nil
+		cfg.Inputs[1].IncludeExtraParameters = []config.ParameterConfig{{Ref: "#/components/parameters/Missing"}}
+		err = New(cfg, false).Merge()
+		assert.ErrorContains(t, err, "parameter #/components/parameters/Missing is not defined")
+	})
+
+	t.Run("ref must point to a component parameter", func(t *testing.T) {
+		cfg.GlobalParameters = []config.ParameterConfig{{Ref: "#/components/schemas/TenantId"}}
+		assert.EqualError(t, cfg.Validate(), `globalParameters[0]: ref "#/components/schemas/TenantId" must point to #/components/parameters/<name>`)
+	})
+}
+
+func TestMerger_ParameterMethods(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {"responses": {"201": {"description": "Created"}}},
+				"patch": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{
+			InputFile: specPath,
+			IncludeExtraParameters: []config.ParameterConfig{
+				{Name: "Prefer", In: "header", Methods: []string{"post"}},
+			},
+		}},
+		Output: filepath.Join(tempDir, "merged.json"),
+		GlobalParameters: []config.ParameterConfig{
+			{Name: "If-Match", In: "header", Methods: []string{"PATCH", "PUT"}},
+		},
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	params := func(method string) []string {
+		var names []string
+		for _, p := range m.master.Paths.Value("/users").GetOperation(method).Parameters {
+			names = append(names, p.Value.Name)
+		}
+		return names
+	}
+	assert.Empty(t, params("GET"))
+	assert.Equal(t, []string{"Prefer"}, params("POST"))
+	assert.Equal(t, []string{"If-Match"}, params("PATCH"))
+}
+
+func TestMerger_ParameterSchemaConstraints(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/users": {"get": {"responses": {"200": {"description": "OK"}}}}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{
+			InputFile: specPath,
+			IncludeExtraParameters: []config.ParameterConfig{
+				{Name: "sort", In: "query", Schema: map[string]interface{}{
+					"type":    "string",
+					"enum":    []interface{}{"asc", "desc"},
+					"default": "asc",
+				}},
+				{Name: "ids", In: "query", Schema: map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 1000.5},
+				}},
+				{Name: "session", In: "cookie", Schema: map[string]interface{}{
+					"type":      "string",
+					"pattern":   "^[a-f0-9]+$",
+					"minLength": 8,
+					"maxLength": 64,
+				}},
+			},
+		}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := make(map[string]*openapi3.Schema)
+	for _, p := range m.master.Paths.Value("/users").Get.Parameters {
+		schemas[p.Value.In+" "+p.Value.Name] = p.Value.Schema.Value
+	}
+
+	order := schemas["query sort"]
+	require.NotNil(t, order)
+	assert.Equal(t, []interface{}{"asc", "desc"}, order.Enum)
+	assert.Equal(t, "asc", order.Default)
+
+	ids := schemas["query ids"]
+	require.NotNil(t, ids)
+	require.NotNil(t, ids.Items)
+	assert.True(t, ids.Items.Value.Type.Is("integer"))
+	require.NotNil(t, ids.Items.Value.Min)
+	assert.Equal(t, 1.0, *ids.Items.Value.Min)
+	require.NotNil(t, ids.Items.Value.Max)
+	assert.Equal(t, 1000.5, *ids.Items.Value.Max)
+
+	session := schemas["cookie session"]
+	require.NotNil(t, session)
+	assert.Equal(t, "^[a-f0-9]+$", session.Pattern)
+	assert.Equal(t, uint64(8), session.MinLength)
+	require.NotNil(t, session.MaxLength)
+	assert.Equal(t, uint64(64), *session.MaxLength)
+}
+
+func TestMatchStatusCode(t *testing.T) {
+	tests := []struct {
+		pattern string
+		code    string
+		want    bool
+	}{
+		{"200", "200", true},
+		{"2XX", "200", true},
+		{"2XX", "204", true},
+		{"2xx", "2XX", true},
+		{"2XX", "404", false},
+		{"*", "default", true},
+		{"2XX", "default", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchStatusCode(tt.pattern, tt.code))
+		})
+	}
+}
+
+func TestMerger_ConvertedServers(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Legacy", "version": "1.0.0"},
+		"host": "legacy.example.com",
+		"basePath": "/v1",
+		"schemes": ["https"],
+		"paths": {
+			"/orders": {
+				"get": {"responses": {"200": {"description": "Success"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "swagger.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(specPath, []byte(swagger), 0644))
+
+	tests := []struct {
+		name        string
+		serversMode string
+		strip       bool
+		want        []string
+	}{
+		{"config mode drops input servers", "", false, nil},
+		{"union keeps converted servers", config.ServersModeUnion, false, []string{"https://legacy.example.com/v1"}},
+		{"union with strip", config.ServersModeUnion, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Inputs:                []config.InputConfig{{InputFile: specPath}},
+				Output:                outputPath,
+				ServersMode:           tt.serversMode,
+				StripConvertedServers: tt.strip,
+			}
+
+			m := New(cfg, false)
+			require.NoError(t, m.Merge())
+
+			var urls []string
+			for _, server := range m.master.Servers {
+				urls = append(urls, server.URL)
+			}
+			assert.Equal(t, tt.want, urls)
+		})
+	}
+}
+
+func TestMerger_ParameterCollision(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API 1", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/PageParam"}],
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		},
+		"components": {
+			"parameters": {
+				"PageParam": {"name": "page", "in": "query", "schema": {"type": "integer"}}
+			}
+		}
+	}`
+
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API 2", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/PageParam"}],
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		},
+		"components": {
+			"parameters": {
+				"PageParam": {"name": "page", "in": "query", "schema": {"type": "string"}}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "spec1.json")
+	spec2Path := filepath.Join(tempDir, "spec2.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	t.Run("without dispute prefix", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: spec1Path},
+				{InputFile: spec2Path},
+			},
+			Output: outputPath,
+		}
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parameter collision for 'PageParam'")
+	})
+
+	t.Run("with dispute prefix", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: spec1Path},
+				{
+					InputFile: spec2Path,
+					Dispute:   &config.DisputeConfig{Prefix: "Orders"},
+				},
+			},
+			Output: outputPath,
+		}
+
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		params := m.master.Components.Parameters
+		require.Contains(t, params, "PageParam")
+		require.Contains(t, params, "OrdersPageParam")
+		assert.True(t, params["PageParam"].Value.Schema.Value.Type.Is("integer"))
+		assert.True(t, params["OrdersPageParam"].Value.Schema.Value.Type.Is("string"))
+
+		assert.Equal(t, "#/components/parameters/PageParam",
+			m.master.Paths.Find("/users").Get.Parameters[0].Ref)
+		assert.Equal(t, "#/components/parameters/OrdersPageParam",
+			m.master.Paths.Find("/orders").Get.Parameters[0].Ref)
+	})
+}
+
+func TestMerger_FetchUserAgent(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(spec))
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: server.URL + "/openapi.json"}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	require.NoError(t, New(cfg, false).Merge())
+	assert.Equal(t, "openapi-merge/"+Version, gotUserAgent)
+
+	cfg.Fetch = &config.FetchConfig{UserAgent: "gateway-builder/2.0"}
+	require.NoError(t, New(cfg, false).Merge())
+	assert.Equal(t, "gateway-builder/2.0", gotUserAgent)
+}
+
+func TestMerger_SchemaConflictMergeEnums(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API 1", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Status": {"type": "string", "enum": ["active", "pending"]}
+			}
+		}
+	}`
+
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API 2", "version": "2.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Status": {"type": "string", "enum": ["pending", "archived"]}
+			}
+		}
+	}`
+
+	spec3 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API 3", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Status": {"type": "integer", "enum": [1, 2]}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "spec1.json")
+	spec2Path := filepath.Join(tempDir, "spec2.json")
+	spec3Path := filepath.Join(tempDir, "spec3.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+	require.NoError(t, os.WriteFile(spec3Path, []byte(spec3), 0644))
+
+	t.Run("union of string enums", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: spec1Path},
+				{InputFile: spec2Path},
+			},
+			Output:         outputPath,
+			SchemaConflict: config.SchemaConflictMergeEnums,
+		}
+
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		assert.Equal(t, []interface{}{"active", "pending", "archived"},
+			m.master.Components.Schemas["Status"].Value.Enum)
+	})
+
+	t.Run("different base types", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: spec1Path},
+				{InputFile: spec3Path},
+			},
+			Output:         outputPath,
+			SchemaConflict: config.SchemaConflictMergeEnums,
+		}
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "enum base types differ")
+	})
+
+	t.Run("default policy errors", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: spec1Path},
+				{InputFile: spec2Path},
+			},
+			Output: outputPath,
+		}
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schema collision for 'Status'")
+	})
+}
+
+func TestMerger_OutputNewline(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	disabled := false
+	tests := []struct {
+		name    string
+		output  string
+		newline *bool
+		want    bool
+	}{
+		{"json default", "merged.json", nil, true},
+		{"yaml default", "merged.yaml", nil, true},
+		{"json disabled", "merged.json", &disabled, false},
+		{"yaml disabled", "merged.yaml", &disabled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(tempDir, tt.output)
+			cfg := &config.Config{
+				Inputs:        []config.InputConfig{{InputFile: specPath}},
+				Output:        outputPath,
+				OutputNewline: tt.newline,
+			}
+
+			require.NoError(t, New(cfg, false).Merge())
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, strings.HasSuffix(string(data), "\n"))
+			assert.False(t, strings.HasSuffix(string(data), "\n\n"))
+			assert.False(t, strings.HasPrefix(string(data), "\ufeff"))
+		})
+	}
+}
+
+func TestMerger_OperationSelectionByExtension(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {
+			"/public": {
+				"get": {
+					"x-visibility": "public",
+					"responses": {"200": {"description": "Success"}}
+				}
+			},
+			"/internal": {
+				"get": {
+					"x-visibility": "internal",
+					"responses": {"200": {"description": "Success"}}
+				}
+			},
+			"/beta": {
+				"get": {
+					"x-beta": true,
+					"responses": {"200": {"description": "Success"}}
+				}
+			},
+			"/plain": {
+				"get": {"responses": {"200": {"description": "Success"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	tests := []struct {
+		name string
+		sel  *config.OperationSelectionConfig
+		want []string
+	}{
+		{
+			name: "include by value",
+			sel: &config.OperationSelectionConfig{
+				IncludeByExtension: []config.ExtensionFilter{{Key: "x-visibility", Value: "public"}},
+			},
+			want: []string{"/public"},
+		},
+		{
+			name: "include by presence",
+			sel: &config.OperationSelectionConfig{
+				IncludeByExtension: []config.ExtensionFilter{{Key: "x-visibility"}},
+			},
+			want: []string{"/internal", "/public"},
+		},
+		{
+			name: "exclude by non-string value",
+			sel: &config.OperationSelectionConfig{
+				ExcludeByExtension: []config.ExtensionFilter{{Key: "x-beta", Value: "true"}},
+			},
+			want: []string{"/internal", "/plain", "/public"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Inputs: []config.InputConfig{{InputFile: specPath, OperationSelection: tt.sel}},
+				Output: outputPath,
+			}
+
+			m := New(cfg, false)
+			require.NoError(t, m.Merge())
+			assert.Equal(t, tt.want, sortedPaths(m.master.Paths))
+		})
+	}
+}
+
+func TestMerger_InfoModePrimary(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {
+			"title": "Platform",
+			"version": "4.2.0",
+			"description": "The platform API",
+			"contact": {"name": "Platform Team", "email": "platform@example.com"}
+		},
+		"paths": {}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "spec1.json")
+	spec2Path := filepath.Join(tempDir, "spec2.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: spec1Path},
+			{InputFile: spec2Path, Primary: true},
+		},
+		Output:   outputPath,
+		InfoMode: config.InfoModePrimary,
+		Info:     &config.InfoConfig{Version: "5.0.0"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	assert.Equal(t, "Platform", m.master.Info.Title)
+	assert.Equal(t, "5.0.0", m.master.Info.Version)
+	assert.Equal(t, "The platform API", m.master.Info.Description)
+	require.NotNil(t, m.master.Info.Contact)
+	assert.Equal(t, "platform@example.com", m.master.Info.Contact.Email)
+
+	// First mode uses the first input instead
+	cfg.InfoMode = config.InfoModeFirst
+	cfg.Info = nil
+	m = New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Equal(t, "Users", m.master.Info.Title)
+}
+
+func TestMerger_InfoModeFirstAndLast(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	specs := []string{`{
+		"openapi": "3.0.0",
+		"info": {
+			"title": "Users",
+			"version": "1.0.0",
+			"contact": {"name": "Users Team", "email": "users@example.com"}
+		},
+		"paths": {}
+	}`, `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "2.0.0"},
+		"paths": {}
+	}`}
+
+	var inputs []config.InputConfig
+	for i, spec := range specs {
+		specPath := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+
+	cfg := &config.Config{
+		Inputs:   inputs,
+		Output:   filepath.Join(tempDir, "merged.json"),
+		InfoMode: config.InfoModeFirst,
+		Info:     &config.InfoConfig{Title: "Shop API"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Equal(t, "Shop API", m.master.Info.Title)
+	assert.Equal(t, "1.0.0", m.master.Info.Version)
+	require.NotNil(t, m.master.Info.Contact)
+	assert.Equal(t, "users@example.com", m.master.Info.Contact.Email)
+
+	cfg.InfoMode = config.InfoModeLast
+	require.NoError(t, cfg.Validate())
+	m = New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Equal(t, "Shop API", m.master.Info.Title)
+	assert.Equal(t, "2.0.0", m.master.Info.Version)
+	assert.Nil(t, m.master.Info.Contact)
+}
+
+func TestMerger_CreateOutputDirDisabled(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	createDir := false
+	missingDir := filepath.Join(tempDir, "missing")
+	cfg := &config.Config{
+		Inputs:          []config.InputConfig{{InputFile: specPath}},
+		Output:          filepath.Join(missingDir, "merged.json"),
+		CreateOutputDir: &createDir,
+	}
+
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "createOutputDir is disabled")
+	_, statErr := os.Stat(missingDir)
+	assert.True(t, os.IsNotExist(statErr))
+
+	// An existing directory is still written to
+	cfg.Output = filepath.Join(tempDir, "merged.json")
+	require.NoError(t, New(cfg, false).Merge())
+	assert.FileExists(t, cfg.Output)
+}
+
+func TestMerger_CallbacksWithPathModificationAndDispute(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Webhooks", "version": "1.0.0"},
+		"paths": {
+			"/v1/subscriptions": {
+				"post": {
+					"responses": {"201": {"description": "Created"}},
+					"callbacks": {
+						"onEvent": {
+							"{$request.body#/callbackUrl}/events": {
+								"post": {
+									"requestBody": {
+										"content": {
+											"application/json": {
+												"schema": {"$ref": "#/components/schemas/Event"}
+											}
+										}
+									},
+									"responses": {"200": {"description": "OK"}}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Event": {"type": "object", "properties": {"id": {"type": "string"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "webhooks.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{
+				InputFile: specPath,
+				PathModification: &config.PathModificationConfig{
+					StripStart: "/v1",
+					Prepend:    "/hooks",
+				},
+				Dispute: &config.DisputeConfig{Prefix: "Hooks"},
+			},
+		},
+		Output: outputPath,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	pathItem := m.master.Paths.Value("/hooks/subscriptions")
+	require.NotNil(t, pathItem)
+	require.NotNil(t, pathItem.Post)
+
+	callback := pathItem.Post.Callbacks["onEvent"]
+	require.NotNil(t, callback)
+	require.NotNil(t, callback.Value)
+
+	// The callback expression is a URL and keeps its original form
+	cbItem := callback.Value.Value("{$request.body#/callbackUrl}/events")
+	require.NotNil(t, cbItem)
+	require.NotNil(t, cbItem.Post)
+
+	// The schema ref inside the callback follows the dispute prefix
+	schema := cbItem.Post.RequestBody.Value.Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/HooksEvent", schema.Ref)
+	assert.Contains(t, m.master.Components.Schemas, "HooksEvent")
+}
+
+func TestMerger_OverlayOnly(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	base := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"operationId": "listUsers",
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		}
+	}`
+
+	overlay := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Errors", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"operationId": "overlayListUsers",
+					"parameters": [
+						{"name": "X-Request-ID", "in": "header", "schema": {"type": "string"}}
+					],
+					"responses": {
+						"200": {"description": "Overlay success"},
+						"429": {"description": "Too many requests"}
+					}
+				},
+				"delete": {
+					"responses": {"204": {"description": "Deleted"}}
+				}
+			},
+			"/status": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	basePath := filepath.Join(tempDir, "base.json")
+	overlayPath := filepath.Join(tempDir, "overlay.json")
+	require.NoError(t, os.WriteFile(basePath, []byte(base), 0644))
+	require.NoError(t, os.WriteFile(overlayPath, []byte(overlay), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: basePath},
+			{InputFile: overlayPath, OverlayOnly: true},
+		},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	// The novel path and operation are skipped
+	assert.Nil(t, m.master.Paths.Value("/status"))
+	usersPath := m.master.Paths.Value("/users")
+	require.NotNil(t, usersPath)
+	assert.Nil(t, usersPath.Delete)
+
+	// The existing operation keeps its own fields and gains the overlay additions
+	op := usersPath.Get
+	require.NotNil(t, op)
+	assert.Equal(t, "listUsers", op.OperationID)
+	assert.Equal(t, "Success", *op.Responses.Value("200").Value.Description)
+	require.NotNil(t, op.Responses.Value("429"))
+	assert.Equal(t, "Too many requests", *op.Responses.Value("429").Value.Description)
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "X-Request-ID", op.Parameters[0].Value.Name)
+}
+
+func TestMerger_OutputHeader(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	oldVersion := Version
+	Version = "1.2.3"
+	t.Cleanup(func() { Version = oldVersion })
+
+	cfg := &config.Config{
+		Inputs:       []config.InputConfig{{InputFile: specPath}},
+		Output:       filepath.Join(tempDir, "merged.yaml"),
+		OutputHeader: true,
+	}
+	m := New(cfg, false)
+	m.SetCommand("openapi-merge merge --config merge-config.yaml")
+	require.NoError(t, m.Merge())
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	output := string(data)
+	assert.True(t, strings.HasPrefix(output, "# Code generated by openapi-merge 1.2.3. DO NOT EDIT.\n# Command: openapi-merge merge --config merge-config.yaml\n"))
+	assert.Contains(t, output, "\n# Generated at: ")
+	assert.Contains(t, output, "\nopenapi: 3.0.3\n")
+
+	// Without a command line the header leaves it out
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err = os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Code generated by openapi-merge 1.2.3. DO NOT EDIT.\n# Generated at: "))
+
+	// JSON has no comments, so no header is written
+	cfg.Output = filepath.Join(tempDir, "merged.json")
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err = os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "{"))
+}
+
+func TestMerger_OutputToStdout(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath}},
+		Output: config.StdoutOutput,
+	}
+	require.NoError(t, cfg.Validate())
+
+	// Without an extension the output is JSON unless outputFormat says otherwise
+	var jsonOut bytes.Buffer
+	m := New(cfg, false)
+	m.stdout = &jsonOut
+	require.NoError(t, m.Merge())
+	assert.True(t, strings.HasPrefix(jsonOut.String(), "{"))
+
+	cfg.OutputFormat = config.OutputFormatYAML
+	var yamlOut bytes.Buffer
+	m = New(cfg, false)
+	m.stdout = &yamlOut
+	require.NoError(t, m.Merge())
+	assert.False(t, strings.HasPrefix(yamlOut.String(), "{"))
+	assert.Contains(t, yamlOut.String(), "openapi: 3.0.3\n")
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "nothing but the input is written to disk")
+
+	// A file extension takes precedence over outputFormat
+	cfg.Output = filepath.Join(tempDir, "merged.json")
+	assert.False(t, cfg.OutputIsYAML())
+
+	cfg.Output = config.StdoutOutput
+	cfg.Checksum = true
+	assert.ErrorContains(t, cfg.Validate(), "standard output")
+}
+
+func TestMerger_VerboseOutputToStdout(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath}},
+		Output: config.StdoutOutput,
+	}
+	require.NoError(t, cfg.Validate())
+
+	// Progress goes to standard error so the spec on standard output stays valid
+	m := New(cfg, true)
+	assert.Equal(t, os.Stderr, m.log)
+
+	var out, log bytes.Buffer
+	m.stdout = &out
+	m.log = &log
+	require.NoError(t, m.Merge())
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Contains(t, log.String(), "Processing input 1: ")
+
+	cfg.Output = filepath.Join(tempDir, "merged.json")
+	assert.Equal(t, os.Stdout, New(cfg, true).log)
+}
+
+func TestMerger_JSONOutputNoHTMLEscape(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0", "description": "Use <b>bold</b> & more"},
+		"paths": {
+			"/users": {
+				"get": {
+					"description": "Returns <i>all</i> users",
+					"responses": {"200": {"description": "Success"}}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath}},
+		Output: filepath.Join(tempDir, "merged.json"),
+		Info:   &config.InfoConfig{Description: "Use <b>bold</b> & more"},
+	}
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	output := string(data)
+
+	assert.Contains(t, output, `"description": "Use <b>bold</b> & more"`)
+	assert.Contains(t, output, `"description": "Returns <i>all</i> users"`)
+	assert.NotContains(t, output, `\u003c`)
+	assert.True(t, json.Valid(data))
+}
+
+func TestMerger_DuplicateOperationDrift(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	origin := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}}
+			},
+			"/users/{id}": {
+				"get": {
+					"operationId": "getUser",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		}
+	}`
+	// The BFF re-exports both endpoints, but /users/{id} has drifted
+	bff := `{
+		"openapi": "3.0.0",
+		"info": {"title": "BFF", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}}
+			},
+			"/users/{id}": {
+				"get": {
+					"operationId": "getUser",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		}
+	}`
+
+	originPath := filepath.Join(tempDir, "users.json")
+	bffPath := filepath.Join(tempDir, "bff.json")
+	require.NoError(t, os.WriteFile(originPath, []byte(origin), 0644))
+	require.NoError(t, os.WriteFile(bffPath, []byte(bff), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: originPath},
+			{InputFile: bffPath},
+		},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	// First wins, and only the drifted duplicate is reported
+	assert.Equal(t, "string", m.master.Paths.Value("/users/{id}").Get.Parameters[0].Value.Schema.Value.Type.Slice()[0])
+	require.Len(t, m.Warnings(), 1)
+	assert.Equal(t, bffPath, m.Warnings()[0].Source)
+	assert.Equal(t, "GET /users/{id} differs from the operation already merged from "+originPath+"; keeping the earlier one", m.Warnings()[0].Message)
+}
+
+func TestMerger_Indent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, output, indent string) string {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: filepath.Join(tempDir, output),
+			Indent: indent,
+		}
+		require.NoError(t, cfg.Validate())
+		require.NoError(t, New(cfg, false).Merge())
+
+		data, err := os.ReadFile(cfg.Output)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("four spaces", func(t *testing.T) {
+		output := run(t, "merged.json", "    ")
+		assert.Contains(t, output, "\n    \"info\": {\n        \"title\": \"Merged API\"")
+	})
+
+	t.Run("tab", func(t *testing.T) {
+		output := run(t, "merged.json", "\t")
+		assert.Contains(t, output, "\n\t\"info\": {\n\t\t\"title\"")
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		output := run(t, "merged.yaml", "  ")
+		assert.Contains(t, output, "info:\n  title: Merged API\n")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			Indent: "--",
+		}
+		assert.EqualError(t, cfg.Validate(), `indent "--" must contain only spaces or tabs`)
+	})
+}
+
+func TestMerger_GitHubTokenFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	envFile := filepath.Join(tempDir, "env-token")
+	cfgFile := filepath.Join(tempDir, "cfg-token")
+	require.NoError(t, os.WriteFile(envFile, []byte("ghp_from_env_file\n"), 0600))
+	require.NoError(t, os.WriteFile(cfgFile, []byte("  ghp_from_config \n"), 0600))
+
+	const url = "https://raw.githubusercontent.com/owner/repo/main/openapi.json"
+	m := New(&config.Config{Auth: &config.AuthConfig{TokenFile: cfgFile}}, false)
+	authorization := func() string {
+		req, err := m.newRequest("GET", url, nil)
+		require.NoError(t, err)
+		return req.Header.Get("Authorization")
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN_FILE", "")
+	assert.Equal(t, "token ghp_from_config", authorization())
+
+	t.Setenv("GITHUB_TOKEN_FILE", envFile)
+	assert.Equal(t, "token ghp_from_env_file", authorization())
+
+	t.Setenv("GITHUB_TOKEN", "ghp_from_env")
+	assert.Equal(t, "token ghp_from_env", authorization())
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN_FILE", filepath.Join(tempDir, "missing"))
+	_, err = m.newRequest("GET", url, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read token file from GITHUB_TOKEN_FILE")
+}
+
+func TestMerger_RemovePaths(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	base := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "Success"}}},
+				"delete": {"operationId": "purgeUsers", "responses": {"204": {"description": "Deleted"}}}
+			},
+			"/legacy/export": {
+				"get": {"operationId": "legacyExport", "responses": {"200": {"description": "Success"}}}
+			}
+		}
+	}`
+
+	overlay := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Public", "version": "1.0.0"},
+		"paths": {
+			"/status": {
+				"get": {"operationId": "getStatus", "responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	basePath := filepath.Join(tempDir, "base.json")
+	overlayPath := filepath.Join(tempDir, "overlay.json")
+	require.NoError(t, os.WriteFile(basePath, []byte(base), 0644))
+	require.NoError(t, os.WriteFile(overlayPath, []byte(overlay), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: basePath},
+			{
+				InputFile: overlayPath,
+				RemovePaths: []config.PathFilter{
+					{Path: "/legacy/**"},
+					{Path: "/users", Method: "delete"},
+					{Path: "/status"},
+				},
+			},
+		},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	// Whole paths and single operations from earlier inputs are removed
+	assert.Nil(t, m.master.Paths.Value("/legacy/export"))
+	users := m.master.Paths.Value("/users")
+	require.NotNil(t, users)
+	assert.NotNil(t, users.Get)
+	assert.Nil(t, users.Delete)
+
+	// Removal runs before the input's own paths are merged
+	assert.NotNil(t, m.master.Paths.Value("/status"))
+
+	require.Len(t, m.Warnings(), 1)
+	assert.Contains(t, m.Warnings()[0].Message, `"/status" matched no merged operation`)
+}
@@ -0,0 +1,91 @@
+package merger
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Normalization passes, in the order Normalize runs them.
+const (
+	NormalizeDownconvert     = "downconvert"
+	NormalizeHoistExamples   = "hoistExamples"
+	NormalizeStripExtensions = "stripExtensions"
+	NormalizePruneUnused     = "prune"
+	NormalizeSort            = "sort"
+)
+
+// NormalizePasses lists every normalization pass in run order.
+var NormalizePasses = []string{
+	NormalizeDownconvert,
+	NormalizeHoistExamples,
+	NormalizeStripExtensions,
+	NormalizePruneUnused,
+	NormalizeSort,
+}
+
+// Normalize loads a single specification, runs the given normalization passes
+// on it and writes the result to the configured output. Passes always run in
+// the order of NormalizePasses, whatever order they are given in.
+func (m *Merger) Normalize(filePath string, passes []string) error {
+	enabled := make(map[string]bool)
+	for _, pass := range passes {
+		if !slices.Contains(NormalizePasses, pass) {
+			return fmt.Errorf("unknown normalization pass %q (expected %s)", pass, strings.Join(NormalizePasses, ", "))
+		}
+		enabled[pass] = true
+	}
+
+	spec, err := m.loadSpec(filePath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", filePath, err)
+	}
+
+	m.warnings = nil
+	m.sources = make(map[*openapi3.Operation]string)
+	m.componentSources = make(map[string]string)
+	m.tagNames = make(map[string]bool)
+	m.master = spec
+	if m.master.Paths == nil {
+		m.master.Paths = openapi3.NewPaths()
+	}
+	if m.master.Components == nil {
+		m.master.Components = &openapi3.Components{}
+	}
+	if m.master.Components.Examples == nil {
+		m.master.Components.Examples = make(openapi3.Examples)
+	}
+	for _, tag := range m.master.Tags {
+		m.tagNames[tag.Name] = true
+	}
+
+	if enabled[NormalizeDownconvert] {
+		downconvertNullableTypes(m.master)
+	}
+	if enabled[NormalizeHoistExamples] {
+		m.hoistExamples()
+	}
+	if enabled[NormalizeStripExtensions] {
+		stripExtensions(m.master)
+	}
+	if enabled[NormalizePruneUnused] {
+		removed, err := pruneUnusedComponents(m.master)
+		if err != nil {
+			return err
+		}
+		if m.verbose && len(removed) > 0 {
+			fmt.Fprintf(m.log, "Pruned %d unused components\n", len(removed))
+		}
+	}
+	if enabled[NormalizeSort] {
+		// Paths and map keys are already sorted on output
+		sort.SliceStable(m.master.Tags, func(i, j int) bool {
+			return m.master.Tags[i].Name < m.master.Tags[j].Name
+		})
+	}
+
+	return m.writeOutput()
+}
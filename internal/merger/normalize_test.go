@@ -0,0 +1,149 @@
+package merger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Normalize(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"tags": [{"name": "users"}, {"name": "admin"}],
+		"paths": {
+			"/users": {
+				"get": {
+					"tags": ["users"],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object"},
+				"Unused": {"type": "string"}
+			}
+		}
+	}`
+	inputPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(inputPath, []byte(spec), 0644))
+
+	outputPath := filepath.Join(tempDir, "clean.json")
+	m := New(&config.Config{Output: outputPath}, false)
+	require.NoError(t, m.Normalize(inputPath, NormalizePasses))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var out struct {
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	require.Len(t, out.Tags, 2)
+	assert.Equal(t, "admin", out.Tags[0].Name)
+	assert.Equal(t, "users", out.Tags[1].Name)
+	assert.Contains(t, out.Components.Schemas, "User")
+	assert.NotContains(t, out.Components.Schemas, "Unused")
+}
+
+func TestMerger_NormalizeUnknownPass(t *testing.T) {
+	m := New(&config.Config{Output: "unused.json"}, false)
+	err := m.Normalize("unused.json", []string{"prune", "minify"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown normalization pass "minify"`)
+}
+
+func TestMerger_NormalizeStripExtensions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"x-root": true,
+		"info": {"title": "Users", "version": "1.0.0", "x-logo": {"url": "logo.png"}},
+		"paths": {
+			"/users": {
+				"get": {
+					"x-internal-id": "list",
+					"responses": {
+						"200": {
+							"description": "OK",
+							"x-cache": "public",
+							"content": {
+								"application/json": {
+									"schema": {"$ref": "#/components/schemas/User"},
+									"example": {"x-id": "u1"}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {
+					"type": "object",
+					"x-go-type": "User",
+					"properties": {"x-id": {"type": "string", "x-order": 1}}
+				}
+			}
+		}
+	}`
+	inputPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(inputPath, []byte(spec), 0644))
+
+	outputPath := filepath.Join(tempDir, "clean.json")
+	m := New(&config.Config{Output: outputPath}, false)
+	require.NoError(t, m.Normalize(inputPath, []string{NormalizeStripExtensions}))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	out := string(data)
+	for _, key := range []string{"x-root", "x-logo", "x-internal-id", "x-cache", "x-go-type", "x-order"} {
+		assert.NotContains(t, out, key)
+	}
+
+	// A property named x-id and example data are not extensions
+	var doc struct {
+		Paths map[string]struct {
+			Get struct {
+				Responses map[string]struct {
+					Content map[string]struct {
+						Example map[string]interface{} `json:"example"`
+					} `json:"content"`
+				} `json:"responses"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Contains(t, doc.Components.Schemas["User"].Properties, "x-id")
+	assert.Equal(t, map[string]interface{}{"x-id": "u1"},
+		doc.Paths["/users"].Get.Responses["200"].Content["application/json"].Example)
+}
@@ -0,0 +1,295 @@
+package merger
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+// openAPI31SchemaJSON is the official JSON Schema of OpenAPI 3.1 documents
+// (https://spec.openapis.org/oas/3.1/schema/2022-10-07). Like the official
+// one, it checks the structure of the document but accepts any object as a
+// Schema Object.
+//
+//go:embed schemas/openapi-3.1.json
+var openAPI31SchemaJSON []byte
+
+// openAPI31SchemaURL is the $id of openAPI31SchemaJSON.
+const openAPI31SchemaURL = "https://spec.openapis.org/oas/3.1/schema/2022-10-07"
+
+var (
+	openAPI31SchemaOnce     sync.Once
+	openAPI31SchemaCompiled *jsonschema.Schema
+	openAPI31SchemaErr      error
+)
+
+// schemaProblem is a violation of the OpenAPI 3.1 schema at a JSON pointer
+// of the document.
+type schemaProblem struct {
+	Location string
+	Message  string
+}
+
+// openAPI31SchemaProblems validates a decoded JSON document against the
+// OpenAPI 3.1 schema and returns every violation, sorted by location.
+func openAPI31SchemaProblems(doc interface{}) ([]schemaProblem, error) {
+	openAPI31SchemaOnce.Do(func() {
+		var schema interface{}
+		schema, openAPI31SchemaErr = jsonschema.UnmarshalJSON(bytes.NewReader(openAPI31SchemaJSON))
+		if openAPI31SchemaErr != nil {
+			return
+		}
+		c := jsonschema.NewCompiler()
+		if openAPI31SchemaErr = c.AddResource(openAPI31SchemaURL, schema); openAPI31SchemaErr != nil {
+			return
+		}
+		openAPI31SchemaCompiled, openAPI31SchemaErr = c.Compile(openAPI31SchemaURL)
+	})
+	if openAPI31SchemaErr != nil {
+		return nil, fmt.Errorf("failed to load the OpenAPI 3.1 schema: %w", openAPI31SchemaErr)
+	}
+
+	err := openAPI31SchemaCompiled.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	// Report the innermost causes; the ones above them only say which
+	// branch of the schema failed. A property the schema does not allow
+	// fails the false schema of unevaluatedProperties, but so does every
+	// property of an object that failed otherwise, so those are only
+	// reported for objects without other problems.
+	seen := make(map[schemaProblem]bool)
+	invalid := make(map[string]bool)
+	var problems, unexpected []schemaProblem
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				collect(cause)
+			}
+			return
+		}
+		var location strings.Builder
+		for _, token := range e.InstanceLocation {
+			location.WriteString("/" + escapePointerToken(token))
+		}
+		if _, ok := e.ErrorKind.(*kind.FalseSchema); ok {
+			unexpected = append(unexpected, schemaProblem{Location: "#" + location.String(), Message: "unexpected property"})
+			return
+		}
+		problem := schemaProblem{Location: "#" + location.String(), Message: e.BasicOutput().Error.String()}
+		invalid[problem.Location] = true
+		if !seen[problem] {
+			seen[problem] = true
+			problems = append(problems, problem)
+		}
+	}
+	collect(verr)
+	for _, problem := range unexpected {
+		parent := problem.Location[:strings.LastIndex(problem.Location, "/")]
+		if !invalid[parent] && !seen[problem] {
+			seen[problem] = true
+			problems = append(problems, problem)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Location < problems[j].Location })
+	return problems, nil
+}
+
+// isOpenAPI31 reports whether a parsed document declares OpenAPI 3.1.
+func isOpenAPI31(raw map[string]interface{}) bool {
+	version, _ := raw["openapi"].(string)
+	return strings.HasPrefix(version, "3.1")
+}
+
+// targetsOpenAPI31 reports whether the output is OpenAPI 3.1.
+func (m *Merger) targetsOpenAPI31() bool {
+	return m.cfg.OutputOpenAPIVersion() == config.OpenAPIVersion31
+}
+
+// lowerExclusiveBounds rewrites the numeric exclusiveMinimum/exclusiveMaximum
+// of OpenAPI 3.1 into the 3.0 boolean form the loader understands, in place:
+// exclusiveMinimum: 5 becomes minimum: 5, exclusiveMinimum: true. When both
+// an inclusive and an exclusive bound are set, the stricter one is kept.
+func lowerExclusiveBounds(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		lowerExclusiveBound(v, "exclusiveMinimum", "minimum", func(excl, incl float64) bool { return excl >= incl })
+		lowerExclusiveBound(v, "exclusiveMaximum", "maximum", func(excl, incl float64) bool { return excl <= incl })
+		for _, child := range v {
+			lowerExclusiveBounds(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			lowerExclusiveBounds(child)
+		}
+	}
+}
+
+// lowerExclusiveBound lowers a single numeric exclusive bound of a schema.
+func lowerExclusiveBound(schema map[string]interface{}, exclusiveKey, inclusiveKey string, stricter func(excl, incl float64) bool) {
+	excl, ok := toFloat(schema[exclusiveKey])
+	if !ok {
+		return
+	}
+	if incl, ok := toFloat(schema[inclusiveKey]); ok && !stricter(excl, incl) {
+		delete(schema, exclusiveKey)
+		return
+	}
+	schema[inclusiveKey] = schema[exclusiveKey]
+	schema[exclusiveKey] = true
+}
+
+// toFloat returns v as a float64 if it is a number.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// upgradeSchemasTo31 rewrites the OpenAPI 3.0-only schema keywords left by
+// 3.0 and Swagger 2.0 inputs into their 3.1 form:
+//
+//	type: string, nullable: true    ->  type: ["string", "null"]
+//	minimum: 5, exclusiveMinimum: true  ->  exclusiveMinimum: 5
+func upgradeSchemasTo31(spec *openapi3.T) {
+	walkSpecSchemas(spec, upgradeSchemaTo31)
+}
+
+// upgradeSchemaTo31 applies the 3.1 rewrites to a single schema.
+func upgradeSchemaTo31(schema *openapi3.Schema) {
+	if schema.Nullable {
+		schema.Nullable = false
+		if schema.Type != nil && len(*schema.Type) > 0 && !schema.Type.Includes(openapi3.TypeNull) {
+			types := append(append(openapi3.Types{}, *schema.Type...), openapi3.TypeNull)
+			schema.Type = &types
+		}
+		// An enum must list null for null to stay valid
+		if len(schema.Enum) > 0 && !containsNil(schema.Enum) {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+
+	// The numeric bounds are kept as extensions, which are written verbatim
+	if schema.ExclusiveMin && schema.Min != nil {
+		setSchemaExtension(schema, "exclusiveMinimum", *schema.Min)
+		schema.ExclusiveMin, schema.Min = false, nil
+	}
+	if schema.ExclusiveMax && schema.Max != nil {
+		setSchemaExtension(schema, "exclusiveMaximum", *schema.Max)
+		schema.ExclusiveMax, schema.Max = false, nil
+	}
+}
+
+func containsNil(values []interface{}) bool {
+	for _, v := range values {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func setSchemaExtension(schema *openapi3.Schema, key string, value interface{}) {
+	if schema.Extensions == nil {
+		schema.Extensions = make(map[string]interface{})
+	}
+	schema.Extensions[key] = value
+}
+
+// literalKeys hold example data rather than schemas, so checkOpenAPI31Output
+// does not look inside them.
+var literalKeys = map[string]bool{
+	"example": true, "examples": true, "default": true, "enum": true, "const": true,
+}
+
+// checkOpenAPI31Output validates the serialized master against the OpenAPI
+// 3.1 schema and checks it for OpenAPI 3.0-only constructs that the schema
+// does not catch, because it accepts any Schema Object: the nullable keyword
+// and boolean exclusive bounds. Problems are reported as warnings, or as an
+// error in strict mode.
+func (m *Merger) checkOpenAPI31Output() error {
+	data, err := json.Marshal(m.master)
+	if err != nil {
+		return fmt.Errorf("failed to check output: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to check output: %w", err)
+	}
+
+	var problems []string
+	var walk func(v interface{}, pointer string)
+	walk = func(v interface{}, pointer string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, key := range sortedKeys(v) {
+				child := v[key]
+				if literalKeys[key] {
+					continue
+				}
+				childPointer := pointer + "/" + escapePointerToken(key)
+				switch key {
+				case "nullable":
+					if _, ok := child.(bool); ok {
+						problems = append(problems, fmt.Sprintf("nullable is not valid in OpenAPI 3.1 (at #%s)", childPointer))
+					}
+				case "exclusiveMinimum", "exclusiveMaximum":
+					if _, ok := child.(bool); ok {
+						problems = append(problems, fmt.Sprintf("%s must be a number in OpenAPI 3.1 (at #%s)", key, childPointer))
+					}
+				}
+				walk(child, childPointer)
+			}
+		case []interface{}:
+			for i, child := range v {
+				walk(child, fmt.Sprintf("%s/%d", pointer, i))
+			}
+		}
+	}
+	walk(doc, "")
+
+	schemaProblems, err := openAPI31SchemaProblems(doc)
+	if err != nil {
+		return fmt.Errorf("failed to check output: %w", err)
+	}
+	for _, problem := range schemaProblems {
+		problems = append(problems, fmt.Sprintf("%s (at %s)", problem.Message, problem.Location))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if m.cfg.Strict {
+		return fmt.Errorf("output is not valid OpenAPI 3.1: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		m.warnf("", "%s", problem)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package merger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const spec31 = `{
+	"openapi": "3.1.0",
+	"info": {"title": "Pets", "version": "1.0.0"},
+	"paths": {
+		"/pets": {
+			"get": {
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+					}
+				}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"properties": {
+					"name": {"type": ["string", "null"], "examples": ["Rex"]},
+					"kind": {"const": "pet"},
+					"age": {"type": "integer", "exclusiveMinimum": 0}
+				}
+			}
+		}
+	}
+}`
+
+const spec30 = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Owners", "version": "1.0.0"},
+	"paths": {
+		"/owners": {
+			"get": {
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Owner"}}}
+					}
+				}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Owner": {
+				"type": "object",
+				"properties": {
+					"nickname": {"type": "string", "nullable": true},
+					"tier": {"type": "string", "nullable": true, "enum": ["gold", "silver"]},
+					"pets": {"type": "integer", "minimum": 0, "exclusiveMinimum": true}
+				}
+			}
+		}
+	}
+}`
+
+// mergeOpenAPIVersion merges spec31 and spec30 into the given OpenAPI version
+// and returns the schemas of the written output.
+func mergeOpenAPIVersion(t *testing.T, version string) (map[string]interface{}, *Merger) {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	input1 := filepath.Join(tempDir, "pets.json")
+	input2 := filepath.Join(tempDir, "owners.json")
+	require.NoError(t, os.WriteFile(input1, []byte(spec31), 0644))
+	require.NoError(t, os.WriteFile(input2, []byte(spec30), 0644))
+
+	cfg := &config.Config{
+		Inputs:         []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output:         filepath.Join(tempDir, "merged.json"),
+		OpenAPIVersion: version,
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	data, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, version, out["openapi"])
+	return out["components"].(map[string]interface{})["schemas"].(map[string]interface{}), m
+}
+
+func properties(schemas map[string]interface{}, name string) map[string]interface{} {
+	return schemas[name].(map[string]interface{})["properties"].(map[string]interface{})
+}
+
+func TestMerger_OpenAPI31Output(t *testing.T) {
+	schemas, m := mergeOpenAPIVersion(t, config.OpenAPIVersion31)
+	assert.Empty(t, m.Warnings())
+
+	pet := properties(schemas, "Pet")
+	assert.Equal(t, []interface{}{"string", "null"}, pet["name"].(map[string]interface{})["type"])
+	assert.Equal(t, []interface{}{"Rex"}, pet["name"].(map[string]interface{})["examples"])
+	assert.Equal(t, "pet", pet["kind"].(map[string]interface{})["const"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "exclusiveMinimum": float64(0)}, pet["age"])
+
+	// 3.0 constructs are upgraded
+	owner := properties(schemas, "Owner")
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"string", "null"}}, owner["nickname"])
+	assert.Equal(t, []interface{}{"gold", "silver", nil}, owner["tier"].(map[string]interface{})["enum"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "exclusiveMinimum": float64(0)}, owner["pets"])
+}
+
+func TestMerger_OpenAPI30Output(t *testing.T) {
+	schemas, _ := mergeOpenAPIVersion(t, config.OpenAPIVersion30)
+
+	pet := properties(schemas, "Pet")
+	assert.Equal(t, map[string]interface{}{"type": "string", "nullable": true, "examples": []interface{}{"Rex"}}, pet["name"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "minimum": float64(0), "exclusiveMinimum": true}, pet["age"])
+
+	owner := properties(schemas, "Owner")
+	assert.Equal(t, map[string]interface{}{"type": "string", "nullable": true}, owner["nickname"])
+}
+
+func TestMerger_CheckOpenAPI31Output(t *testing.T) {
+	m := New(&config.Config{OpenAPIVersion: config.OpenAPIVersion31, Strict: true}, false)
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Legacy": {"type": "string", "nullable": true, "example": {"nullable": true}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	m.master = spec
+
+	err = m.checkOpenAPI31Output()
+	require.Error(t, err)
+	assert.Equal(t, "output is not valid OpenAPI 3.1: nullable is not valid in OpenAPI 3.1 (at #/components/schemas/Legacy/nullable)", err.Error())
+}
+
+func TestMerger_CheckOpenAPI31OutputSchema(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "API", "version": "1.0.0", "owner": "platform"},
+		"paths": {
+			"/users": {"get": {"responses": {"200": {"content": {}}}}}
+		},
+		"components": {
+			"securitySchemes": {"key": {"type": "apiKey", "in": "header"}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	m := New(&config.Config{OpenAPIVersion: config.OpenAPIVersion31}, false)
+	m.master = spec
+	require.NoError(t, m.checkOpenAPI31Output())
+	assert.Equal(t, []Warning{
+		{Message: "missing property 'description' (at #/paths/~1users/get/responses/200)"},
+		{Message: "missing property 'name' (at #/components/securitySchemes/key)"},
+		{Message: "unexpected property (at #/info/owner)"},
+	}, m.Warnings())
+
+	m = New(&config.Config{OpenAPIVersion: config.OpenAPIVersion31, Strict: true}, false)
+	m.master = spec
+	err = m.checkOpenAPI31Output()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output is not valid OpenAPI 3.1: missing property 'description'")
+}
@@ -0,0 +1,250 @@
+package merger
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// applyOperationIDStyle rewrites every operationId to the configured style.
+// Distinct IDs that collapse into the same one are reported as warnings, or as
+// an error in strict mode.
+func (m *Merger) applyOperationIDStyle() error {
+	style := m.cfg.OperationIDStyle
+	if style == "" || m.master.Paths == nil {
+		return nil
+	}
+
+	// Map each rewritten ID to the original it came from first
+	seen := make(map[string]string)
+	var duplicates []string
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID == "" {
+			return
+		}
+		original := op.OperationID
+		op.OperationID = formatOperationID(original, style)
+
+		if first, ok := seen[op.OperationID]; ok && first != original {
+			msg := fmt.Sprintf("operationId %q of %s %s conflicts with %q as %q",
+				original, method, path, first, op.OperationID)
+			if m.cfg.Strict {
+				duplicates = append(duplicates, msg)
+			} else {
+				m.warnf(m.sources[op], "%s", msg)
+			}
+			return
+		}
+		seen[op.OperationID] = original
+	})
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("operationId style produced duplicates: %s", strings.Join(duplicates, "; "))
+	}
+	return nil
+}
+
+// ensureOperationIDs handles the input's operations that have no operationId:
+// with AutoGenerateOperationID they get one derived from method and path,
+// otherwise RequireOperationID drops them with a warning, or fails in strict mode.
+func (m *Merger) ensureOperationIDs(spec *openapi3.T, input *config.InputConfig) (*openapi3.T, error) {
+	sel := input.OperationSelection
+	if sel == nil || (!sel.RequireOperationID && !sel.AutoGenerateOperationID) || spec.Paths == nil {
+		return spec, nil
+	}
+
+	used := make(map[string]bool)
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID != "" {
+			used[op.OperationID] = true
+		}
+	})
+
+	var missing []string
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID != "" {
+			return
+		}
+		if sel.AutoGenerateOperationID {
+			op.OperationID = m.generateOperationID(method, path, used)
+			return
+		}
+		missing = append(missing, method+" "+path)
+		if !m.cfg.Strict {
+			removeOperation(spec.Paths.Value(path), method)
+			m.warnf(input.InputFile, "dropped %s %s: missing operationId", method, path)
+			m.recordSkipped(input.InputFile, method, path, "missing operationId")
+		}
+	})
+
+	if len(missing) > 0 && m.cfg.Strict {
+		return nil, fmt.Errorf("operations without operationId: %s", strings.Join(missing, ", "))
+	}
+
+	for _, path := range sortedPaths(spec.Paths) {
+		if isPathItemEmpty(spec.Paths.Value(path)) {
+			spec.Paths.Delete(path)
+		}
+	}
+
+	return spec, nil
+}
+
+// generateMissingOperationIDs gives every merged operation without an
+// operationId one derived from its method and final path.
+func (m *Merger) generateMissingOperationIDs() {
+	if !m.cfg.GenerateMissingOperationIDs || m.master.Paths == nil {
+		return
+	}
+
+	used := make(map[string]bool)
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID != "" {
+			used[op.OperationID] = true
+		}
+	})
+
+	generated := 0
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID == "" {
+			op.OperationID = m.generateOperationID(method, path, used)
+			generated++
+		}
+	})
+
+	if m.verbose && generated > 0 {
+		fmt.Fprintf(m.log, "Generated %d missing operationIds\n", generated)
+	}
+}
+
+// generateOperationID derives an operationId from an operation's method and
+// path in the configured style (get_users_id for GET /users/{id} by default).
+// A numeric suffix is added if the ID is already in used, and the result is
+// recorded there.
+func (m *Merger) generateOperationID(method, path string, used map[string]bool) string {
+	style := m.cfg.GeneratedOperationIDStyle
+	if style == "" {
+		style = config.OperationIDStyleSnake
+	}
+
+	base := strings.ToLower(method) + "/" + path
+	id := formatOperationID(base, style)
+	for n := 2; used[id]; n++ {
+		id = formatOperationID(fmt.Sprintf("%s/%d", base, n), style)
+	}
+
+	used[id] = true
+	return id
+}
+
+// formatOperationID converts id to the given style.
+func formatOperationID(id, style string) string {
+	words := splitWords(id)
+	if len(words) == 0 {
+		return id
+	}
+
+	switch style {
+	case config.OperationIDStyleSnake:
+		return strings.Join(words, "_")
+	case config.OperationIDStyleKebab:
+		return strings.Join(words, "-")
+	default:
+		var b strings.Builder
+		b.WriteString(words[0])
+		for _, w := range words[1:] {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			b.WriteString(string(r))
+		}
+		return b.String()
+	}
+}
+
+// splitWords splits an identifier into lowercase words at separators and case
+// changes, keeping acronyms together ("getHTTPStatus" -> get, http, status).
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// prefixOperationIDs prepends prefix to the operationId of every operation in
+// the spec, updating links that name them. Operations without an operationId
+// are left alone; autoGenerateOperationId fills them in beforehand.
+func prefixOperationIDs(spec *openapi3.T, prefix string) {
+	if spec.Paths == nil {
+		return
+	}
+
+	renamed := make(map[string]string)
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID == "" {
+			return
+		}
+		renamed[op.OperationID] = prefix + op.OperationID
+		op.OperationID = prefix + op.OperationID
+	})
+
+	renameLink := func(link *openapi3.LinkRef) {
+		if link == nil || link.Ref != "" || link.Value == nil {
+			return
+		}
+		if id, ok := renamed[link.Value.OperationID]; ok {
+			link.Value.OperationID = id
+		}
+	}
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.Responses == nil {
+			return
+		}
+		for _, resp := range op.Responses.Map() {
+			if resp == nil || resp.Ref != "" || resp.Value == nil {
+				continue
+			}
+			for _, link := range resp.Value.Links {
+				renameLink(link)
+			}
+		}
+	})
+	if spec.Components != nil {
+		for _, link := range spec.Components.Links {
+			renameLink(link)
+		}
+		for _, resp := range spec.Components.Responses {
+			if resp != nil && resp.Value != nil {
+				for _, link := range resp.Value.Links {
+					renameLink(link)
+				}
+			}
+		}
+	}
+}
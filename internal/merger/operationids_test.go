@@ -0,0 +1,317 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatOperationID(t *testing.T) {
+	tests := []struct {
+		id    string
+		style string
+		want  string
+	}{
+		{"list_users", config.OperationIDStyleCamel, "listUsers"},
+		{"get-user-by-id", config.OperationIDStyleCamel, "getUserById"},
+		{"CreateOrder", config.OperationIDStyleCamel, "createOrder"},
+		{"getHTTPStatus", config.OperationIDStyleCamel, "getHttpStatus"},
+		{"listUsers", config.OperationIDStyleCamel, "listUsers"},
+		{"users.list v2", config.OperationIDStyleCamel, "usersListV2"},
+		{"listUserOrders", config.OperationIDStyleSnake, "list_user_orders"},
+		{"get-user-by-id", config.OperationIDStyleSnake, "get_user_by_id"},
+		{"listUserOrders", config.OperationIDStyleKebab, "list-user-orders"},
+		{"OAuth2Token", config.OperationIDStyleKebab, "o-auth2-token"},
+		{"___", config.OperationIDStyleCamel, "___"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id+"/"+tt.style, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatOperationID(tt.id, tt.style))
+		})
+	}
+}
+
+func TestMerger_OperationIDStyle(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "list_users", "responses": {"200": {"description": "Success"}}},
+				"post": {"operationId": "create-user", "responses": {"201": {"description": "Created"}}}
+			}
+		}
+	}`
+
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"operationId": "ListOrders", "responses": {"200": {"description": "Success"}}}
+			},
+			"/users/all": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "Success"}}}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "users.json")
+	spec2Path := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: spec1Path},
+			{InputFile: spec2Path},
+		},
+		Output:           filepath.Join(tempDir, "merged.json"),
+		OperationIDStyle: config.OperationIDStyleCamel,
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	assert.Equal(t, "listUsers", m.master.Paths.Value("/users").Get.OperationID)
+	assert.Equal(t, "createUser", m.master.Paths.Value("/users").Post.OperationID)
+	assert.Equal(t, "listOrders", m.master.Paths.Value("/orders").Get.OperationID)
+	assert.Equal(t, "listUsers", m.master.Paths.Value("/users/all").Get.OperationID)
+
+	// list_users and listUsers collapse into the same ID
+	require.Len(t, m.Warnings(), 1)
+	assert.Equal(t, spec2Path, m.Warnings()[0].Source)
+	assert.Equal(t, `operationId "listUsers" of GET /users/all conflicts with "list_users" as "listUsers"`, m.Warnings()[0].Message)
+
+	cfg.Strict = true
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operationId style produced duplicates")
+}
+
+func TestGenerateOperationID(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		style  string
+		want   string
+	}{
+		{"GET", "/users/{id}", "", "get_users_id"},
+		{"POST", "/user-groups/{groupId}/members", "", "post_user_groups_group_id_members"},
+		{"GET", "/", "", "get"},
+		{"GET", "/users/{id}", config.OperationIDStyleCamel, "getUsersId"},
+		{"GET", "/users/{id}", config.OperationIDStyleKebab, "get-users-id"},
+	}
+
+	for _, tt := range tests {
+		m := New(&config.Config{GeneratedOperationIDStyle: tt.style}, false)
+		assert.Equal(t, tt.want, m.generateOperationID(tt.method, tt.path, map[string]bool{}), "%s %s", tt.method, tt.path)
+	}
+
+	// Taken IDs get the lowest free numeric suffix
+	m := New(&config.Config{GeneratedOperationIDStyle: config.OperationIDStyleCamel}, false)
+	used := map[string]bool{"getUsers": true, "getUsers2": true}
+	assert.Equal(t, "getUsers3", m.generateOperationID("GET", "/users", used))
+	assert.True(t, used["getUsers3"])
+}
+
+func TestMerger_MissingOperationIDs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}},
+				"post": {"responses": {"201": {"description": "Created"}}}
+			},
+			"/users/{id}": {
+				"get": {
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			},
+			"/legacy/user": {
+				"get": {"operationId": "get_users_id", "responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, sel *config.OperationSelectionConfig, strict bool) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath, OperationSelection: sel}},
+			Output: filepath.Join(tempDir, "merged.json"),
+			Strict: strict,
+		}
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("require drops operations", func(t *testing.T) {
+		m, err := run(t, &config.OperationSelectionConfig{RequireOperationID: true}, false)
+		require.NoError(t, err)
+
+		assert.NotNil(t, m.master.Paths.Value("/users").Get)
+		assert.Nil(t, m.master.Paths.Value("/users").Post)
+		assert.Nil(t, m.master.Paths.Value("/users/{id}"))
+		require.Len(t, m.Warnings(), 2)
+		assert.Contains(t, m.Warnings()[0].Message, "POST /users: missing operationId")
+	})
+
+	t.Run("require fails in strict mode", func(t *testing.T) {
+		_, err := run(t, &config.OperationSelectionConfig{RequireOperationID: true}, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operations without operationId: POST /users, GET /users/{id}")
+	})
+
+	t.Run("auto-generate", func(t *testing.T) {
+		sel := &config.OperationSelectionConfig{AutoGenerateOperationID: true, RequireOperationID: true}
+		for i := 0; i < 3; i++ {
+			m, err := run(t, sel, true)
+			require.NoError(t, err)
+
+			assert.Equal(t, "post_users", m.master.Paths.Value("/users").Post.OperationID)
+			// get_users_id is taken by /legacy/user, so a suffix is added
+			assert.Equal(t, "get_users_id_2", m.master.Paths.Value("/users/{id}").Get.OperationID)
+			assert.Equal(t, "listUsers", m.master.Paths.Value("/users").Get.OperationID)
+		}
+	})
+}
+
+func TestMerger_GenerateMissingOperationIDs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"operationId": "getApiUsers", "responses": {"200": {"description": "OK"}}},
+				"post": {"responses": {"201": {"description": "Created"}}}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "users.json")
+	spec2Path := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: spec1Path},
+			{InputFile: spec2Path},
+		},
+		Output:                      filepath.Join(tempDir, "merged.json"),
+		BasePath:                    "/api",
+		GenerateMissingOperationIDs: true,
+		GeneratedOperationIDStyle:   config.OperationIDStyleCamel,
+	}
+	require.NoError(t, cfg.Validate())
+
+	for i := 0; i < 3; i++ {
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		// IDs use the final path, and skip the one already taken by another input
+		assert.Equal(t, "getApiUsers2", m.master.Paths.Value("/api/users").Get.OperationID)
+		assert.Equal(t, "postApiOrders", m.master.Paths.Value("/api/orders").Post.OperationID)
+		assert.Equal(t, "getApiUsers", m.master.Paths.Value("/api/orders").Get.OperationID)
+	}
+}
+
+func TestMerger_OperationIDPrefix(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	service := func(title, path string) string {
+		return `{
+			"openapi": "3.0.0",
+			"info": {"title": "` + title + `", "version": "1.0.0"},
+			"paths": {
+				"` + path + `": {
+					"get": {
+						"operationId": "list",
+						"responses": {
+							"200": {
+								"description": "OK",
+								"links": {"first": {"operationId": "get"}}
+							}
+						}
+					},
+					"post": {"responses": {"201": {"description": "Created"}}}
+				},
+				"` + path + `/{id}": {
+					"get": {
+						"operationId": "get",
+						"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+						"responses": {"200": {"description": "OK"}}
+					}
+				}
+			}
+		}`
+	}
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	ordersPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(service("Users", "/users")), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(service("Orders", "/orders")), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{
+				InputFile:          usersPath,
+				OperationIDPrefix:  "users_",
+				OperationSelection: &config.OperationSelectionConfig{AutoGenerateOperationID: true},
+			},
+			{InputFile: ordersPath, OperationIDPrefix: "orders_"},
+		},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	var ids []string
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID != "" {
+			ids = append(ids, op.OperationID)
+		}
+	})
+	assert.ElementsMatch(t, []string{"users_list", "users_get", "users_post_users", "orders_list", "orders_get"}, ids)
+
+	// Links follow the renamed operations
+	link := m.master.Paths.Value("/orders").Get.Responses.Value("200").Value.Links["first"]
+	assert.Equal(t, "orders_get", link.Value.OperationID)
+
+	// Without autoGenerateOperationId, missing IDs stay missing
+	assert.Empty(t, m.master.Paths.Value("/orders").Post.OperationID)
+}
@@ -0,0 +1,212 @@
+package merger
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// extOrder is the extension used to order paths and operations in the output.
+const extOrder = "x-order"
+
+// orderedMap is an object that marshals its keys in insertion order.
+// Plain Go maps are always marshaled with sorted keys.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+// Set adds or replaces a key, keeping its original position.
+func (o *orderedMap) Set(key string, value interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := marshalJSONIndent(key, "")
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalJSONIndent(o.values[key], "")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (o *orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range o.keys {
+		var k, v yaml.Node
+		if err := k.Encode(key); err != nil {
+			return nil, err
+		}
+		if err := v.Encode(o.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &k, &v)
+	}
+	return node, nil
+}
+
+// xOrder returns the integer x-order extension of a decoded JSON object.
+func xOrder(value interface{}) (int, bool) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	n, ok := obj[extOrder].(float64)
+	if !ok || n != math.Trunc(n) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// xOrderLess orders keys whose values have x-order first, by ascending x-order,
+// followed by the remaining keys alphabetically.
+func xOrderLess(a string, aValue interface{}, b string, bValue interface{}) bool {
+	aOrder, aOK := xOrder(aValue)
+	bOrder, bOK := xOrder(bValue)
+	switch {
+	case aOK && bOK && aOrder != bOrder:
+		return aOrder < bOrder
+	case aOK != bOK:
+		return aOK
+	}
+	return a < b
+}
+
+// orderOperations orders the operations of a decoded path item by x-order.
+// Other path item fields come first, alphabetically. Path items without any
+// x-order operation are returned unchanged.
+func orderOperations(pathItem interface{}) interface{} {
+	item, ok := pathItem.(map[string]interface{})
+	if !ok {
+		return pathItem
+	}
+
+	var fields, methods []string
+	hasOrder := false
+	for key, value := range item {
+		if !isHTTPMethod(key) {
+			fields = append(fields, key)
+			continue
+		}
+		methods = append(methods, key)
+		if _, ok := xOrder(value); ok {
+			hasOrder = true
+		}
+	}
+	if !hasOrder {
+		return pathItem
+	}
+
+	sort.Strings(fields)
+	sort.Slice(methods, func(i, j int) bool {
+		return xOrderLess(methods[i], item[methods[i]], methods[j], item[methods[j]])
+	})
+
+	ordered := newOrderedMap()
+	for _, key := range append(fields, methods...) {
+		ordered.Set(key, item[key])
+	}
+	return ordered
+}
+
+// isHTTPMethod reports whether key names an operation in a path item.
+func isHTTPMethod(key string) bool {
+	for _, method := range httpMethods {
+		if strings.EqualFold(key, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortComponents returns the decoded components object with every component
+// map, and the components object itself, in alphabetical key order.
+func sortComponents(components interface{}) interface{} {
+	obj, ok := components.(map[string]interface{})
+	if !ok {
+		return components
+	}
+
+	ordered := newOrderedMap()
+	for _, kind := range sortedKeys(obj) {
+		value := obj[kind]
+		if entries, ok := value.(map[string]interface{}); ok && !strings.HasPrefix(kind, "x-") {
+			sorted := newOrderedMap()
+			for _, name := range sortedKeys(entries) {
+				sorted.Set(name, entries[name])
+			}
+			value = sorted
+		}
+		ordered.Set(kind, value)
+	}
+	return ordered
+}
+
+// orderResponses orders the responses of every operation of a decoded path
+// item by status code, with default after the status codes and extensions
+// last.
+func orderResponses(pathItem interface{}) {
+	item, ok := pathItem.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, value := range item {
+		op, ok := value.(map[string]interface{})
+		if !ok || !isHTTPMethod(key) {
+			continue
+		}
+		responses, ok := op["responses"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		statuses := sortedKeys(responses)
+		sort.SliceStable(statuses, func(i, j int) bool {
+			return responseRank(statuses[i]) < responseRank(statuses[j])
+		})
+
+		ordered := newOrderedMap()
+		for _, status := range statuses {
+			ordered.Set(status, responses[status])
+		}
+		op["responses"] = ordered
+	}
+}
+
+// responseRank groups response keys: status codes and ranges such as 4XX
+// first, then default, then extensions.
+func responseRank(key string) int {
+	switch {
+	case key == "default":
+		return 1
+	case strings.HasPrefix(key, "x-"):
+		return 2
+	}
+	return 0
+}
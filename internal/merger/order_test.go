@@ -0,0 +1,175 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_XOrder(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0.0"},
+		"paths": {
+			"/alpha": {
+				"get": {"operationId": "getAlpha", "responses": {"200": {"description": "OK"}}}
+			},
+			"/zeta": {
+				"x-order": 1,
+				"get": {"operationId": "getZeta", "x-order": 2, "responses": {"200": {"description": "OK"}}},
+				"post": {"operationId": "postZeta", "x-order": 1, "responses": {"201": {"description": "Created"}}},
+				"delete": {"operationId": "deleteZeta", "responses": {"204": {"description": "Deleted"}}}
+			},
+			"/middle": {
+				"x-order": 2,
+				"get": {"operationId": "getMiddle", "responses": {"200": {"description": "OK"}}}
+			},
+			"/beta": {
+				"get": {"operationId": "getBeta", "responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "shop.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	for _, ext := range []string{".json", ".yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			outputPath := filepath.Join(tempDir, "merged"+ext)
+			cfg := &config.Config{
+				Inputs: []config.InputConfig{{InputFile: specPath}},
+				Output: outputPath,
+			}
+			require.NoError(t, New(cfg, false).Merge())
+
+			data, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			assertInOrder(t, string(data),
+				"/zeta", "postZeta", "getZeta", "deleteZeta",
+				"/middle", "getMiddle",
+				"/alpha", "getAlpha",
+				"/beta", "getBeta",
+			)
+		})
+	}
+}
+
+func TestMerger_XOrderAfterPathsOrder(t *testing.T) {
+	paths := map[string]interface{}{
+		"/a": map[string]interface{}{},
+		"/b": map[string]interface{}{"x-order": float64(1)},
+		"/c": map[string]interface{}{},
+		"/d": map[string]interface{}{"x-order": 1.5},
+	}
+
+	m := New(&config.Config{PathsOrder: []string{"/c", "/missing"}}, false)
+	assert.Equal(t, []string{"/c", "/b", "/a", "/d"}, m.sortPaths(paths).keys)
+
+	m = New(&config.Config{PathsOrder: []string{"/c", "/a", "/c"}}, false)
+	assert.Equal(t, []string{"/c", "/a", "/b", "/d"}, m.sortPaths(paths).keys)
+}
+
+func BenchmarkSortPaths(b *testing.B) {
+	paths := make(map[string]interface{}, 5000)
+	for i := 0; i < 5000; i++ {
+		paths[fmt.Sprintf("/service%d/resource%d", i%50, i)] = map[string]interface{}{}
+	}
+	m := New(&config.Config{PathsOrder: []string{"/service0/resource0", "/service1/resource1"}}, false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.sortPaths(paths)
+	}
+}
+
+// assertInOrder checks that each substring occurs in s after the previous one.
+func assertInOrder(t *testing.T, s string, substrings ...string) {
+	t.Helper()
+	pos := 0
+	for _, sub := range substrings {
+		idx := strings.Index(s[pos:], sub)
+		if !assert.GreaterOrEqual(t, idx, 0, "%q not found in order", sub) {
+			return
+		}
+		pos += idx + len(sub)
+	}
+}
+
+func TestMerger_DeterministicOutput(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {
+					"operationId": "listOrders",
+					"responses": {
+						"default": {"description": "Error"},
+						"404": {"description": "Not found"},
+						"4XX": {"description": "Client error"},
+						"200": {"description": "OK"}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Zebra": {"type": "string"},
+				"Order": {"type": "object"},
+				"Apple": {"type": "string"},
+				"Mango": {"type": "integer"}
+			},
+			"parameters": {
+				"sort": {"name": "sort", "in": "query", "schema": {"type": "string"}},
+				"limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+			},
+			"responses": {
+				"NotFound": {"description": "Not found"},
+				"BadRequest": {"description": "Bad request"}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "shop.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	for _, ext := range []string{".json", ".yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			outputPath := filepath.Join(tempDir, "merged"+ext)
+			cfg := &config.Config{
+				Inputs: []config.InputConfig{{InputFile: specPath}},
+				Output: outputPath,
+			}
+
+			require.NoError(t, New(cfg, false).Merge())
+			first, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+
+			require.NoError(t, New(cfg, false).Merge())
+			second, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(first), string(second))
+			assertInOrder(t, string(first),
+				"limit", "sort",
+				"BadRequest", "NotFound",
+				"Apple", "Mango", "Order", "Zebra",
+				"OK", "Not found", "Client error", "Error",
+			)
+		})
+	}
+}
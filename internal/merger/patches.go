@@ -0,0 +1,223 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// applyPatches applies the configured JSON Patch operations to the merged
+// spec in order. The spec is patched as a plain JSON document and loaded
+// back, so a patch can touch any part of it; a patch whose target does not
+// exist, or a failed test, stops the merge.
+func (m *Merger) applyPatches() error {
+	data, err := json.Marshal(m.master)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged spec for patches: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to decode merged spec for patches: %w", err)
+	}
+
+	for i, patch := range m.cfg.Patches {
+		if doc, err = applyPatch(doc, patch); err != nil {
+			return fmt.Errorf("patches[%d]: %s %s: %w", i, patch.Op, patch.Path, err)
+		}
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return fmt.Errorf("failed to marshal patched spec: %w", err)
+	}
+	patched := &openapi3.T{}
+	if err := json.Unmarshal(data, patched); err != nil {
+		return fmt.Errorf("patched spec is not valid OpenAPI: %w", err)
+	}
+	if err := openapi3.NewLoader().ResolveRefsIn(patched, nil); err != nil {
+		return fmt.Errorf("patched spec is not valid OpenAPI: %w", err)
+	}
+	if patched.Paths == nil {
+		patched.Paths = openapi3.NewPaths()
+	}
+	if patched.Components == nil {
+		patched.Components = &openapi3.Components{}
+	}
+	if patched.Components.Examples == nil {
+		patched.Components.Examples = make(openapi3.Examples)
+	}
+
+	// Operations were decoded anew, so carry their sources over by location
+	sources := make(map[string]string)
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if source, ok := m.sources[op]; ok {
+			sources[method+" "+path] = source
+		}
+	})
+	m.sources = make(map[*openapi3.Operation]string)
+	forEachOperation(patched.Paths, func(path, method string, op *openapi3.Operation) {
+		if source, ok := sources[method+" "+path]; ok {
+			m.sources[op] = source
+		}
+	})
+	for _, tag := range patched.Tags {
+		m.tagNames[tag.Name] = true
+	}
+
+	m.master = patched
+	return nil
+}
+
+// applyPatch applies one JSON Patch operation to doc and returns the result.
+func applyPatch(doc interface{}, patch config.PatchConfig) (interface{}, error) {
+	switch patch.Op {
+	case config.PatchOpAdd:
+		return patchAdd(doc, patch.Path, stringKeys(patch.Value))
+	case config.PatchOpRemove:
+		doc, _, err := patchRemove(doc, patch.Path)
+		return doc, err
+	case config.PatchOpReplace:
+		doc, _, err := patchRemove(doc, patch.Path)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, patch.Path, stringKeys(patch.Value))
+	case config.PatchOpMove:
+		if patch.Path == patch.From || strings.HasPrefix(patch.Path, patch.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into itself", patch.From)
+		}
+		doc, value, err := patchRemove(doc, patch.From)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, patch.Path, value)
+	case config.PatchOpCopy:
+		value, ok := lookupPointer(doc, patch.From)
+		if !ok {
+			return nil, fmt.Errorf("from %s not found", patch.From)
+		}
+		copied, err := copyJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, patch.Path, copied)
+	case config.PatchOpTest:
+		value, ok := lookupPointer(doc, patch.Path)
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		if !jsonEqual(value, stringKeys(patch.Value)) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unsupported op")
+}
+
+// patchAdd adds value at pointer, replacing an existing object member or
+// inserting into an array ("-" appends). The parent must exist.
+func patchAdd(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	return patchParent(doc, pointerTokens(pointer), func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			if token == "-" {
+				return append(p, value), nil
+			}
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i > len(p) {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			return append(p[:i], append([]interface{}{value}, p[i:]...)...), nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+// patchRemove removes the value at pointer and returns it along with the
+// patched document. The value must exist.
+func patchRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	var removed interface{}
+	doc, err := patchParent(doc, pointerTokens(pointer), func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			value, ok := p[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			removed = value
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(p) {
+				return nil, fmt.Errorf("path not found")
+			}
+			removed = p[i]
+			return append(p[:i:i], p[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+	return doc, removed, err
+}
+
+// patchParent walks node to the parent of the last token and replaces it
+// with the result of fn, so arrays can grow or shrink in place.
+func patchParent(node interface{}, tokens []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		child, ok := v[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		patched, err := patchParent(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		v[tokens[0]] = patched
+		return v, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tokens[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, fmt.Errorf("path not found")
+		}
+		patched, err := patchParent(v[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = patched
+		return v, nil
+	}
+	return nil, fmt.Errorf("path not found")
+}
+
+// pointerTokens splits a JSON Pointer into its unescaped reference tokens.
+func pointerTokens(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointerToken(token)
+	}
+	return tokens
+}
+
+// copyJSONValue returns a deep copy of a decoded JSON value.
+func copyJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
@@ -0,0 +1,103 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Patches(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"summary": "List users",
+					"tags": ["users"],
+					"responses": {"200": {"$ref": "#/components/responses/Users"}}
+				},
+				"post": {"responses": {"201": {"description": "Created"}}}
+			}
+		},
+		"components": {
+			"responses": {
+				"Users": {"description": "The users"}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	newConfig := func(patches ...config.PatchConfig) *config.Config {
+		return &config.Config{
+			Inputs:   []config.InputConfig{{InputFile: specPath}},
+			Output:   filepath.Join(tempDir, "merged.json"),
+			BasePath: "/api",
+			Patches:  patches,
+		}
+	}
+
+	t.Run("applies operations in order", func(t *testing.T) {
+		cfg := newConfig(
+			config.PatchConfig{Op: config.PatchOpTest, Path: "/paths/~1api~1users/get/summary", Value: "List users"},
+			config.PatchConfig{Op: config.PatchOpAdd, Path: "/paths/~1api~1users/get/deprecated", Value: true},
+			config.PatchConfig{Op: config.PatchOpAdd, Path: "/paths/~1api~1users/get/tags/-", Value: "legacy"},
+			config.PatchConfig{Op: config.PatchOpReplace, Path: "/paths/~1api~1users/get/summary", Value: "List all users"},
+			config.PatchConfig{Op: config.PatchOpCopy, From: "/paths/~1api~1users/get/summary", Path: "/paths/~1api~1users/get/description"},
+			config.PatchConfig{Op: config.PatchOpRemove, Path: "/paths/~1api~1users/post"},
+		)
+		require.NoError(t, cfg.Validate())
+
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		users := m.master.Paths.Value("/api/users")
+		require.NotNil(t, users)
+		require.NotNil(t, users.Get)
+		assert.True(t, users.Get.Deprecated)
+		assert.Equal(t, []string{"users", "legacy"}, users.Get.Tags)
+		assert.Equal(t, "List all users", users.Get.Summary)
+		assert.Equal(t, "List all users", users.Get.Description)
+		assert.Nil(t, users.Post)
+
+		// References are resolved again after patching
+		ok := users.Get.Responses.Value("200")
+		require.NotNil(t, ok)
+		require.NotNil(t, ok.Value)
+		assert.Equal(t, "The users", *ok.Value.Description)
+		assert.Equal(t, specPath, m.sources[users.Get])
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		cfg := newConfig(config.PatchConfig{Op: config.PatchOpReplace, Path: "/paths/~1users/get/deprecated", Value: true})
+		require.NoError(t, cfg.Validate())
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "patches[0]: replace /paths/~1users/get/deprecated: path not found")
+	})
+
+	t.Run("failed test", func(t *testing.T) {
+		cfg := newConfig(config.PatchConfig{Op: config.PatchOpTest, Path: "/info/title", Value: "Accounts"})
+		require.NoError(t, cfg.Validate())
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "patches[0]: test /info/title: test failed")
+	})
+
+	t.Run("invalid op", func(t *testing.T) {
+		cfg := newConfig(config.PatchConfig{Op: "set", Path: "/info/title"})
+		assert.ErrorContains(t, cfg.Validate(), `patches[0]: invalid op "set"`)
+	})
+}
@@ -0,0 +1,61 @@
+package merger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// resolvePathConflict handles an operation of src that differs from the one
+// already merged for the same path and method, according to OnPathConflict.
+func (m *Merger) resolvePathConflict(path, method string, existing, src *openapi3.PathItem, input *config.InputConfig) error {
+	earlier := m.sources[existing.GetOperation(method)]
+
+	switch m.cfg.OnPathConflict {
+	case config.PathConflictError:
+		return fmt.Errorf("%s %s is defined differently by %s and %s", method, path, earlier, input.InputFile)
+
+	case config.PathConflictPrefix:
+		prefixed := conflictPathPrefix(input) + path
+		target := m.master.Paths.Value(prefixed)
+		if target == nil {
+			target = &openapi3.PathItem{
+				Summary:     src.Summary,
+				Description: src.Description,
+				Parameters:  src.Parameters,
+				Extensions:  src.Extensions,
+			}
+			m.master.Paths.Set(prefixed, target)
+		}
+		if target.GetOperation(method) != nil {
+			return fmt.Errorf("%s %s conflicts with %s, and the prefixed path %s is also taken", method, path, earlier, prefixed)
+		}
+		target.SetOperation(method, src.GetOperation(method))
+		m.warnf(input.InputFile, "%s %s differs from the operation already merged from %s; merged as %s %s",
+			method, path, earlier, method, prefixed)
+		return nil
+
+	default:
+		m.warnf(input.InputFile, "%s %s differs from the operation already merged from %s; keeping the earlier one",
+			method, path, earlier)
+		return nil
+	}
+}
+
+// conflictPathPrefix derives a path prefix from the input's label or file
+// name, e.g. "OrdersAPI.yaml" becomes "/orders-api".
+func conflictPathPrefix(input *config.InputConfig) string {
+	name := input.Label
+	if name == "" {
+		base := filepath.Base(input.InputFile)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	words := splitWords(name)
+	if len(words) == 0 {
+		return "/input"
+	}
+	return "/" + strings.Join(words, "-")
+}
@@ -0,0 +1,97 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_OnPathConflict(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/health": {
+				"get": {"operationId": "health", "responses": {"200": {"description": "OK"}}}
+			},
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "All users"}}}
+			}
+		}
+	}`
+	// Both services expose an identical /health, but claim GET /users differently
+	accounts := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Accounts", "version": "1.0.0"},
+		"paths": {
+			"/health": {
+				"get": {"operationId": "health", "responses": {"200": {"description": "OK"}}}
+			},
+			"/users": {
+				"get": {"operationId": "listAccountUsers", "responses": {"200": {"description": "Account users"}}}
+			}
+		}
+	}`
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	accountsPath := filepath.Join(tempDir, "AccountsService.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(users), 0644))
+	require.NoError(t, os.WriteFile(accountsPath, []byte(accounts), 0644))
+
+	run := func(mode string) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs:         []config.InputConfig{{InputFile: usersPath}, {InputFile: accountsPath}},
+			Output:         filepath.Join(tempDir, "merged.json"),
+			OnPathConflict: mode,
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("first", func(t *testing.T) {
+		m, err := run(config.PathConflictFirst)
+		require.NoError(t, err)
+		assert.Equal(t, "listUsers", m.master.Paths.Value("/users").Get.OperationID)
+		require.Len(t, m.Warnings(), 1)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := run(config.PathConflictError)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GET /users is defined differently by "+usersPath+" and "+accountsPath)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		m, err := run(config.PathConflictPrefix)
+		require.NoError(t, err)
+		assert.Equal(t, "listUsers", m.master.Paths.Value("/users").Get.OperationID)
+		moved := m.master.Paths.Value("/accounts-service/users")
+		require.NotNil(t, moved)
+		assert.Equal(t, "listAccountUsers", moved.Get.OperationID)
+
+		// Identical duplicates are not conflicts
+		assert.Nil(t, m.master.Paths.Value("/accounts-service/health"))
+		require.Len(t, m.Warnings(), 1)
+		assert.Contains(t, m.Warnings()[0].Message, "merged as GET /accounts-service/users")
+	})
+}
+
+func TestConfig_ValidateOnPathConflict(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:         []config.InputConfig{{InputFile: "api.json"}},
+		Output:         "merged.json",
+		OnPathConflict: "last",
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid onPathConflict "last"`)
+}
@@ -0,0 +1,48 @@
+package merger
+
+import (
+	"encoding/json"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// pathItemsKey is the components key of the reusable path items of OpenAPI
+// 3.1. The loader has no field for them and keeps them among the component
+// extensions.
+const pathItemsKey = "pathItems"
+
+// componentPathItems returns the reusable path items of components, or nil if
+// there are none. They are decoded on first use and stored back typed, so
+// they can be renamed, merged and have their refs rewritten like any other
+// component.
+func componentPathItems(c *openapi3.Components) map[string]*openapi3.PathItem {
+	if c == nil {
+		return nil
+	}
+	raw, ok := c.Extensions[pathItemsKey]
+	if !ok {
+		return nil
+	}
+	if pathItems, ok := raw.(map[string]*openapi3.PathItem); ok {
+		return pathItems
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var pathItems map[string]*openapi3.PathItem
+	if err := json.Unmarshal(data, &pathItems); err != nil {
+		return nil
+	}
+	c.Extensions[pathItemsKey] = pathItems
+	return pathItems
+}
+
+// setComponentPathItems replaces the reusable path items of components.
+func setComponentPathItems(c *openapi3.Components, pathItems map[string]*openapi3.PathItem) {
+	if c.Extensions == nil {
+		c.Extensions = make(map[string]interface{})
+	}
+	c.Extensions[pathItemsKey] = pathItems
+}
@@ -0,0 +1,84 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_DisputeRenamesReferencedPathItems(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := func(path, title string) string {
+		return fmt.Sprintf(`{
+			"openapi": "3.1.0",
+			"info": {"title": %q, "version": "1.0.0"},
+			"paths": {
+				%q: {"$ref": "#/components/pathItems/Items"}
+			},
+			"components": {
+				"pathItems": {
+					"Items": {
+						"get": {
+							"summary": %q,
+							"responses": {
+								"200": {
+									"description": "OK",
+									"content": {
+										"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}
+									}
+								}
+							}
+						}
+					}
+				},
+				"schemas": {
+					"Item": {"type": "object", "title": %q}
+				}
+			}
+		}`, title, path, title, title)
+	}
+
+	var inputs []config.InputConfig
+	for i, s := range []string{spec("/users", "Users"), spec("/orders", "Orders")} {
+		specPath := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(specPath, []byte(s), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+	inputs[1].Dispute = &config.DisputeConfig{Prefix: "Orders"}
+
+	cfg := &config.Config{
+		Inputs: inputs,
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	assert.Equal(t, "#/components/pathItems/Items", m.master.Paths.Value("/users").Ref)
+	assert.Equal(t, "#/components/pathItems/OrdersItems", m.master.Paths.Value("/orders").Ref)
+
+	pathItems := componentPathItems(m.master.Components)
+	require.Contains(t, pathItems, "Items")
+	require.Contains(t, pathItems, "OrdersItems")
+	assert.Equal(t, "Users", pathItems["Items"].Get.Summary)
+	assert.Equal(t, "Orders", pathItems["OrdersItems"].Get.Summary)
+
+	schemaRef := func(name string) string {
+		return pathItems[name].Get.Responses.Value("200").Value.Content.Get("application/json").Schema.Ref
+	}
+	assert.Equal(t, "#/components/schemas/Item", schemaRef("Items"))
+	assert.Equal(t, "#/components/schemas/OrdersItem", schemaRef("OrdersItems"))
+
+	dangling, err := danglingRefs(m.master)
+	require.NoError(t, err)
+	assert.Empty(t, dangling)
+}
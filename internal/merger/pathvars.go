@@ -0,0 +1,143 @@
+package merger
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// normalizePathVariables renames every path variable to its canonical name,
+// so that /items/{id} and /items/{itemId} become the same /items/{itemId}
+// route, and renames the matching path parameters along with it. Renamed
+// paths are recorded as rewrites of source.
+func (m *Merger) normalizePathVariables(spec *openapi3.T, source string) *openapi3.T {
+	if m.cfg.PathVariableNormalization != config.PathVariableNormalizationCanonical || spec.Paths == nil {
+		return spec
+	}
+
+	newPaths := openapi3.NewPaths()
+	newPaths.Extensions = spec.Paths.Extensions
+	for _, path := range sortedPaths(spec.Paths) {
+		pathItem := spec.Paths.Value(path)
+		newPath, renames := m.canonicalPath(path)
+		if len(renames) > 0 {
+			renamePathParameters(pathItem, renames)
+		}
+		m.recordPathRewrite(source, path, newPath)
+
+		// Two routes of the same input may now coincide
+		if existing := newPaths.Value(newPath); existing != nil {
+			mergePathItem(existing, pathItem)
+			continue
+		}
+		newPaths.Set(newPath, pathItem)
+	}
+
+	spec.Paths = newPaths
+	return spec
+}
+
+// canonicalPath returns path with its variables renamed, and the renames made
+// (old name to new name). A variable keeps its name when no static segment
+// precedes it or when the canonical name is already taken in the path.
+func (m *Merger) canonicalPath(path string) (string, map[string]string) {
+	segments := strings.Split(path, "/")
+	used := make(map[string]bool)
+	for _, segment := range segments {
+		if name, ok := pathVariable(segment); ok {
+			used[name] = true
+		}
+	}
+
+	renames := make(map[string]string)
+	for i, segment := range segments {
+		name, ok := pathVariable(segment)
+		if !ok || i == 0 {
+			continue
+		}
+		prev := segments[i-1]
+		if prev == "" || strings.ContainsAny(prev, "{}") {
+			continue
+		}
+
+		canonical := m.cfg.PathVariableNames[prev]
+		if canonical == "" {
+			canonical = formatOperationID(singular(prev)+"/id", config.OperationIDStyleCamel)
+		}
+		if canonical == name || used[canonical] {
+			continue
+		}
+
+		used[canonical] = true
+		renames[name] = canonical
+		segments[i] = "{" + canonical + "}"
+	}
+
+	return strings.Join(segments, "/"), renames
+}
+
+// pathTemplateExpr matches a template expression in a path, such as "{id}".
+var pathTemplateExpr = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// pathVariables returns the names of the template expressions in a path, sorted.
+func pathVariables(path string) []string {
+	var names []string
+	for _, match := range pathTemplateExpr.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	slices.Sort(names)
+	return names
+}
+
+// pathVariable returns the variable name of a segment that is exactly one
+// template expression, such as "{id}".
+func pathVariable(segment string) (string, bool) {
+	if len(segment) < 3 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+		return "", false
+	}
+	name := segment[1 : len(segment)-1]
+	if strings.ContainsAny(name, "{}") {
+		return "", false
+	}
+	return name, true
+}
+
+// singular naively turns an English plural path segment into its singular.
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// renamePathParameters renames the path parameters of a path item and its
+// operations. Referenced parameters are copied inline so that the shared
+// component keeps its name.
+func renamePathParameters(pathItem *openapi3.PathItem, renames map[string]string) {
+	rename := func(params openapi3.Parameters) {
+		for i, param := range params {
+			if param == nil || param.Value == nil || param.Value.In != openapi3.ParameterInPath {
+				continue
+			}
+			newName, ok := renames[param.Value.Name]
+			if !ok {
+				continue
+			}
+			value := *param.Value
+			value.Name = newName
+			params[i] = &openapi3.ParameterRef{Value: &value}
+		}
+	}
+
+	rename(pathItem.Parameters)
+	for _, op := range pathItem.Operations() {
+		rename(op.Parameters)
+	}
+}
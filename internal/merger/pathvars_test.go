@@ -0,0 +1,116 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_PathVariableNormalization(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Items", "version": "1.0.0"},
+		"paths": {
+			"/items/{id}": {
+				"get": {
+					"operationId": "getItem",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		}
+	}`
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Item Writes", "version": "1.0.0"},
+		"paths": {
+			"/items/{itemId}": {
+				"parameters": [{"$ref": "#/components/parameters/ItemId"}],
+				"delete": {
+					"operationId": "deleteItem",
+					"responses": {"204": {"description": "Deleted"}}
+				}
+			},
+			"/people/{id}": {
+				"get": {
+					"operationId": "getPerson",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"components": {
+			"parameters": {
+				"ItemId": {"name": "itemId", "in": "path", "required": true, "schema": {"type": "string"}}
+			}
+		}
+	}`
+
+	input1 := filepath.Join(tempDir, "items.json")
+	input2 := filepath.Join(tempDir, "writes.json")
+	require.NoError(t, os.WriteFile(input1, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(input2, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs:                    []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output:                    filepath.Join(tempDir, "merged.json"),
+		PathVariableNormalization: config.PathVariableNormalizationCanonical,
+		PathVariableNames:         map[string]string{"people": "personId"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	paths := m.master.Paths
+	assert.Nil(t, paths.Value("/items/{id}"))
+	assert.Nil(t, paths.Value("/people/{id}"))
+
+	item := paths.Value("/items/{itemId}")
+	require.NotNil(t, item)
+	require.NotNil(t, item.Get)
+	require.NotNil(t, item.Delete)
+	assert.Equal(t, "itemId", item.Get.Parameters[0].Value.Name)
+	assert.Equal(t, "itemId", item.Parameters[0].Value.Name)
+
+	person := paths.Value("/people/{personId}")
+	require.NotNil(t, person)
+	assert.Equal(t, "personId", person.Get.Parameters[0].Value.Name)
+	assert.Empty(t, m.Warnings())
+}
+
+func TestMerger_CanonicalPath(t *testing.T) {
+	m := New(&config.Config{PathVariableNormalization: config.PathVariableNormalizationCanonical}, false)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/items/{id}", "/items/{itemId}"},
+		{"/categories/{cat}/items/{id}", "/categories/{categoryId}/items/{itemId}"},
+		{"/{tenant}/users/{user_id}", "/{tenant}/users/{userId}"},
+		{"/files/{id}.json", "/files/{id}.json"},
+		{"/users/{a}/users/{b}", "/users/{userId}/users/{b}"},
+	}
+	for _, tt := range tests {
+		got, _ := m.canonicalPath(tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+}
+
+func TestConfig_ValidatePathVariableNormalization(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:                    []config.InputConfig{{InputFile: "api.json"}},
+		Output:                    "merged.json",
+		PathVariableNormalization: "positional",
+	}
+	assert.EqualError(t, cfg.Validate(), `invalid pathVariableNormalization "positional" (expected canonical)`)
+}
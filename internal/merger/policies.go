@@ -0,0 +1,30 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// applyOperationPolicies merges the extensions of every operation policy into
+// the merged operations it matches. Policies are applied in config order, so a
+// later policy overrides an extension set by an earlier one.
+func (m *Merger) applyOperationPolicies() {
+	if len(m.cfg.OperationPolicies) == 0 || m.master.Paths == nil {
+		return
+	}
+
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		for _, policy := range m.cfg.OperationPolicies {
+			filter := config.PathFilter{Path: policy.Path, Method: policy.Method}
+			if !matchPathFilter(path, method, filter) {
+				continue
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(map[string]interface{})
+			}
+			for key, value := range policy.Extensions {
+				op.Extensions[key] = stringKeys(value)
+			}
+		}
+	})
+}
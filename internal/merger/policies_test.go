@@ -0,0 +1,71 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_OperationPolicies(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Reports", "version": "1.0.0"},
+		"paths": {
+			"/reports": {
+				"get": {"responses": {"200": {"description": "OK"}}},
+				"post": {
+					"x-timeout": "5s",
+					"responses": {"202": {"description": "Accepted"}}
+				}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "reports.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:   []config.InputConfig{{InputFile: specPath}},
+		Output:   filepath.Join(tempDir, "merged.json"),
+		BasePath: "/api",
+		OperationPolicies: []config.OperationPolicyConfig{
+			{
+				Path:       "/api/reports",
+				Method:     "post",
+				Extensions: map[string]interface{}{"x-timeout": "60s"},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	reports := m.master.Paths.Value("/api/reports")
+	require.NotNil(t, reports)
+	assert.Equal(t, "60s", reports.Post.Extensions["x-timeout"])
+	assert.NotContains(t, reports.Get.Extensions, "x-timeout")
+	assert.NotContains(t, m.master.Paths.Value("/api/health").Get.Extensions, "x-timeout")
+}
+
+func TestConfig_ValidateOperationPolicies(t *testing.T) {
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: "api.json"}},
+		Output: "merged.json",
+		OperationPolicies: []config.OperationPolicyConfig{
+			{Path: "/reports", Extensions: map[string]interface{}{"timeout": "5s"}},
+		},
+	}
+	assert.EqualError(t, cfg.Validate(), `operationPolicies[0]: extension "timeout" must start with x-`)
+}
@@ -0,0 +1,318 @@
+package merger
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// postmanSchema identifies the Postman collection format that is exported.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection is a Postman v2.1 collection.
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanInfo describes a collection.
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem is either a folder (with Item) or a request (with Request).
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+}
+
+// PostmanRequest is a single request of a collection.
+type PostmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []PostmanHeader `json:"header"`
+	URL         PostmanURL      `json:"url"`
+	Body        *PostmanBody    `json:"body,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// PostmanHeader is a request header.
+type PostmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PostmanURL is a request URL, both raw and split into parts.
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []PostmanVariable `json:"query,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanVariable is a key/value pair used for collection variables, query
+// parameters and path variables.
+type PostmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PostmanBody is a raw request body.
+type PostmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// ExportPostman runs the merge without writing output and converts the result
+// into a Postman collection.
+func (m *Merger) ExportPostman() (*PostmanCollection, error) {
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+	return PostmanCollectionFor(m.master), nil
+}
+
+// PostmanCollectionFor converts a specification into a Postman collection with
+// a folder per tag, in the order of the root tags. Each operation becomes a
+// request in the folder of its first tag; untagged operations are listed after
+// the folders. Requests use a {{baseUrl}} variable set to the first server.
+func PostmanCollectionFor(spec *openapi3.T) *PostmanCollection {
+	collection := &PostmanCollection{
+		Info: PostmanInfo{Schema: postmanSchema},
+		Item: []PostmanItem{},
+	}
+	if spec.Info != nil {
+		collection.Info.Name = spec.Info.Title
+		collection.Info.Description = spec.Info.Description
+	}
+	baseURL := ""
+	if len(spec.Servers) > 0 && spec.Servers[0] != nil {
+		baseURL = strings.TrimSuffix(spec.Servers[0].URL, "/")
+	}
+	collection.Variable = []PostmanVariable{{Key: "baseUrl", Value: baseURL}}
+
+	// Folders follow the root tags, then any undeclared tags as first seen
+	folders := make(map[string]*PostmanItem)
+	var order []string
+	addFolder := func(name, description string) *PostmanItem {
+		if folder, ok := folders[name]; ok {
+			return folder
+		}
+		folder := &PostmanItem{Name: name, Description: description, Item: []PostmanItem{}}
+		folders[name] = folder
+		order = append(order, name)
+		return folder
+	}
+	for _, tag := range spec.Tags {
+		if tag != nil {
+			addFolder(tag.Name, tag.Description)
+		}
+	}
+
+	var untagged []PostmanItem
+	if spec.Paths != nil {
+		forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+			item := postmanRequestItem(path, method, spec.Paths.Value(path), op)
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, item)
+				return
+			}
+			folder := addFolder(op.Tags[0], "")
+			folder.Item = append(folder.Item, item)
+		})
+	}
+
+	for _, name := range order {
+		if folder := folders[name]; len(folder.Item) > 0 {
+			collection.Item = append(collection.Item, *folder)
+		}
+	}
+	collection.Item = append(collection.Item, untagged...)
+
+	return collection
+}
+
+// postmanRequestItem converts a single operation into a request item.
+func postmanRequestItem(path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) PostmanItem {
+	name := op.Summary
+	if name == "" {
+		name = op.OperationID
+	}
+	if name == "" {
+		name = method + " " + path
+	}
+
+	req := &PostmanRequest{
+		Method:      method,
+		Header:      []PostmanHeader{},
+		Description: op.Description,
+	}
+
+	// Path segments use Postman's :name syntax for variables
+	segments := []string{}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if variable, ok := pathVariable(segment); ok {
+			segment = ":" + variable
+		}
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	req.URL = PostmanURL{
+		Raw:  "{{baseUrl}}/" + strings.Join(segments, "/"),
+		Host: []string{"{{baseUrl}}"},
+		Path: segments,
+	}
+
+	params := appendMissingParameters(append(openapi3.Parameters{}, op.Parameters...), pathItem.Parameters)
+	for _, param := range params {
+		if param == nil || param.Value == nil {
+			continue
+		}
+		p := param.Value
+		value := parameterExample(p)
+		switch p.In {
+		case openapi3.ParameterInPath:
+			req.URL.Variable = append(req.URL.Variable, PostmanVariable{Key: p.Name, Value: value})
+		case openapi3.ParameterInQuery:
+			req.URL.Query = append(req.URL.Query, PostmanVariable{Key: p.Name, Value: value})
+		case openapi3.ParameterInHeader:
+			req.Header = append(req.Header, PostmanHeader{Key: p.Name, Value: value})
+		}
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		if mediaType, media := jsonMediaType(op.RequestBody.Value.Content); media != nil {
+			req.Header = append(req.Header, PostmanHeader{Key: "Content-Type", Value: mediaType})
+			if example, ok := mediaTypeExample(media); ok {
+				data, _ := json.MarshalIndent(example, "", "  ")
+				req.Body = &PostmanBody{
+					Mode:    "raw",
+					Raw:     string(data),
+					Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+				}
+			}
+		}
+	}
+
+	return PostmanItem{Name: name, Request: req}
+}
+
+// jsonMediaType returns the first JSON media type of content, preferring
+// application/json.
+func jsonMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if media := content.Get("application/json"); media != nil {
+		return "application/json", media
+	}
+	for _, mediaType := range sortedKeys(content) {
+		if strings.HasSuffix(mediaType, "+json") {
+			return mediaType, content[mediaType]
+		}
+	}
+	return "", nil
+}
+
+// mediaTypeExample returns the example of a media type: its example, its first
+// named example, the schema example, or one generated from the schema.
+func mediaTypeExample(media *openapi3.MediaType) (interface{}, bool) {
+	if media.Example != nil {
+		return media.Example, true
+	}
+	for _, name := range sortedKeys(media.Examples) {
+		if ex := media.Examples[name]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+			return ex.Value.Value, true
+		}
+	}
+	if media.Schema != nil && media.Schema.Value != nil {
+		return schemaExample(media.Schema.Value, 0), true
+	}
+	return nil, false
+}
+
+// parameterExample returns a parameter's example as text, or an empty string.
+func parameterExample(p *openapi3.Parameter) string {
+	example := p.Example
+	if example == nil && p.Schema != nil && p.Schema.Value != nil {
+		if example = p.Schema.Value.Example; example == nil {
+			example = p.Schema.Value.Default
+		}
+	}
+	switch v := example.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// maxSchemaExampleDepth bounds schemaExample on recursive schemas.
+const maxSchemaExampleDepth = 5
+
+// schemaExample builds an example value from a schema: its example or default
+// when set, otherwise a placeholder for its type.
+func schemaExample(schema *openapi3.Schema, depth int) interface{} {
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if depth >= maxSchemaExampleDepth {
+		return nil
+	}
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, s := range schema.AllOf {
+			if s == nil || s.Value == nil {
+				continue
+			}
+			if obj, ok := schemaExample(s.Value, depth+1).(map[string]interface{}); ok {
+				maps.Copy(merged, obj)
+			}
+		}
+		return merged
+	}
+	for _, alternatives := range []openapi3.SchemaRefs{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) > 0 && alternatives[0] != nil && alternatives[0].Value != nil {
+			return schemaExample(alternatives[0].Value, depth+1)
+		}
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeObject) || len(schema.Properties) > 0:
+		obj := make(map[string]interface{})
+		for _, name := range sortedKeys(schema.Properties) {
+			if prop := schema.Properties[name]; prop != nil && prop.Value != nil {
+				obj[name] = schemaExample(prop.Value, depth+1)
+			}
+		}
+		return obj
+	case schema.Type.Is(openapi3.TypeArray):
+		if schema.Items != nil && schema.Items.Value != nil {
+			return []interface{}{schemaExample(schema.Items.Value, depth+1)}
+		}
+		return []interface{}{}
+	case schema.Type.Is(openapi3.TypeString):
+		return "string"
+	case schema.Type.Is(openapi3.TypeInteger), schema.Type.Is(openapi3.TypeNumber):
+		return 0
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return false
+	default:
+		return nil
+	}
+}
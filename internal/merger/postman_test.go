@@ -0,0 +1,106 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_ExportPostman(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Shop", "version": "1.0.0"},
+		"tags": [{"name": "Users", "description": "User operations"}, {"name": "Orders"}],
+		"paths": {
+			"/users": {
+				"get": {
+					"tags": ["Users"],
+					"summary": "List users",
+					"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 20}}],
+					"responses": {"200": {"description": "OK"}}
+				},
+				"post": {
+					"tags": ["Users"],
+					"operationId": "createUser",
+					"requestBody": {
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"properties": {
+										"name": {"type": "string", "example": "Alice"},
+										"admin": {"type": "boolean"}
+									}
+								}
+							}
+						}
+					},
+					"responses": {"201": {"description": "Created"}}
+				}
+			},
+			"/orders/{orderId}": {
+				"parameters": [{"name": "orderId", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"get": {
+					"tags": ["Orders"],
+					"responses": {"200": {"description": "OK"}}
+				}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+	specPath := filepath.Join(tempDir, "shop.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:  []config.InputConfig{{InputFile: specPath}},
+		Output:  filepath.Join(tempDir, "merged.json"),
+		Info:    &config.InfoConfig{Title: "Shop API", Version: "1.0.0"},
+		Servers: []config.ServerConfig{{URL: "https://api.example.com/"}},
+	}
+
+	collection, err := New(cfg, false).ExportPostman()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shop API", collection.Info.Name)
+	assert.Equal(t, postmanSchema, collection.Info.Schema)
+	assert.Equal(t, []PostmanVariable{{Key: "baseUrl", Value: "https://api.example.com"}}, collection.Variable)
+
+	// One folder per tag, then the untagged request
+	require.Len(t, collection.Item, 3)
+	users, orders, health := collection.Item[0], collection.Item[1], collection.Item[2]
+	assert.Equal(t, "Users", users.Name)
+	assert.Equal(t, "User operations", users.Description)
+	assert.Equal(t, "Orders", orders.Name)
+	require.NotNil(t, health.Request)
+	assert.Equal(t, "GET /health", health.Name)
+
+	// One request per operation
+	require.Len(t, users.Item, 2)
+	list, create := users.Item[0], users.Item[1]
+	assert.Equal(t, "List users", list.Name)
+	assert.Equal(t, "GET", list.Request.Method)
+	assert.Equal(t, "{{baseUrl}}/users", list.Request.URL.Raw)
+	assert.Equal(t, []PostmanVariable{{Key: "limit", Value: "20"}}, list.Request.URL.Query)
+
+	assert.Equal(t, "createUser", create.Name)
+	assert.Equal(t, "POST", create.Request.Method)
+	require.NotNil(t, create.Request.Body)
+	assert.JSONEq(t, `{"name": "Alice", "admin": false}`, create.Request.Body.Raw)
+	assert.Contains(t, create.Request.Header, PostmanHeader{Key: "Content-Type", Value: "application/json"})
+
+	require.Len(t, orders.Item, 1)
+	get := orders.Item[0].Request
+	assert.Equal(t, "{{baseUrl}}/orders/:orderId", get.URL.Raw)
+	assert.Equal(t, []string{"orders", ":orderId"}, get.URL.Path)
+	assert.Equal(t, []PostmanVariable{{Key: "orderId", Value: ""}}, get.URL.Variable)
+}
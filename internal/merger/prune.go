@@ -0,0 +1,103 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// pruneUnusedComponents removes components that are not reachable through
+// $refs from outside components, following references between components.
+// Security schemes are referenced by name, not $ref, so they are kept. It
+// returns the removed references, e.g. "#/components/schemas/Unused".
+func pruneUnusedComponents(spec *openapi3.T) ([]string, error) {
+	if spec.Components == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	delete(doc, "components")
+
+	// Walk everything outside components, then every component reached
+	used := make(map[string]bool)
+	var queue []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix) {
+				if target := componentOfRef(ref); !used[target] {
+					used[target] = true
+					queue = append(queue, target)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	for len(queue) > 0 {
+		target := queue[0]
+		queue = queue[1:]
+		kind, name, _ := strings.Cut(strings.TrimPrefix(target, componentsRefPrefix), "/")
+		if bucket, ok := components[kind].(map[string]interface{}); ok {
+			walk(bucket[unescapePointerToken(name)])
+		}
+	}
+
+	var removed []string
+	prune := func(kind string, names []string, del func(string)) {
+		for _, name := range names {
+			ref := componentsRefPrefix + kind + "/" + escapePointerToken(name)
+			if !used[ref] {
+				del(name)
+				removed = append(removed, ref)
+			}
+		}
+	}
+
+	c := spec.Components
+	prune("schemas", sortedKeys(c.Schemas), func(name string) { delete(c.Schemas, name) })
+	prune("parameters", sortedKeys(c.Parameters), func(name string) { delete(c.Parameters, name) })
+	prune("headers", sortedKeys(c.Headers), func(name string) { delete(c.Headers, name) })
+	prune("requestBodies", sortedKeys(c.RequestBodies), func(name string) { delete(c.RequestBodies, name) })
+	prune("responses", sortedKeys(c.Responses), func(name string) { delete(c.Responses, name) })
+	prune("examples", sortedKeys(c.Examples), func(name string) { delete(c.Examples, name) })
+	prune("links", sortedKeys(c.Links), func(name string) { delete(c.Links, name) })
+	prune("callbacks", sortedKeys(c.Callbacks), func(name string) { delete(c.Callbacks, name) })
+
+	return removed, nil
+}
+
+// componentOfRef trims a reference pointing inside a component (such as
+// "#/components/parameters/Limit/schema") to the component itself.
+func componentOfRef(ref string) string {
+	parts := strings.SplitN(strings.TrimPrefix(ref, componentsRefPrefix), "/", 3)
+	if len(parts) < 2 {
+		return ref
+	}
+	return componentsRefPrefix + parts[0] + "/" + parts[1]
+}
+
+func escapePointerToken(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointerToken(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
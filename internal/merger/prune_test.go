@@ -0,0 +1,156 @@
+package merger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneUnusedComponents(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/Limit"}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/UserList"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"UserList": {"type": "array", "items": {"$ref": "#/components/schemas/User"}},
+				"User": {"type": "object", "properties": {"id": {"type": "integer"}}},
+				"Orphan": {"type": "object", "properties": {"peer": {"$ref": "#/components/schemas/OrphanPeer"}}},
+				"OrphanPeer": {"type": "string"}
+			},
+			"parameters": {
+				"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+				"Offset": {"name": "offset", "in": "query", "schema": {"type": "integer"}}
+			},
+			"securitySchemes": {
+				"bearerAuth": {"type": "http", "scheme": "bearer"}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate(context.Background()))
+
+	removed, err := pruneUnusedComponents(spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"#/components/schemas/Orphan",
+		"#/components/schemas/OrphanPeer",
+		"#/components/parameters/Offset",
+	}, removed)
+	assert.Contains(t, spec.Components.Schemas, "UserList")
+	assert.Contains(t, spec.Components.Schemas, "User")
+	assert.Contains(t, spec.Components.Parameters, "Limit")
+	assert.Contains(t, spec.Components.SecuritySchemes, "bearerAuth", "security schemes are never pruned")
+}
+
+func TestMerger_PruneUnusedComponents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"post": {
+					"tags": ["Orders"],
+					"requestBody": {"$ref": "#/components/requestBodies/NewOrder"},
+					"responses": {"201": {"description": "Created"}},
+					"callbacks": {
+						"shipped": {"$ref": "#/components/callbacks/Shipped"}
+					}
+				}
+			},
+			"/admin/audit": {
+				"get": {
+					"tags": ["Admin"],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/AuditLog"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Order": {
+					"allOf": [
+						{"$ref": "#/components/schemas/Entity"},
+						{"type": "object", "properties": {"total": {"type": "number"}}}
+					]
+				},
+				"Entity": {"type": "object", "properties": {"id": {"type": "string"}}},
+				"Shipment": {"type": "object", "properties": {"carrier": {"type": "string"}}},
+				"AuditLog": {"type": "array", "items": {"$ref": "#/components/schemas/AuditEntry"}},
+				"AuditEntry": {"type": "object"}
+			},
+			"requestBodies": {
+				"NewOrder": {
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Order"}}}
+				}
+			},
+			"callbacks": {
+				"Shipped": {
+					"{$request.body#/callbackUrl}": {
+						"post": {
+							"requestBody": {
+								"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Shipment"}}}
+							},
+							"responses": {"200": {"description": "OK"}}
+						}
+					}
+				}
+			},
+			"headers": {
+				"X-Unused": {"schema": {"type": "string"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{
+			InputFile:          specPath,
+			OperationSelection: &config.OperationSelectionConfig{ExcludeTags: []string{"Admin"}},
+		}},
+		Output:                filepath.Join(tempDir, "merged.json"),
+		PruneUnusedComponents: true,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemas := m.master.Components.Schemas
+	assert.Contains(t, schemas, "Order")
+	assert.Contains(t, schemas, "Entity", "reachable only through allOf")
+	assert.Contains(t, schemas, "Shipment", "reachable only through a callback")
+	assert.NotContains(t, schemas, "AuditLog", "its only operation was filtered out")
+	assert.NotContains(t, schemas, "AuditEntry")
+	assert.Contains(t, m.master.Components.RequestBodies, "NewOrder")
+	assert.Contains(t, m.master.Components.Callbacks, "Shipped")
+	assert.Empty(t, m.master.Components.Headers)
+}
@@ -0,0 +1,49 @@
+package merger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// publishOutput POSTs the serialized merged spec to OutputURL, with a
+// Content-Type matching the output format. Only OutputURLHeaders are added
+// for authentication; the GitHub token used for fetching inputs is never
+// sent to the output URL.
+func (m *Merger) publishOutput(data []byte, isYAML bool) error {
+	req, err := http.NewRequest("POST", m.cfg.OutputURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create publish request: %w", err)
+	}
+	req.Header.Set("User-Agent", m.userAgent())
+
+	if isYAML {
+		req.Header.Set("Content-Type", "application/yaml")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range m.cfg.OutputURLHeaders {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := m.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish output: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("publishing output to %s failed with status %s: %s",
+			m.cfg.OutputURL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Published output to %s\n", m.cfg.OutputURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package merger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_OutputURL(t *testing.T) {
+	var gotMethod, gotContentType, gotAuth string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		gotAuth = r.Header.Get("Authorization")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	t.Setenv("GATEWAY_TOKEN", "secret")
+	cfg := &config.Config{
+		Inputs:           []config.InputConfig{{InputFile: specPath}},
+		Output:           filepath.Join(tempDir, "merged.yaml"),
+		OutputURL:        server.URL + "/apis/platform",
+		OutputURLHeaders: map[string]string{"Authorization": "Bearer ${GATEWAY_TOKEN}"},
+	}
+	require.NoError(t, cfg.Validate())
+	require.NoError(t, New(cfg, false).Merge())
+
+	written, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "application/yaml", gotContentType)
+	assert.Equal(t, "Bearer secret", gotAuth)
+	assert.Equal(t, string(written), string(gotBody))
+}
+
+func TestMerger_OutputURLError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid spec", http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{"openapi": "3.0.0", "info": {"title": "Users", "version": "1.0.0"}, "paths": {}}`
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs:    []config.InputConfig{{InputFile: specPath}},
+		Output:    filepath.Join(tempDir, "merged.json"),
+		OutputURL: server.URL,
+	}
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400 Bad Request: invalid spec")
+}
+
+func TestMerger_OutputURLSendsOnlyConfiguredHeaders(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{"openapi": "3.0.0", "info": {"title": "Users", "version": "1.0.0"}, "paths": {}}`
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	// A path mentioning github.com would get the token if publishing reused
+	// the input fetch request
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	cfg := &config.Config{
+		Inputs:    []config.InputConfig{{InputFile: specPath}},
+		Output:    filepath.Join(tempDir, "merged.json"),
+		OutputURL: server.URL + "/mirror/github.com/apis",
+	}
+	require.NoError(t, New(cfg, false).Merge())
+	assert.Empty(t, gotAuth)
+}
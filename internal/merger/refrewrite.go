@@ -0,0 +1,136 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// applyRefRewrites rewrites every $ref in the merged spec with the first
+// matching refRewrite rule. Rewritten local references that no longer resolve
+// are reported as warnings, or as an error in strict mode.
+func (m *Merger) applyRefRewrites() error {
+	rules := m.cfg.RefRewrite
+	if len(rules) == 0 {
+		return nil
+	}
+
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		if rule.Regex {
+			re, err := regexp.Compile(rule.From)
+			if err != nil {
+				return fmt.Errorf("invalid refRewrite[%d] regex: %w", i, err)
+			}
+			patterns[i] = re
+		}
+	}
+
+	// Refs shared between several places must only be rewritten once
+	visited := make(map[*string]bool)
+	rewritten := make(map[string]bool)
+	visitRefs(m.master, func(kind string, ref *string) {
+		if visited[ref] {
+			return
+		}
+		visited[ref] = true
+		for i, rule := range rules {
+			if next, ok := rewriteRef(*ref, rule, patterns[i]); ok {
+				*ref = next
+				rewritten[next] = true
+				return
+			}
+		}
+	})
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Rewrote references to %d distinct targets\n", len(rewritten))
+	}
+
+	return m.checkRewrittenRefs(rewritten)
+}
+
+// rewriteRef applies a single rule to ref, reporting whether it matched.
+func rewriteRef(ref string, rule config.RefRewriteConfig, re *regexp.Regexp) (string, bool) {
+	if re != nil {
+		if !re.MatchString(ref) {
+			return ref, false
+		}
+		return re.ReplaceAllString(ref, rule.To), true
+	}
+	if !strings.HasPrefix(ref, rule.From) {
+		return ref, false
+	}
+	return rule.To + strings.TrimPrefix(ref, rule.From), true
+}
+
+// checkRewrittenRefs reports rewritten local references that do not resolve
+// in the merged document. External references cannot be checked and are skipped.
+func (m *Merger) checkRewrittenRefs(refs map[string]bool) error {
+	data, err := json.Marshal(m.master)
+	if err != nil {
+		return fmt.Errorf("failed to check rewritten references: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to check rewritten references: %w", err)
+	}
+
+	var dangling []string
+	for ref := range refs {
+		if strings.HasPrefix(ref, "#") && !resolvePointer(doc, strings.TrimPrefix(ref, "#")) {
+			dangling = append(dangling, ref)
+		}
+	}
+	if len(dangling) == 0 {
+		return nil
+	}
+	sort.Strings(dangling)
+
+	if m.cfg.Strict {
+		return fmt.Errorf("refRewrite produced dangling references: %s", strings.Join(dangling, ", "))
+	}
+	for _, ref := range dangling {
+		m.warnf("", "refRewrite produced dangling reference %s", ref)
+	}
+	return nil
+}
+
+// resolvePointer reports whether the JSON pointer resolves within doc.
+func resolvePointer(doc interface{}, pointer string) bool {
+	_, ok := lookupPointer(doc, pointer)
+	return ok
+}
+
+// lookupPointer returns the value the JSON pointer points to within doc.
+func lookupPointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	current := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,116 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_RefRewrite(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/PageSize"}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"address": {"$ref": "#/components/schemas/LegacyAddress"}}},
+				"LegacyAddress": {"type": "object"},
+				"Address": {"type": "object"}
+			},
+			"parameters": {
+				"PageSize": {"name": "pageSize", "in": "query", "schema": {"type": "integer"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, rules []config.RefRewriteConfig, strict bool) (*Merger, error) {
+		cfg := &config.Config{
+			Inputs:     []config.InputConfig{{InputFile: specPath}},
+			Output:     filepath.Join(tempDir, "merged.json"),
+			RefRewrite: rules,
+			Strict:     strict,
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		return m, m.Merge()
+	}
+
+	t.Run("prefix to custom path", func(t *testing.T) {
+		m, err := run(t, []config.RefRewriteConfig{
+			{From: "#/components/schemas/", To: "https://registry.example.com/platform.yaml#/schemas/"},
+		}, false)
+		require.NoError(t, err)
+		assert.Empty(t, m.Warnings())
+
+		data, err := os.ReadFile(filepath.Join(tempDir, "merged.json"))
+		require.NoError(t, err)
+		output := string(data)
+
+		assert.Contains(t, output, `"$ref": "https://registry.example.com/platform.yaml#/schemas/User"`)
+		assert.Contains(t, output, `"$ref": "https://registry.example.com/platform.yaml#/schemas/LegacyAddress"`)
+		assert.Contains(t, output, `"$ref": "#/components/parameters/PageSize"`)
+		assert.False(t, strings.Contains(output, `"$ref": "#/components/schemas/`))
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		m, err := run(t, []config.RefRewriteConfig{
+			{From: `^#/components/schemas/Legacy(\w+)$`, To: "#/components/schemas/$1", Regex: true},
+		}, true)
+		require.NoError(t, err)
+
+		address := m.master.Components.Schemas["User"].Value.Properties["address"]
+		assert.Equal(t, "#/components/schemas/Address", address.Ref)
+	})
+
+	t.Run("dangling", func(t *testing.T) {
+		rules := []config.RefRewriteConfig{{From: "#/components/schemas/", To: "#/definitions/"}}
+
+		m, err := run(t, rules, false)
+		require.NoError(t, err)
+		require.Len(t, m.Warnings(), 2)
+		assert.Equal(t, "refRewrite produced dangling reference #/definitions/LegacyAddress", m.Warnings()[0].Message)
+
+		_, err = run(t, rules, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "#/definitions/User")
+	})
+}
+
+func TestResolvePointer(t *testing.T) {
+	doc := map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{"a/b": map[string]interface{}{}},
+		},
+		"tags": []interface{}{map[string]interface{}{"name": "Users"}},
+	}
+
+	assert.True(t, resolvePointer(doc, ""))
+	assert.True(t, resolvePointer(doc, "/components/schemas/a~1b"))
+	assert.True(t, resolvePointer(doc, "/tags/0/name"))
+	assert.False(t, resolvePointer(doc, "/tags/1"))
+	assert.False(t, resolvePointer(doc, "/components/schemas/User"))
+}
@@ -1,35 +1,85 @@
 package merger
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// componentsRefPrefix starts every local component reference.
+const componentsRefPrefix = "#/components/"
+
+// refVisitor is called for every $ref with the component kind the reference
+// must point to (e.g. "schemas") and a pointer to the reference itself.
+type refVisitor func(kind string, ref *string)
+
 // updateRefs updates all $ref references in the spec according to the rename map.
 func updateRefs(spec *openapi3.T, renames map[string]string) {
 	if len(renames) == 0 {
 		return
 	}
 
-	// Update refs in paths
+	visitRefs(spec, func(_ string, ref *string) {
+		*ref = renameRef(*ref, renames)
+	})
+}
+
+// renameRef returns the renamed reference. References pointing inside a
+// renamed component (e.g. "#/components/parameters/Limit/schema") follow it.
+func renameRef(ref string, renames map[string]string) string {
+	if newRef, ok := renames[ref]; ok {
+		return newRef
+	}
+	for i := len(ref) - 1; i > len(componentsRefPrefix); i-- {
+		if ref[i] != '/' {
+			continue
+		}
+		if newRef, ok := renames[ref[:i]]; ok {
+			return newRef + ref[i:]
+		}
+	}
+	return ref
+}
+
+// visitRefs calls fn for every $ref in the paths, webhooks and components of
+// the spec.
+// Referenced values are not followed; they are visited through components,
+// which also keeps recursive schemas from looping forever.
+func visitRefs(spec *openapi3.T, fn refVisitor) {
+	// Visit refs in paths
 	if spec.Paths != nil {
 		for _, pathItem := range spec.Paths.Map() {
-			updatePathItemRefs(pathItem, renames)
+			visitPathItemRefs(pathItem, fn)
 		}
 	}
 
-	// Update refs in components
+	// Visit refs in webhooks (OpenAPI 3.1)
+	for _, pathItem := range specWebhooks(spec) {
+		visitPathItemRefs(pathItem, fn)
+	}
+
+	// Visit refs in components
 	if spec.Components != nil {
-		updateComponentsRefs(spec.Components, renames)
+		visitComponentsRefs(spec.Components, fn)
 	}
 }
 
-// updatePathItemRefs updates refs in a path item.
-func updatePathItemRefs(pathItem *openapi3.PathItem, renames map[string]string) {
+// visitPathItemRefs visits refs in a path item.
+func visitPathItemRefs(pathItem *openapi3.PathItem, fn refVisitor) {
 	if pathItem == nil {
 		return
 	}
 
-	// Update refs in operations
+	// The loader resolves a referenced path item in place, so its
+	// operations below are visited along with the reference
+	if pathItem.Ref != "" {
+		fn("pathItems", &pathItem.Ref)
+	}
+
+	// Visit refs in operations
 	operations := []*openapi3.Operation{
 		pathItem.Get, pathItem.Post, pathItem.Put, pathItem.Delete,
 		pathItem.Patch, pathItem.Head, pathItem.Options, pathItem.Trace,
@@ -37,234 +87,402 @@ func updatePathItemRefs(pathItem *openapi3.PathItem, renames map[string]string)
 
 	for _, op := range operations {
 		if op != nil {
-			updateOperationRefs(op, renames)
+			visitOperationRefs(op, fn)
 		}
 	}
 
-	// Update refs in parameters
+	// Visit refs in parameters
 	for _, param := range pathItem.Parameters {
-		updateParameterRefRefs(param, renames)
+		visitParameterRefs(param, fn)
 	}
 }
 
-// updateOperationRefs updates refs in an operation.
-func updateOperationRefs(op *openapi3.Operation, renames map[string]string) {
-	// Update parameters
+// visitOperationRefs visits refs in an operation.
+func visitOperationRefs(op *openapi3.Operation, fn refVisitor) {
+	// Visit parameters
 	for _, param := range op.Parameters {
-		updateParameterRefRefs(param, renames)
+		visitParameterRefs(param, fn)
 	}
 
-	// Update request body
-	if op.RequestBody != nil {
-		updateRequestBodyRefRefs(op.RequestBody, renames)
-	}
+	// Visit request body
+	visitRequestBodyRefs(op.RequestBody, fn)
 
-	// Update responses
-	for _, resp := range op.Responses.Map() {
-		updateResponseRefRefs(resp, renames)
+	// Visit responses
+	if op.Responses != nil {
+		for _, resp := range op.Responses.Map() {
+			visitResponseRefs(resp, fn)
+		}
 	}
 
-	// Update callbacks
+	// Visit callbacks
 	for _, callback := range op.Callbacks {
-		updateCallbackRefRefs(callback, renames)
+		visitCallbackRefs(callback, fn)
 	}
 }
 
-// updateParameterRefRefs updates refs in a parameter ref.
-func updateParameterRefRefs(paramRef *openapi3.ParameterRef, renames map[string]string) {
+// visitParameterRefs visits refs in a parameter ref.
+func visitParameterRefs(paramRef *openapi3.ParameterRef, fn refVisitor) {
 	if paramRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if paramRef.Ref != "" {
-		if newRef, ok := renames[paramRef.Ref]; ok {
-			paramRef.Ref = newRef
-		}
+		fn("parameters", &paramRef.Ref)
+		return
 	}
-
-	// Update schema refs
-	if paramRef.Value != nil && paramRef.Value.Schema != nil {
-		updateSchemaRefRefs(paramRef.Value.Schema, renames)
+	if paramRef.Value == nil {
+		return
 	}
+
+	visitSchemaRefs(paramRef.Value.Schema, fn)
+	visitContentRefs(paramRef.Value.Content, fn)
+	visitExamplesRefs(paramRef.Value.Examples, fn)
 }
 
-// updateSchemaRefRefs updates refs in a schema ref.
-func updateSchemaRefRefs(schemaRef *openapi3.SchemaRef, renames map[string]string) {
+// visitSchemaRefs visits refs in a schema ref and its nested schemas.
+func visitSchemaRefs(schemaRef *openapi3.SchemaRef, fn refVisitor) {
 	if schemaRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if schemaRef.Ref != "" {
-		if newRef, ok := renames[schemaRef.Ref]; ok {
-			schemaRef.Ref = newRef
-		}
+		fn("schemas", &schemaRef.Ref)
+		return
+	}
+	if schemaRef.Value == nil {
+		return
 	}
 
-	// Update nested schemas
-	if schemaRef.Value != nil {
-		schema := schemaRef.Value
-
-		// Update items
-		if schema.Items != nil {
-			updateSchemaRefRefs(schema.Items, renames)
-		}
-
-		// Update properties
-		for _, prop := range schema.Properties {
-			updateSchemaRefRefs(prop, renames)
-		}
-
-		// Update additionalProperties
-		if schema.AdditionalProperties.Schema != nil {
-			updateSchemaRefRefs(schema.AdditionalProperties.Schema, renames)
-		}
+	schema := schemaRef.Value
 
-		// Update allOf
-		for _, s := range schema.AllOf {
-			updateSchemaRefRefs(s, renames)
-		}
+	// Visit items
+	visitSchemaRefs(schema.Items, fn)
 
-		// Update oneOf
-		for _, s := range schema.OneOf {
-			updateSchemaRefRefs(s, renames)
-		}
+	// Visit properties
+	for _, prop := range schema.Properties {
+		visitSchemaRefs(prop, fn)
+	}
 
-		// Update anyOf
-		for _, s := range schema.AnyOf {
-			updateSchemaRefRefs(s, renames)
-		}
+	// Visit additionalProperties
+	visitSchemaRefs(schema.AdditionalProperties.Schema, fn)
 
-		// Update not
-		if schema.Not != nil {
-			updateSchemaRefRefs(schema.Not, renames)
-		}
+	// Visit allOf, oneOf and anyOf
+	for _, s := range schema.AllOf {
+		visitSchemaRefs(s, fn)
+	}
+	for _, s := range schema.OneOf {
+		visitSchemaRefs(s, fn)
 	}
+	for _, s := range schema.AnyOf {
+		visitSchemaRefs(s, fn)
+	}
+
+	// Visit not
+	visitSchemaRefs(schema.Not, fn)
 }
 
-// updateRequestBodyRefRefs updates refs in a request body ref.
-func updateRequestBodyRefRefs(bodyRef *openapi3.RequestBodyRef, renames map[string]string) {
+// visitRequestBodyRefs visits refs in a request body ref.
+func visitRequestBodyRefs(bodyRef *openapi3.RequestBodyRef, fn refVisitor) {
 	if bodyRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if bodyRef.Ref != "" {
-		if newRef, ok := renames[bodyRef.Ref]; ok {
-			bodyRef.Ref = newRef
-		}
+		fn("requestBodies", &bodyRef.Ref)
+		return
 	}
-
-	// Update content schemas
-	if bodyRef.Value != nil && bodyRef.Value.Content != nil {
-		for _, mediaType := range bodyRef.Value.Content {
-			if mediaType.Schema != nil {
-				updateSchemaRefRefs(mediaType.Schema, renames)
-			}
-		}
+	if bodyRef.Value == nil {
+		return
 	}
+
+	visitContentRefs(bodyRef.Value.Content, fn)
 }
 
-// updateResponseRefRefs updates refs in a response ref.
-func updateResponseRefRefs(respRef *openapi3.ResponseRef, renames map[string]string) {
+// visitResponseRefs visits refs in a response ref.
+func visitResponseRefs(respRef *openapi3.ResponseRef, fn refVisitor) {
 	if respRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if respRef.Ref != "" {
-		if newRef, ok := renames[respRef.Ref]; ok {
-			respRef.Ref = newRef
-		}
+		fn("responses", &respRef.Ref)
+		return
+	}
+	if respRef.Value == nil {
+		return
 	}
 
-	// Update content schemas
-	if respRef.Value != nil {
-		if respRef.Value.Content != nil {
-			for _, mediaType := range respRef.Value.Content {
-				if mediaType.Schema != nil {
-					updateSchemaRefRefs(mediaType.Schema, renames)
-				}
-			}
-		}
+	visitContentRefs(respRef.Value.Content, fn)
+
+	// Visit headers
+	for _, header := range respRef.Value.Headers {
+		visitHeaderRefs(header, fn)
+	}
 
-		// Update headers
-		for _, header := range respRef.Value.Headers {
-			updateHeaderRefRefs(header, renames)
+	// Visit links
+	for _, link := range respRef.Value.Links {
+		if link != nil && link.Ref != "" {
+			fn("links", &link.Ref)
 		}
 	}
 }
 
-// updateHeaderRefRefs updates refs in a header ref.
-func updateHeaderRefRefs(headerRef *openapi3.HeaderRef, renames map[string]string) {
+// visitHeaderRefs visits refs in a header ref.
+func visitHeaderRefs(headerRef *openapi3.HeaderRef, fn refVisitor) {
 	if headerRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if headerRef.Ref != "" {
-		if newRef, ok := renames[headerRef.Ref]; ok {
-			headerRef.Ref = newRef
-		}
+		fn("headers", &headerRef.Ref)
+		return
 	}
-
-	// Update schema
-	if headerRef.Value != nil && headerRef.Value.Schema != nil {
-		updateSchemaRefRefs(headerRef.Value.Schema, renames)
+	if headerRef.Value == nil {
+		return
 	}
+
+	visitSchemaRefs(headerRef.Value.Schema, fn)
+	visitContentRefs(headerRef.Value.Content, fn)
+	visitExamplesRefs(headerRef.Value.Examples, fn)
 }
 
-// updateCallbackRefRefs updates refs in a callback ref.
-func updateCallbackRefRefs(callbackRef *openapi3.CallbackRef, renames map[string]string) {
+// visitCallbackRefs visits refs in a callback ref.
+func visitCallbackRefs(callbackRef *openapi3.CallbackRef, fn refVisitor) {
 	if callbackRef == nil {
 		return
 	}
-
-	// Update the ref itself
 	if callbackRef.Ref != "" {
-		if newRef, ok := renames[callbackRef.Ref]; ok {
-			callbackRef.Ref = newRef
+		fn("callbacks", &callbackRef.Ref)
+		return
+	}
+	if callbackRef.Value == nil {
+		return
+	}
+
+	// Visit path items in callback
+	for _, pathItem := range callbackRef.Value.Map() {
+		visitPathItemRefs(pathItem, fn)
+	}
+}
+
+// visitContentRefs visits refs in the media types of a content map.
+func visitContentRefs(content openapi3.Content, fn refVisitor) {
+	for _, mediaType := range content {
+		if mediaType == nil {
+			continue
 		}
+		visitSchemaRefs(mediaType.Schema, fn)
+		visitExamplesRefs(mediaType.Examples, fn)
 	}
+}
 
-	// Update path items in callback
-	if callbackRef.Value != nil {
-		for _, pathItem := range callbackRef.Value.Map() {
-			updatePathItemRefs(pathItem, renames)
+// visitExamplesRefs visits refs in an examples map.
+func visitExamplesRefs(examples openapi3.Examples, fn refVisitor) {
+	for _, example := range examples {
+		if example != nil && example.Ref != "" {
+			fn("examples", &example.Ref)
 		}
 	}
 }
 
-// updateComponentsRefs updates refs in components.
-func updateComponentsRefs(components *openapi3.Components, renames map[string]string) {
-	// Update schemas
+// visitComponentsRefs visits refs in components.
+func visitComponentsRefs(components *openapi3.Components, fn refVisitor) {
+	// Visit schemas
 	for _, schema := range components.Schemas {
-		updateSchemaRefRefs(schema, renames)
+		visitSchemaRefs(schema, fn)
 	}
 
-	// Update parameters
+	// Visit parameters
 	for _, param := range components.Parameters {
-		updateParameterRefRefs(param, renames)
+		visitParameterRefs(param, fn)
 	}
 
-	// Update responses
+	// Visit responses
 	for _, resp := range components.Responses {
-		updateResponseRefRefs(resp, renames)
+		visitResponseRefs(resp, fn)
 	}
 
-	// Update request bodies
+	// Visit request bodies
 	for _, body := range components.RequestBodies {
-		updateRequestBodyRefRefs(body, renames)
+		visitRequestBodyRefs(body, fn)
 	}
 
-	// Update headers
+	// Visit headers
 	for _, header := range components.Headers {
-		updateHeaderRefRefs(header, renames)
+		visitHeaderRefs(header, fn)
 	}
 
-	// Update callbacks
+	// Visit callbacks
 	for _, callback := range components.Callbacks {
-		updateCallbackRefRefs(callback, renames)
+		visitCallbackRefs(callback, fn)
+	}
+
+	// Visit examples
+	visitExamplesRefs(components.Examples, fn)
+
+	// Visit path items (OpenAPI 3.1)
+	for _, pathItem := range componentPathItems(components) {
+		visitPathItemRefs(pathItem, fn)
+	}
+}
+
+// collectLocalRefs returns every distinct local component reference
+// ("#/components/...") in the spec, sorted.
+func collectLocalRefs(spec *openapi3.T) ([]string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to scan references: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, componentsRefPrefix) {
+				seen[ref] = true
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// danglingRefs returns the local component references in the spec that point
+// to components it does not define.
+func danglingRefs(spec *openapi3.T) ([]string, error) {
+	refs, err := collectLocalRefs(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var dangling []string
+	for _, ref := range refs {
+		if !hasComponent(spec.Components, ref) {
+			dangling = append(dangling, ref)
+		}
+	}
+	return dangling, nil
+}
+
+// unresolvedLocalRefs scans a parsed document for local references ("#/...")
+// that do not resolve within it, returning one description per use with the
+// location of the $ref, sorted.
+func unresolvedLocalRefs(doc interface{}) []string {
+	var problems []string
+	for _, use := range unresolvedLocalRefUses(doc) {
+		problems = append(problems, fmt.Sprintf("%s (at %s)", use.Ref, use.Location))
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// refUse is a $ref and the JSON pointer of the object holding it.
+type refUse struct {
+	Ref      string
+	Location string
+}
+
+// unresolvedLocalRefUses returns every use of a local reference in a parsed
+// document that does not resolve within it.
+func unresolvedLocalRefUses(doc interface{}) []refUse {
+	var uses []refUse
+	var walk func(v interface{}, location string)
+	walk = func(v interface{}, location string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+				if !resolvePointer(doc, strings.TrimPrefix(ref, "#")) {
+					uses = append(uses, refUse{Ref: ref, Location: location})
+				}
+			}
+			for key, child := range v {
+				if literalKeys[key] {
+					continue
+				}
+				walk(child, location+"/"+escapePointerToken(key))
+			}
+		case []interface{}:
+			for i, child := range v {
+				walk(child, fmt.Sprintf("%s/%d", location, i))
+			}
+		}
+	}
+	walk(doc, "#")
+	return uses
+}
+
+// hasComponent reports whether the local component reference resolves.
+func hasComponent(components *openapi3.Components, ref string) bool {
+	kind, name, ok := strings.Cut(strings.TrimPrefix(ref, componentsRefPrefix), "/")
+	if !ok || components == nil {
+		return false
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+
+	var found bool
+	switch kind {
+	case "schemas":
+		_, found = components.Schemas[name]
+	case "parameters":
+		_, found = components.Parameters[name]
+	case "headers":
+		_, found = components.Headers[name]
+	case "requestBodies":
+		_, found = components.RequestBodies[name]
+	case "responses":
+		_, found = components.Responses[name]
+	case "securitySchemes":
+		_, found = components.SecuritySchemes[name]
+	case "examples":
+		_, found = components.Examples[name]
+	case "links":
+		_, found = components.Links[name]
+	case "callbacks":
+		_, found = components.Callbacks[name]
+	case "pathItems":
+		_, found = componentPathItems(components)[name]
+	}
+	return found
+}
+
+// checkRefKinds reports local references that point into a component bucket
+// other than the one their position requires, such as a schema $ref naming a
+// parameter. Mismatches are warnings, or an error in strict mode.
+func (m *Merger) checkRefKinds(spec *openapi3.T, source string) error {
+	var problems []string
+	visitRefs(spec, func(kind string, ref *string) {
+		if !strings.HasPrefix(*ref, componentsRefPrefix) {
+			return
+		}
+		got, _, _ := strings.Cut(strings.TrimPrefix(*ref, componentsRefPrefix), "/")
+		if got != kind {
+			problems = append(problems, fmt.Sprintf("reference %s points to %s but is used where %s are expected", *ref, got, kind))
+		}
+	})
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+
+	if m.cfg.Strict {
+		return fmt.Errorf("mismatched references: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		m.warnf(source, "%s", problem)
 	}
+	return nil
 }
@@ -0,0 +1,183 @@
+package merger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_CrossBucketRefWarning(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"User": {
+					"type": "object",
+					"properties": {
+						"limit": {"$ref": "#/components/parameters/Limit/schema"}
+					}
+				}
+			},
+			"parameters": {
+				"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: filepath.Join(tempDir, "merged.json"),
+		}
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		m := New(newConfig(), false)
+		require.NoError(t, m.Merge())
+		assert.Contains(t, m.Warnings(), Warning{
+			Source:  specPath,
+			Message: "reference #/components/parameters/Limit/schema points to parameters but is used where schemas are expected",
+		})
+	})
+
+	t.Run("dispute", func(t *testing.T) {
+		// Prefixing keeps the reference pointing into the renamed parameter
+		cfg := newConfig()
+		cfg.Inputs[0].Dispute = &config.DisputeConfig{Prefix: "Users"}
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		user := m.master.Components.Schemas["UsersUser"]
+		require.NotNil(t, user)
+		assert.Equal(t, "#/components/parameters/UsersLimit/schema", user.Value.Properties["limit"].Ref)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Strict = true
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "mismatched references")
+	})
+}
+
+func TestMerger_RecursiveSchemaWithDispute(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Tree", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Node": {
+					"type": "object",
+					"properties": {
+						"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "tree.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: specPath, Dispute: &config.DisputeConfig{Prefix: "Tree"}}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	node := m.master.Components.Schemas["TreeNode"]
+	require.NotNil(t, node)
+	assert.Equal(t, "#/components/schemas/TreeNode", node.Value.Properties["children"].Value.Items.Ref)
+}
+
+func TestMerger_DanglingInputRefs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	valid := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`
+	broken := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/Missing"}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {
+								"schema": {"$ref": "#/components/schemas/Gone"},
+								"example": {"$ref": "#/not/a/reference"}
+							}}
+						}
+					}
+				}
+			}
+		},
+		"components": {"schemas": {"Order": {"type": "object"}}}
+	}`
+
+	validPath := filepath.Join(tempDir, "users.json")
+	brokenPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(validPath, []byte(valid), 0644))
+	require.NoError(t, os.WriteFile(brokenPath, []byte(broken), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: validPath},
+			{InputFile: brokenPath},
+		},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+	problems := "dangling references: " +
+		"#/components/parameters/Missing (at #/paths/~1orders/get/parameters/0), " +
+		"#/components/schemas/Gone (at #/paths/~1orders/get/responses/200/content/application~1json/schema)"
+
+	// Outside strict mode the input is merged with its references as written
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	require.Len(t, m.Warnings(), 1)
+	assert.Equal(t, brokenPath, m.Warnings()[0].Source)
+	assert.Equal(t, problems, m.Warnings()[0].Message)
+
+	orders := m.master.Paths.Value("/orders").Get
+	assert.Equal(t, "#/components/parameters/Missing", orders.Parameters[0].Ref)
+	assert.Equal(t, "#/components/schemas/Gone",
+		orders.Responses.Value("200").Value.Content["application/json"].Schema.Ref)
+	assert.NotContains(t, m.master.Components.Schemas, "Gone")
+
+	cfg.Strict = true
+	err = New(cfg, false).Merge()
+	require.Error(t, err)
+
+	var inputErr *InputError
+	require.True(t, errors.As(err, &inputErr))
+	assert.Equal(t, brokenPath, inputErr.Source)
+	assert.Contains(t, err.Error(), problems)
+}
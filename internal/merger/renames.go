@@ -0,0 +1,78 @@
+package merger
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+// Extensions set on renamed components when AnnotateRenames is enabled.
+const (
+	originalNameExtension = "x-original-name"
+	sourceExtension       = "x-source"
+)
+
+// ComponentRename records a component merged under a new name by a dispute
+// or the prefix conflict policy.
+type ComponentRename struct {
+	Kind   string `json:"kind"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Source string `json:"source"`
+}
+
+// recordRename records that a component of the given kind from source was
+// renamed. With AnnotateRenames the component also gets its original name
+// and input file as extensions; components that are themselves references
+// share their target's value and are left alone.
+func (m *Merger) recordRename(kind string, component interface{}, original, renamed, source string) {
+	m.renames = append(m.renames, ComponentRename{Kind: kind, From: original, To: renamed, Source: source})
+	if !m.cfg.AnnotateRenames {
+		return
+	}
+
+	var extensions *map[string]interface{}
+	switch c := component.(type) {
+	case *openapi3.SchemaRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.ParameterRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.ResponseRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.RequestBodyRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.SecuritySchemeRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.HeaderRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.ExampleRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.LinkRef:
+		if c != nil && c.Ref == "" && c.Value != nil {
+			extensions = &c.Value.Extensions
+		}
+	case *openapi3.PathItem:
+		if c != nil && c.Ref == "" {
+			extensions = &c.Extensions
+		}
+	}
+	if extensions == nil {
+		return
+	}
+
+	if *extensions == nil {
+		*extensions = make(map[string]interface{})
+	}
+	(*extensions)[originalNameExtension] = original
+	(*extensions)[sourceExtension] = source
+}
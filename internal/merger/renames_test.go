@@ -0,0 +1,69 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_AnnotateRenames(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := func(title, property string) string {
+		return `{
+			"openapi": "3.0.0",
+			"info": {"title": "` + title + `", "version": "1.0.0"},
+			"paths": {},
+			"components": {
+				"schemas": {
+					"User": {"type": "object", "properties": {"` + property + `": {"type": "string"}}},
+					"UserRef": {"$ref": "#/components/schemas/User"}
+				},
+				"responses": {
+					"NotFound": {"description": "` + title + ` not found"}
+				}
+			}
+		}`
+	}
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	billingPath := filepath.Join(tempDir, "billing.json")
+	ordersPath := filepath.Join(tempDir, "orders-api.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(spec("Users", "id")), 0644))
+	require.NoError(t, os.WriteFile(billingPath, []byte(spec("Billing", "iban")), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(spec("Orders", "orderId")), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: usersPath},
+			{InputFile: billingPath, Dispute: &config.DisputeConfig{Prefix: "Svc"}},
+			{InputFile: ordersPath},
+		},
+		Output:          filepath.Join(tempDir, "merged.json"),
+		ConflictPolicy:  &config.ConflictPolicyConfig{Schemas: config.ConflictPolicyPrefix},
+		AnnotateRenames: true,
+	}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	schemas := m.master.Components.Schemas
+
+	// Renamed by a dispute prefix
+	prefixed := schemas["SvcUser"].Value.Extensions
+	assert.Equal(t, "User", prefixed["x-original-name"])
+	assert.Equal(t, billingPath, prefixed["x-source"])
+	assert.Equal(t, "NotFound", m.master.Components.Responses["SvcNotFound"].Value.Extensions["x-original-name"])
+
+	// Renamed by the prefix conflict policy
+	assert.Equal(t, "User", schemas["OrdersApi_User"].Value.Extensions["x-original-name"])
+	assert.Equal(t, ordersPath, schemas["OrdersApi_User"].Value.Extensions["x-source"])
+
+	// Components that were not renamed, and aliases of renamed ones, are untouched
+	assert.NotContains(t, schemas["User"].Value.Extensions, "x-original-name")
+	assert.Equal(t, "#/components/schemas/SvcUser", schemas["SvcUserRef"].Ref)
+}
@@ -0,0 +1,53 @@
+package merger
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// responseViewCodes lists the status code patterns each ResponseView keeps.
+var responseViewCodes = map[string][]string{
+	config.ResponseViewSuccessOnly: {"2XX", "default"},
+	config.ResponseViewErrorsOnly:  {"4XX", "5XX", "default"},
+}
+
+// responseViewFallback describes the default response added to operations
+// that are left without any response.
+var responseViewFallback = map[string]string{
+	config.ResponseViewSuccessOnly: "Successful response",
+	config.ResponseViewErrorsOnly:  "Error response",
+}
+
+// applyResponseView removes the responses of every operation whose status code
+// the configured ResponseView does not show. An operation left without
+// responses gets a bare default response, since at least one is required.
+func (m *Merger) applyResponseView() {
+	codes, ok := responseViewCodes[m.cfg.ResponseView]
+	if !ok {
+		return
+	}
+
+	removed := 0
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.Responses == nil {
+			return
+		}
+		for code := range op.Responses.Map() {
+			if !matchStatusCodes(codes, code) {
+				op.Responses.Delete(code)
+				removed++
+			}
+		}
+		if op.Responses.Len() == 0 {
+			op.Responses.Set("default", &openapi3.ResponseRef{
+				Value: openapi3.NewResponse().WithDescription(responseViewFallback[m.cfg.ResponseView]),
+			})
+		}
+	})
+
+	if m.verbose && removed > 0 {
+		fmt.Fprintf(m.log, "Removed %d responses outside the %s view\n", removed, m.cfg.ResponseView)
+	}
+}
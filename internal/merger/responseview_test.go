@@ -0,0 +1,88 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_ResponseView(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Catalog", "version": "1.0.0"},
+		"paths": {
+			"/products": {
+				"get": {
+					"responses": {
+						"200": {"description": "OK"},
+						"304": {"description": "Not modified"},
+						"404": {"description": "Not found"},
+						"5XX": {"description": "Server error"},
+						"default": {"description": "Unexpected"}
+					}
+				},
+				"post": {
+					"responses": {
+						"201": {"description": "Created"},
+						"2XX": {"description": "Other success"},
+						"400": {"description": "Bad request"}
+					}
+				},
+				"delete": {
+					"responses": {
+						"409": {"description": "Conflict"}
+					}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "catalog.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	codes := func(t *testing.T, view string) map[string][]string {
+		cfg := &config.Config{
+			Inputs:       []config.InputConfig{{InputFile: specPath}},
+			Output:       filepath.Join(tempDir, "merged.json"),
+			ResponseView: view,
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		item := m.master.Paths.Value("/products")
+		return map[string][]string{
+			"GET":    sortedKeys(item.Get.Responses.Map()),
+			"POST":   sortedKeys(item.Post.Responses.Map()),
+			"DELETE": sortedKeys(item.Delete.Responses.Map()),
+		}
+	}
+
+	t.Run("success-only", func(t *testing.T) {
+		assert.Equal(t, map[string][]string{
+			"GET":    {"200", "default"},
+			"POST":   {"201", "2XX"},
+			"DELETE": {"default"},
+		}, codes(t, config.ResponseViewSuccessOnly))
+	})
+
+	t.Run("errors-only", func(t *testing.T) {
+		assert.Equal(t, map[string][]string{
+			"GET":    {"404", "5XX", "default"},
+			"POST":   {"400"},
+			"DELETE": {"409"},
+		}, codes(t, config.ResponseViewErrorsOnly))
+	})
+
+	t.Run("all", func(t *testing.T) {
+		assert.Len(t, codes(t, config.ResponseViewAll)["GET"], 5)
+	})
+}
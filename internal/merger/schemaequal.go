@@ -0,0 +1,90 @@
+package merger
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// schemasEqual reports whether two schema refs describe the same schema.
+// References compare by target. Inline schemas compare structurally, so key
+// order and formatting do not matter, and neither does the order of required
+// names or enum values.
+func schemasEqual(a, b *openapi3.SchemaRef) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	if a.Ref != "" && b.Ref != "" {
+		return a.Ref == b.Ref
+	}
+
+	aValue, ok := canonicalSchema(a)
+	if !ok {
+		return false
+	}
+	bValue, ok := canonicalSchema(b)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// canonicalSchema decodes the JSON form of a schema with its unordered
+// keywords sorted, ready for comparison.
+func canonicalSchema(s *openapi3.SchemaRef) (interface{}, bool) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, false
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, false
+	}
+	sortUnorderedKeywords(value)
+	return value, true
+}
+
+// sortUnorderedKeywords sorts the required and enum arrays of a decoded
+// schema and its subschemas in place. Example data is left untouched.
+func sortUnorderedKeywords(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			// Property names are not keywords; "required" may name a property
+			if key == "properties" {
+				if properties, ok := child.(map[string]interface{}); ok {
+					for _, property := range properties {
+						sortUnorderedKeywords(property)
+					}
+				}
+				continue
+			}
+			if list, ok := child.([]interface{}); ok && (key == "required" || key == "enum") {
+				sortByJSON(list)
+				continue
+			}
+			if literalKeys[key] {
+				continue
+			}
+			sortUnorderedKeywords(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			sortUnorderedKeywords(child)
+		}
+	}
+}
+
+// sortByJSON sorts values of any type by their JSON text.
+func sortByJSON(values []interface{}) {
+	text := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	sort.Slice(values, func(i, j int) bool { return text(values[i]) < text(values[j]) })
+}
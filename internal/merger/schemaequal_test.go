@@ -0,0 +1,130 @@
+package merger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemasEqual(t *testing.T) {
+	parse := func(t *testing.T, s string) *openapi3.SchemaRef {
+		var schema openapi3.Schema
+		require.NoError(t, json.Unmarshal([]byte(s), &schema))
+		return &openapi3.SchemaRef{Value: &schema}
+	}
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{
+			name:  "key and property order",
+			a:     `{"type": "object", "properties": {"code": {"type": "integer"}, "message": {"type": "string"}}}`,
+			b:     `{"properties": {"message": {"type": "string"}, "code": {"type": "integer"}}, "type": "object"}`,
+			equal: true,
+		},
+		{
+			name:  "required order",
+			a:     `{"type": "object", "required": ["code", "message"]}`,
+			b:     `{"type": "object", "required": ["message", "code"]}`,
+			equal: true,
+		},
+		{
+			name:  "nested enum order",
+			a:     `{"type": "object", "properties": {"level": {"type": "string", "enum": ["warn", "error"]}}}`,
+			b:     `{"type": "object", "properties": {"level": {"type": "string", "enum": ["error", "warn"]}}}`,
+			equal: true,
+		},
+		{
+			name:  "property named required",
+			a:     `{"type": "object", "properties": {"required": {"type": "array", "items": {"type": "string"}, "example": ["a", "b"]}}}`,
+			b:     `{"type": "object", "properties": {"required": {"type": "array", "items": {"type": "string"}, "example": ["a", "b"]}}}`,
+			equal: true,
+		},
+		{
+			name:  "different required names",
+			a:     `{"type": "object", "required": ["code"]}`,
+			b:     `{"type": "object", "required": ["message"]}`,
+			equal: false,
+		},
+		{
+			name:  "example order is significant",
+			a:     `{"type": "array", "example": ["a", "b"]}`,
+			b:     `{"type": "array", "example": ["b", "a"]}`,
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, schemasEqual(parse(t, tt.a), parse(t, tt.b)))
+		})
+	}
+
+	assert.True(t, schemasEqual(&openapi3.SchemaRef{Ref: "#/components/schemas/A"}, &openapi3.SchemaRef{Ref: "#/components/schemas/A"}))
+	assert.False(t, schemasEqual(&openapi3.SchemaRef{Ref: "#/components/schemas/A"}, nil))
+}
+
+func TestMerger_EquivalentSchemasMergeSilently(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Error": {
+					"type": "object",
+					"required": ["code", "message"],
+					"properties": {
+						"code": {"type": "integer"},
+						"message": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`
+
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Error": {
+					"properties": {
+						"message": {"type": "string"},
+						"code": {"type": "integer"}
+					},
+					"required": ["message", "code"],
+					"type": "object"
+				}
+			}
+		}
+	}`
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	ordersPath := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(users), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(orders), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{InputFile: usersPath}, {InputFile: ordersPath}},
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Empty(t, m.Warnings())
+	assert.Equal(t, []string{"code", "message"}, m.master.Components.Schemas["Error"].Value.Required)
+}
@@ -0,0 +1,147 @@
+package merger
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// applySecuritySchemeAliases collapses aliased security schemes into their
+// canonical names. The alias definition is dropped (or renamed if the canonical
+// scheme is not defined) and every root and operation security requirement is
+// rewritten to use the canonical name.
+func (m *Merger) applySecuritySchemeAliases() {
+	aliases := m.cfg.SecuritySchemeAliases
+	if len(aliases) == 0 {
+		return
+	}
+
+	if schemes := m.master.Components.SecuritySchemes; schemes != nil {
+		names := make([]string, 0, len(aliases))
+		for alias := range aliases {
+			names = append(names, alias)
+		}
+		sort.Strings(names)
+
+		for _, alias := range names {
+			scheme, ok := schemes[alias]
+			if !ok {
+				continue
+			}
+			delete(schemes, alias)
+			if _, exists := schemes[aliases[alias]]; !exists {
+				schemes[aliases[alias]] = scheme
+			}
+			if m.verbose {
+				fmt.Fprintf(m.log, "Merged security scheme %s into %s\n", alias, aliases[alias])
+			}
+		}
+	}
+
+	m.master.Security = aliasSecurityRequirements(m.master.Security, aliases)
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.Security != nil {
+			*op.Security = aliasSecurityRequirements(*op.Security, aliases)
+		}
+	})
+}
+
+// aliasSecurityRequirements renames aliased schemes in each requirement. If a
+// requirement names both an alias and its canonical scheme, their scopes are
+// combined, and requirements that become identical are kept once.
+func aliasSecurityRequirements(reqs openapi3.SecurityRequirements, aliases map[string]string) openapi3.SecurityRequirements {
+	if reqs == nil {
+		return nil
+	}
+
+	result := make(openapi3.SecurityRequirements, 0, len(reqs))
+	for _, req := range reqs {
+		renamed := make(openapi3.SecurityRequirement, len(req))
+		for name, scopes := range req {
+			if canonical, ok := aliases[name]; ok {
+				name = canonical
+			}
+			for _, scope := range scopes {
+				if !slices.Contains(renamed[name], scope) {
+					renamed[name] = append(renamed[name], scope)
+				}
+			}
+			if renamed[name] == nil {
+				renamed[name] = []string{}
+			}
+		}
+		if !slices.ContainsFunc(result, func(r openapi3.SecurityRequirement) bool { return reflect.DeepEqual(r, renamed) }) {
+			result = append(result, renamed)
+		}
+	}
+	return result
+}
+
+// inputSecurity returns the global security requirements of an input. Schemes
+// renamed by the input's dispute are referred to by their new names.
+func inputSecurity(spec *openapi3.T, input *config.InputConfig) openapi3.SecurityRequirements {
+	if !input.Dispute.Active() || spec.Components == nil {
+		return spec.Security
+	}
+
+	reqs := make(openapi3.SecurityRequirements, 0, len(spec.Security))
+	for _, req := range spec.Security {
+		renamed := make(openapi3.SecurityRequirement, len(req))
+		for name, scopes := range req {
+			if newName := input.Dispute.Rename(name); spec.Components.SecuritySchemes[newName] != nil {
+				name = newName
+			}
+			renamed[name] = scopes
+		}
+		reqs = append(reqs, renamed)
+	}
+	return reqs
+}
+
+// renameSecuritySchemes applies the security scheme renames among the
+// component renames to the root and operation security requirements of spec.
+// Requirements name their schemes instead of referencing them, so updateRefs
+// does not reach them.
+func renameSecuritySchemes(spec *openapi3.T, renames map[string]string) {
+	prefix := componentsRefPrefix + "securitySchemes/"
+	names := make(map[string]string)
+	for oldRef, newRef := range renames {
+		if name, ok := strings.CutPrefix(oldRef, prefix); ok {
+			names[name] = strings.TrimPrefix(newRef, prefix)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	spec.Security = aliasSecurityRequirements(spec.Security, names)
+	rename := func(path, method string, op *openapi3.Operation) {
+		if op.Security != nil {
+			*op.Security = aliasSecurityRequirements(*op.Security, names)
+		}
+	}
+	forEachOperation(spec.Paths, rename)
+	for name, pathItem := range specWebhooks(spec) {
+		for method, op := range getOperationsMap(pathItem) {
+			if op != nil {
+				rename(name, method, op)
+			}
+		}
+	}
+}
+
+// appendSecurityRequirements appends the requirements of src that dest does
+// not already contain.
+func appendSecurityRequirements(dest, src openapi3.SecurityRequirements) openapi3.SecurityRequirements {
+	for _, req := range src {
+		if !slices.ContainsFunc(dest, func(r openapi3.SecurityRequirement) bool { return reflect.DeepEqual(r, req) }) {
+			dest = append(dest, req)
+		}
+	}
+	return dest
+}
@@ -0,0 +1,148 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_SecuritySchemeAliases(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"security": [{"bearerAuth": []}],
+		"paths": {
+			"/users": {
+				"get": {"security": [{"bearerAuth": []}], "responses": {"200": {"description": "OK"}}}
+			}
+		},
+		"components": {
+			"securitySchemes": {
+				"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+			}
+		}
+	}`
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {
+					"security": [{"jwtAuth": []}, {"bearerAuth": []}, {"apiKey": []}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"components": {
+			"securitySchemes": {
+				"jwtAuth": {"type": "http", "scheme": "bearer"},
+				"apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"}
+			}
+		}
+	}`
+
+	spec1Path := filepath.Join(tempDir, "users.json")
+	spec2Path := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(spec1Path, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(spec2Path, []byte(spec2), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: spec1Path},
+			{InputFile: spec2Path},
+		},
+		Output:                filepath.Join(tempDir, "merged.json"),
+		Security:              []map[string][]string{{"jwtAuth": {}}},
+		SecuritySchemeAliases: map[string]string{"jwtAuth": "bearerAuth"},
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	schemes := m.master.Components.SecuritySchemes
+	assert.NotContains(t, schemes, "jwtAuth")
+	require.Contains(t, schemes, "bearerAuth")
+	assert.Equal(t, "JWT", schemes["bearerAuth"].Value.BearerFormat)
+	assert.Contains(t, schemes, "apiKey")
+
+	bearer := openapi3.SecurityRequirement{"bearerAuth": {}}
+	assert.Equal(t, openapi3.SecurityRequirements{bearer}, m.master.Security)
+
+	orders := m.master.Paths.Value("/orders").Get
+	require.NotNil(t, orders.Security)
+	assert.Equal(t, openapi3.SecurityRequirements{bearer, {"apiKey": {}}}, *orders.Security)
+}
+
+func TestConfig_ValidateSecuritySchemeAliases(t *testing.T) {
+	cfg := &config.Config{
+		Inputs:                []config.InputConfig{{InputFile: "api.json"}},
+		Output:                "merged.json",
+		SecuritySchemeAliases: map[string]string{"jwtAuth": "tokenAuth", "tokenAuth": "bearerAuth"},
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestMerger_SecurityMergeStrategy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := func(title, security, schemes string) string {
+		return `{
+			"openapi": "3.0.0",
+			"info": {"title": "` + title + `", "version": "1.0.0"},
+			"security": ` + security + `,
+			"paths": {},
+			"components": {"securitySchemes": ` + schemes + `}
+		}`
+	}
+	bearer := `{"bearerAuth": {"type": "http", "scheme": "bearer"}}`
+	specs := map[string]string{
+		"users.json":  spec("Users", `[{"bearerAuth": []}]`, bearer),
+		"orders.json": spec("Orders", `[{"bearerAuth": []}]`, bearer),
+		"legacy.json": spec("Legacy", `[{"oauth": ["read"]}]`,
+			`{"oauth": {"type": "oauth2", "flows": {"implicit": {"authorizationUrl": "https://example.com/auth", "scopes": {"read": "Read"}}}}}`),
+	}
+	var inputs []config.InputConfig
+	for _, name := range []string{"users.json", "orders.json", "legacy.json"} {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(specs[name]), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+	inputs[2].Dispute = &config.DisputeConfig{Prefix: "Legacy_"}
+
+	cfg := &config.Config{
+		Inputs:                inputs,
+		Output:                filepath.Join(tempDir, "merged.json"),
+		Security:              []map[string][]string{{"apiKey": {}}},
+		SecurityMergeStrategy: config.SecurityMergeStrategyMerge,
+	}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Equal(t, openapi3.SecurityRequirements{
+		{"apiKey": {}},
+		{"bearerAuth": {}},
+		{"Legacy_oauth": {"read"}},
+	}, m.master.Security)
+
+	// The default replaces the inputs' security with the config's
+	cfg.SecurityMergeStrategy = ""
+	m = New(cfg, false)
+	require.NoError(t, m.Merge())
+	assert.Equal(t, openapi3.SecurityRequirements{{"apiKey": {}}}, m.master.Security)
+
+	cfg.SecurityMergeStrategy = "union"
+	assert.EqualError(t, cfg.Validate(), `invalid securityMergeStrategy "union" (expected replace or merge)`)
+}
@@ -0,0 +1,68 @@
+package merger
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// mergeServerVariables folds the variables of src into dest, a server with the
+// same URL collected from an earlier input. Variables only one of them defines
+// are kept. Same-named variables that differ have their enums unioned and the
+// first non-empty default and description kept, or fail the merge when
+// ServerVariableConflict is error.
+func (m *Merger) mergeServerVariables(dest, src *openapi3.Server, source string) error {
+	if len(src.Variables) == 0 {
+		return nil
+	}
+	if dest.Variables == nil {
+		dest.Variables = make(map[string]*openapi3.ServerVariable)
+	}
+
+	var conflicts []string
+	for _, name := range sortedKeys(src.Variables) {
+		variable := src.Variables[name]
+		existing, ok := dest.Variables[name]
+		if !ok || existing == nil {
+			dest.Variables[name] = variable
+			continue
+		}
+		if variable == nil || reflect.DeepEqual(existing, variable) {
+			continue
+		}
+
+		if m.cfg.ServerVariableConflict == config.ServerVariableConflictError {
+			conflicts = append(conflicts, name)
+			continue
+		}
+
+		// A variable without an enum accepts any value, so the union does too
+		if len(existing.Enum) == 0 || len(variable.Enum) == 0 {
+			existing.Enum = nil
+		} else {
+			for _, value := range variable.Enum {
+				if !slices.Contains(existing.Enum, value) {
+					existing.Enum = append(existing.Enum, value)
+				}
+			}
+		}
+		if existing.Default == "" {
+			existing.Default = variable.Default
+		} else if variable.Default != "" && variable.Default != existing.Default {
+			m.warnf(source, "server %s: variable %q default %q differs from %q; keeping %q",
+				dest.URL, name, variable.Default, existing.Default, existing.Default)
+		}
+		if existing.Description == "" {
+			existing.Description = variable.Description
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("server %s: variables %s differ from an earlier input", dest.URL, strings.Join(conflicts, ", "))
+	}
+	return nil
+}
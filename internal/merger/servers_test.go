@@ -0,0 +1,91 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeServerSpecs(t *testing.T) (string, string, string) {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec1 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"servers": [{
+			"url": "https://{region}.example.com/{version}",
+			"variables": {
+				"region": {"default": "eu", "enum": ["eu", "us"]},
+				"version": {"default": "v1"}
+			}
+		}],
+		"paths": {}
+	}`
+	spec2 := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"servers": [{
+			"url": "https://{region}.example.com/{version}",
+			"variables": {
+				"region": {"default": "eu", "enum": ["eu", "ap"], "description": "Deployment region"},
+				"version": {"default": "v2", "enum": ["v1", "v2"]}
+			}
+		}],
+		"paths": {}
+	}`
+
+	input1 := filepath.Join(tempDir, "users.json")
+	input2 := filepath.Join(tempDir, "orders.json")
+	require.NoError(t, os.WriteFile(input1, []byte(spec1), 0644))
+	require.NoError(t, os.WriteFile(input2, []byte(spec2), 0644))
+	return tempDir, input1, input2
+}
+
+func TestMerger_MergeServerVariables(t *testing.T) {
+	tempDir, input1, input2 := writeServerSpecs(t)
+
+	cfg := &config.Config{
+		Inputs:      []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output:      filepath.Join(tempDir, "merged.json"),
+		ServersMode: config.ServersModeUnion,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	require.Len(t, m.master.Servers, 1)
+	vars := m.master.Servers[0].Variables
+	require.Len(t, vars, 2)
+
+	assert.Equal(t, []string{"eu", "us", "ap"}, vars["region"].Enum)
+	assert.Equal(t, "eu", vars["region"].Default)
+	assert.Equal(t, "Deployment region", vars["region"].Description)
+	assert.Empty(t, vars["version"].Enum, "an unrestricted variable stays unrestricted")
+	assert.Equal(t, "v1", vars["version"].Default, "the first default is kept")
+
+	require.Len(t, m.Warnings(), 1)
+	assert.Contains(t, m.Warnings()[0].Message, `variable "version" default "v2" differs from "v1"`)
+}
+
+func TestMerger_ServerVariableConflictError(t *testing.T) {
+	tempDir, input1, input2 := writeServerSpecs(t)
+
+	cfg := &config.Config{
+		Inputs:                 []config.InputConfig{{InputFile: input1}, {InputFile: input2}},
+		Output:                 filepath.Join(tempDir, "merged.json"),
+		ServersMode:            config.ServersModeUnion,
+		ServerVariableConflict: config.ServerVariableConflictError,
+	}
+	require.NoError(t, cfg.Validate())
+
+	err := New(cfg, false).Merge()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server https://{region}.example.com/{version}: variables region, version differ from an earlier input")
+}
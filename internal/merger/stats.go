@@ -0,0 +1,93 @@
+package merger
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// largestSchemasLimit is the number of schemas reported in SpecStats.LargestSchemas.
+const largestSchemasLimit = 5
+
+// SpecStats holds metrics about a single OpenAPI specification.
+type SpecStats struct {
+	Paths                  int              `json:"paths"`
+	Operations             int              `json:"operations"`
+	OperationsByMethod     map[string]int   `json:"operationsByMethod"`
+	Schemas                int              `json:"schemas"`
+	Parameters             int              `json:"parameters"`
+	OperationParameters    int              `json:"operationParameters"`
+	Tags                   int              `json:"tags"`
+	AvgPropertiesPerSchema float64          `json:"avgPropertiesPerSchema"`
+	LargestSchemas         []SchemaSizeStat `json:"largestSchemas,omitempty"`
+}
+
+// SchemaSizeStat reports the number of properties of a component schema.
+type SchemaSizeStat struct {
+	Name       string `json:"name"`
+	Properties int    `json:"properties"`
+}
+
+// LoadSpec loads a single specification the same way inputs are loaded during a merge,
+// converting Swagger 2.0 to OpenAPI 3.0 if needed.
+func (m *Merger) LoadSpec(filePath string) (*openapi3.T, error) {
+	return m.loadSpec(filePath, nil)
+}
+
+// ComputeStats computes metrics for a specification.
+func ComputeStats(spec *openapi3.T) SpecStats {
+	stats := SpecStats{
+		OperationsByMethod: make(map[string]int),
+		Tags:               len(spec.Tags),
+	}
+
+	if spec.Paths != nil {
+		stats.Paths = spec.Paths.Len()
+		for _, pathItem := range spec.Paths.Map() {
+			if pathItem == nil {
+				continue
+			}
+			for method, op := range getOperationsMap(pathItem) {
+				if op == nil {
+					continue
+				}
+				stats.Operations++
+				stats.OperationsByMethod[method]++
+				stats.OperationParameters += len(op.Parameters) + len(pathItem.Parameters)
+			}
+		}
+	}
+
+	if spec.Components != nil {
+		stats.Schemas = len(spec.Components.Schemas)
+		stats.Parameters = len(spec.Components.Parameters)
+
+		totalProps := 0
+		sizes := make([]SchemaSizeStat, 0, len(spec.Components.Schemas))
+		for name, schema := range spec.Components.Schemas {
+			props := 0
+			if schema != nil && schema.Value != nil {
+				props = len(schema.Value.Properties)
+			}
+			totalProps += props
+			sizes = append(sizes, SchemaSizeStat{Name: name, Properties: props})
+		}
+
+		if stats.Schemas > 0 {
+			stats.AvgPropertiesPerSchema = float64(totalProps) / float64(stats.Schemas)
+		}
+
+		sort.Slice(sizes, func(i, j int) bool {
+			if sizes[i].Properties != sizes[j].Properties {
+				return sizes[i].Properties > sizes[j].Properties
+			}
+			return sizes[i].Name < sizes[j].Name
+		})
+		if len(sizes) > largestSchemasLimit {
+			sizes = sizes[:largestSchemasLimit]
+		}
+		stats.LargestSchemas = sizes
+	}
+
+	return stats
+}
@@ -0,0 +1,77 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "API", "version": "1.0.0"},
+		"tags": [{"name": "Users"}, {"name": "Orders"}],
+		"paths": {
+			"/users": {
+				"parameters": [{"name": "X-Tenant", "in": "header", "schema": {"type": "string"}}],
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/Page"}],
+					"responses": {"200": {"description": "Success"}}
+				},
+				"post": {"responses": {"201": {"description": "Created"}}}
+			},
+			"/orders": {
+				"get": {"responses": {"200": {"description": "Success"}}}
+			}
+		},
+		"components": {
+			"parameters": {
+				"Page": {"name": "page", "in": "query", "schema": {"type": "integer"}}
+			},
+			"schemas": {
+				"User": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "string"},
+						"name": {"type": "string"},
+						"email": {"type": "string"}
+					}
+				},
+				"Order": {
+					"type": "object",
+					"properties": {"id": {"type": "string"}}
+				},
+				"Status": {"type": "string"}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	loaded, err := New(&config.Config{}, false).LoadSpec(specPath)
+	require.NoError(t, err)
+
+	stats := ComputeStats(loaded)
+	assert.Equal(t, 2, stats.Paths)
+	assert.Equal(t, 3, stats.Operations)
+	assert.Equal(t, map[string]int{"GET": 2, "POST": 1}, stats.OperationsByMethod)
+	assert.Equal(t, 3, stats.Schemas)
+	assert.Equal(t, 1, stats.Parameters)
+	assert.Equal(t, 3, stats.OperationParameters)
+	assert.Equal(t, 2, stats.Tags)
+	assert.InDelta(t, 4.0/3.0, stats.AvgPropertiesPerSchema, 0.001)
+	assert.Equal(t, []SchemaSizeStat{
+		{Name: "User", Properties: 3},
+		{Name: "Order", Properties: 1},
+		{Name: "Status", Properties: 0},
+	}, stats.LargestSchemas)
+}
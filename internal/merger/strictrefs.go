@@ -0,0 +1,50 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// verifyRefs proves that every reference in the merged spec resolves. A
+// serialized copy is loaded with kin-openapi, which follows references
+// transitively and fails on any missing or mistyped target; the loaded copy
+// is discarded. External references are kept as written and not followed.
+func (m *Merger) verifyRefs() error {
+	data, err := json.Marshal(m.master)
+	if err != nil {
+		return fmt.Errorf("failed to serialize merged spec: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to serialize merged spec: %w", err)
+	}
+
+	// List every broken local reference; the loader stops at the first one
+	if unresolved := unresolvedLocalRefs(raw); len(unresolved) > 0 {
+		return fmt.Errorf("unresolved references in merged spec: %s", strings.Join(unresolved, ", "))
+	}
+
+	if isOpenAPI31(raw) {
+		lowerExclusiveBounds(raw)
+		if data, err = json.Marshal(raw); err != nil {
+			return fmt.Errorf("failed to serialize merged spec: %w", err)
+		}
+	}
+
+	loader := openapi3.NewLoader()
+	if err := keepExternalRefs(loader, raw); err != nil {
+		return err
+	}
+	if _, err := loader.LoadFromData(data); err != nil {
+		return fmt.Errorf("unresolved references in merged spec: %w", err)
+	}
+
+	if m.verbose {
+		fmt.Fprintln(m.log, "All references resolve")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_StrictRefs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"parameters": [{"$ref": "#/components/parameters/Limit"}],
+					"responses": {
+						"200": {
+							"description": "Success",
+							"content": {
+								"application/json": {"schema": {"$ref": "#/components/schemas/User"}}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"address": {"$ref": "#/components/schemas/UserAddress"}}},
+				"UserAddress": {"type": "object", "properties": {"city": {"type": "string"}}}
+			},
+			"parameters": {
+				"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	run := func(t *testing.T, sel *config.ComponentSelectionConfig) (string, error) {
+		outputPath := filepath.Join(tempDir, t.Name()+".json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(outputPath), 0755))
+		cfg := &config.Config{
+			Inputs:     []config.InputConfig{{InputFile: specPath, Components: sel}},
+			Output:     outputPath,
+			StrictRefs: true,
+		}
+		return outputPath, New(cfg, false).Merge()
+	}
+
+	t.Run("all references resolve", func(t *testing.T) {
+		outputPath, err := run(t, nil)
+		require.NoError(t, err)
+		assert.FileExists(t, outputPath)
+	})
+
+	t.Run("reference to a pruned component", func(t *testing.T) {
+		// The $ref string is unchanged, but its target was filtered out
+		outputPath, err := run(t, &config.ComponentSelectionConfig{Exclude: []string{"Limit"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unresolved references in merged spec")
+		assert.Contains(t, err.Error(), "#/components/parameters/Limit (at #/paths/~1users/get/parameters/0)")
+		assert.NoFileExists(t, outputPath)
+	})
+}
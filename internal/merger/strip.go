@@ -0,0 +1,337 @@
+package merger
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// stripExtensions removes every x- extension from the spec, from the root
+// down to schemas, examples and security flows. Other keys the loader keeps
+// among the extensions, such as the webhooks and path items of OpenAPI 3.1,
+// are left in place and their contents stripped too.
+// Referenced values are not followed; they are stripped through components,
+// which also keeps recursive schemas from looping forever.
+func stripExtensions(spec *openapi3.T) {
+	dropExtensions(spec.Extensions)
+	if spec.Info != nil {
+		dropExtensions(spec.Info.Extensions)
+		if spec.Info.Contact != nil {
+			dropExtensions(spec.Info.Contact.Extensions)
+		}
+		if spec.Info.License != nil {
+			dropExtensions(spec.Info.License.Extensions)
+		}
+	}
+	stripServersExtensions(spec.Servers)
+	for _, tag := range spec.Tags {
+		if tag != nil {
+			dropExtensions(tag.Extensions)
+			stripExternalDocsExtensions(tag.ExternalDocs)
+		}
+	}
+	stripExternalDocsExtensions(spec.ExternalDocs)
+
+	if spec.Paths != nil {
+		dropExtensions(spec.Paths.Extensions)
+		for _, pathItem := range spec.Paths.Map() {
+			stripPathItemExtensions(pathItem)
+		}
+	}
+	for _, pathItem := range specWebhooks(spec) {
+		stripPathItemExtensions(pathItem)
+	}
+	if spec.Components != nil {
+		stripComponentsExtensions(spec.Components)
+	}
+}
+
+// dropExtensions deletes the x- keys of an extensions map.
+func dropExtensions(extensions map[string]interface{}) {
+	for key := range extensions {
+		if strings.HasPrefix(key, "x-") {
+			delete(extensions, key)
+		}
+	}
+}
+
+// stripServersExtensions strips servers and their variables.
+func stripServersExtensions(servers openapi3.Servers) {
+	for _, server := range servers {
+		stripServerExtensions(server)
+	}
+}
+
+// stripServerExtensions strips a server and its variables.
+func stripServerExtensions(server *openapi3.Server) {
+	if server == nil {
+		return
+	}
+	dropExtensions(server.Extensions)
+	for _, variable := range server.Variables {
+		if variable != nil {
+			dropExtensions(variable.Extensions)
+		}
+	}
+}
+
+// stripExternalDocsExtensions strips an external documentation object.
+func stripExternalDocsExtensions(docs *openapi3.ExternalDocs) {
+	if docs != nil {
+		dropExtensions(docs.Extensions)
+	}
+}
+
+// stripPathItemExtensions strips a path item and its operations.
+func stripPathItemExtensions(pathItem *openapi3.PathItem) {
+	if pathItem == nil {
+		return
+	}
+	dropExtensions(pathItem.Extensions)
+	stripServersExtensions(pathItem.Servers)
+	for _, param := range pathItem.Parameters {
+		stripParameterExtensions(param)
+	}
+	for _, op := range getOperationsMap(pathItem) {
+		if op != nil {
+			stripOperationExtensions(op)
+		}
+	}
+}
+
+// stripOperationExtensions strips an operation and everything it defines inline.
+func stripOperationExtensions(op *openapi3.Operation) {
+	dropExtensions(op.Extensions)
+	stripExternalDocsExtensions(op.ExternalDocs)
+	if op.Servers != nil {
+		stripServersExtensions(*op.Servers)
+	}
+	for _, param := range op.Parameters {
+		stripParameterExtensions(param)
+	}
+	stripRequestBodyExtensions(op.RequestBody)
+	if op.Responses != nil {
+		dropExtensions(op.Responses.Extensions)
+		for _, resp := range op.Responses.Map() {
+			stripResponseExtensions(resp)
+		}
+	}
+	for _, callback := range op.Callbacks {
+		stripCallbackExtensions(callback)
+	}
+}
+
+// stripParameterExtensions strips a parameter ref.
+func stripParameterExtensions(paramRef *openapi3.ParameterRef) {
+	if paramRef == nil {
+		return
+	}
+	dropExtensions(paramRef.Extensions)
+	if paramRef.Ref != "" || paramRef.Value == nil {
+		return
+	}
+	dropExtensions(paramRef.Value.Extensions)
+	stripSchemaExtensions(paramRef.Value.Schema)
+	stripContentExtensions(paramRef.Value.Content)
+	stripExamplesExtensions(paramRef.Value.Examples)
+}
+
+// stripSchemaExtensions strips a schema ref and its nested schemas.
+func stripSchemaExtensions(schemaRef *openapi3.SchemaRef) {
+	if schemaRef == nil {
+		return
+	}
+	dropExtensions(schemaRef.Extensions)
+	if schemaRef.Ref != "" || schemaRef.Value == nil {
+		return
+	}
+
+	schema := schemaRef.Value
+	dropExtensions(schema.Extensions)
+	stripExternalDocsExtensions(schema.ExternalDocs)
+	if schema.Discriminator != nil {
+		dropExtensions(schema.Discriminator.Extensions)
+	}
+	if schema.XML != nil {
+		dropExtensions(schema.XML.Extensions)
+	}
+
+	stripSchemaExtensions(schema.Items)
+	for _, prop := range schema.Properties {
+		stripSchemaExtensions(prop)
+	}
+	stripSchemaExtensions(schema.AdditionalProperties.Schema)
+	for _, s := range schema.AllOf {
+		stripSchemaExtensions(s)
+	}
+	for _, s := range schema.OneOf {
+		stripSchemaExtensions(s)
+	}
+	for _, s := range schema.AnyOf {
+		stripSchemaExtensions(s)
+	}
+	stripSchemaExtensions(schema.Not)
+}
+
+// stripRequestBodyExtensions strips a request body ref.
+func stripRequestBodyExtensions(bodyRef *openapi3.RequestBodyRef) {
+	if bodyRef == nil {
+		return
+	}
+	dropExtensions(bodyRef.Extensions)
+	if bodyRef.Ref != "" || bodyRef.Value == nil {
+		return
+	}
+	dropExtensions(bodyRef.Value.Extensions)
+	stripContentExtensions(bodyRef.Value.Content)
+}
+
+// stripResponseExtensions strips a response ref with its headers and links.
+func stripResponseExtensions(respRef *openapi3.ResponseRef) {
+	if respRef == nil {
+		return
+	}
+	dropExtensions(respRef.Extensions)
+	if respRef.Ref != "" || respRef.Value == nil {
+		return
+	}
+	dropExtensions(respRef.Value.Extensions)
+	stripContentExtensions(respRef.Value.Content)
+	for _, header := range respRef.Value.Headers {
+		stripHeaderExtensions(header)
+	}
+	for _, link := range respRef.Value.Links {
+		stripLinkExtensions(link)
+	}
+}
+
+// stripHeaderExtensions strips a header ref.
+func stripHeaderExtensions(headerRef *openapi3.HeaderRef) {
+	if headerRef == nil {
+		return
+	}
+	dropExtensions(headerRef.Extensions)
+	if headerRef.Ref != "" || headerRef.Value == nil {
+		return
+	}
+	dropExtensions(headerRef.Value.Extensions)
+	stripSchemaExtensions(headerRef.Value.Schema)
+	stripContentExtensions(headerRef.Value.Content)
+	stripExamplesExtensions(headerRef.Value.Examples)
+}
+
+// stripLinkExtensions strips a link ref.
+func stripLinkExtensions(linkRef *openapi3.LinkRef) {
+	if linkRef == nil {
+		return
+	}
+	dropExtensions(linkRef.Extensions)
+	if linkRef.Ref != "" || linkRef.Value == nil {
+		return
+	}
+	dropExtensions(linkRef.Value.Extensions)
+	stripServerExtensions(linkRef.Value.Server)
+}
+
+// stripCallbackExtensions strips a callback ref and its path items.
+func stripCallbackExtensions(callbackRef *openapi3.CallbackRef) {
+	if callbackRef == nil {
+		return
+	}
+	dropExtensions(callbackRef.Extensions)
+	if callbackRef.Ref != "" || callbackRef.Value == nil {
+		return
+	}
+	dropExtensions(callbackRef.Value.Extensions)
+	for _, pathItem := range callbackRef.Value.Map() {
+		stripPathItemExtensions(pathItem)
+	}
+}
+
+// stripContentExtensions strips the media types of a content map.
+func stripContentExtensions(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType == nil {
+			continue
+		}
+		dropExtensions(mediaType.Extensions)
+		stripSchemaExtensions(mediaType.Schema)
+		stripExamplesExtensions(mediaType.Examples)
+		for _, encoding := range mediaType.Encoding {
+			if encoding == nil {
+				continue
+			}
+			dropExtensions(encoding.Extensions)
+			for _, header := range encoding.Headers {
+				stripHeaderExtensions(header)
+			}
+		}
+	}
+}
+
+// stripExamplesExtensions strips an examples map. Example values are data
+// and are left untouched.
+func stripExamplesExtensions(examples openapi3.Examples) {
+	for _, example := range examples {
+		if example == nil {
+			continue
+		}
+		dropExtensions(example.Extensions)
+		if example.Ref == "" && example.Value != nil {
+			dropExtensions(example.Value.Extensions)
+		}
+	}
+}
+
+// stripSecuritySchemeExtensions strips a security scheme ref and its flows.
+func stripSecuritySchemeExtensions(schemeRef *openapi3.SecuritySchemeRef) {
+	if schemeRef == nil {
+		return
+	}
+	dropExtensions(schemeRef.Extensions)
+	if schemeRef.Ref != "" || schemeRef.Value == nil {
+		return
+	}
+	dropExtensions(schemeRef.Value.Extensions)
+	if flows := schemeRef.Value.Flows; flows != nil {
+		dropExtensions(flows.Extensions)
+		for _, flow := range []*openapi3.OAuthFlow{flows.Implicit, flows.Password, flows.ClientCredentials, flows.AuthorizationCode} {
+			if flow != nil {
+				dropExtensions(flow.Extensions)
+			}
+		}
+	}
+}
+
+// stripComponentsExtensions strips components and every component in them.
+func stripComponentsExtensions(components *openapi3.Components) {
+	dropExtensions(components.Extensions)
+	for _, schema := range components.Schemas {
+		stripSchemaExtensions(schema)
+	}
+	for _, param := range components.Parameters {
+		stripParameterExtensions(param)
+	}
+	for _, resp := range components.Responses {
+		stripResponseExtensions(resp)
+	}
+	for _, body := range components.RequestBodies {
+		stripRequestBodyExtensions(body)
+	}
+	for _, header := range components.Headers {
+		stripHeaderExtensions(header)
+	}
+	for _, scheme := range components.SecuritySchemes {
+		stripSecuritySchemeExtensions(scheme)
+	}
+	for _, link := range components.Links {
+		stripLinkExtensions(link)
+	}
+	for _, callback := range components.Callbacks {
+		stripCallbackExtensions(callback)
+	}
+	stripExamplesExtensions(components.Examples)
+	for _, pathItem := range componentPathItems(components) {
+		stripPathItemExtensions(pathItem)
+	}
+}
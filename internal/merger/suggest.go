@@ -0,0 +1,123 @@
+package merger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// Conflict describes a component that two inputs define differently.
+type Conflict struct {
+	// Kind is the component bucket, e.g. "schemas" or "parameters"
+	Kind string
+
+	// Name is the component name
+	Name string
+
+	// First is the input that defined the component first
+	First string
+
+	// Second is the input whose definition collided with it
+	Second string
+}
+
+// PrefixSuggestion proposes a dispute prefix for an input.
+type PrefixSuggestion struct {
+	// InputFile is the input the prefix should be added to
+	InputFile string
+
+	// Prefix is the suggested dispute prefix
+	Prefix string
+
+	// Conflicts are the collisions the prefix resolves
+	Conflicts []Conflict
+}
+
+// SuggestPrefixes runs the merge without writing output, collecting every
+// component collision, and suggests a dispute prefix for each input that
+// collides with an earlier one.
+func (m *Merger) SuggestPrefixes() ([]PrefixSuggestion, error) {
+	m.collectConflicts = true
+	defer func() { m.collectConflicts = false }()
+
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+
+	byInput := make(map[string]*PrefixSuggestion)
+	usedPrefixes := make(map[string]bool)
+	var suggestions []*PrefixSuggestion
+	for _, input := range m.cfg.Inputs {
+		for _, c := range m.conflicts {
+			if c.Second != input.InputFile {
+				continue
+			}
+			s, ok := byInput[input.InputFile]
+			if !ok {
+				s = &PrefixSuggestion{
+					InputFile: input.InputFile,
+					Prefix:    uniquePrefix(suggestedPrefix(&input), usedPrefixes),
+				}
+				byInput[input.InputFile] = s
+				suggestions = append(suggestions, s)
+			}
+			s.Conflicts = append(s.Conflicts, c)
+		}
+	}
+
+	result := make([]PrefixSuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+// recordConflict records a component collision when conflicts are being
+// collected, reporting whether the merge should carry on.
+func (m *Merger) recordConflict(kind, name string, input *config.InputConfig) bool {
+	if !m.collectConflicts {
+		return false
+	}
+	m.conflicts = append(m.conflicts, Conflict{
+		Kind:   kind,
+		Name:   name,
+		First:  m.componentSources[kind+"/"+name],
+		Second: input.InputFile,
+	})
+	return true
+}
+
+// suggestedPrefix derives a dispute prefix from the input's label or file
+// name, e.g. "orders-api.yaml" becomes "OrdersApi_".
+func suggestedPrefix(input *config.InputConfig) string {
+	name := input.Label
+	if name == "" {
+		base := filepath.Base(input.InputFile)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	if b.Len() == 0 {
+		b.WriteString("Input")
+	}
+	b.WriteString("_")
+	return b.String()
+}
+
+// uniquePrefix returns prefix, numbered if it is already taken, and marks it used.
+func uniquePrefix(prefix string, used map[string]bool) string {
+	candidate := prefix
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d_", strings.TrimSuffix(prefix, "_"), i)
+	}
+	used[candidate] = true
+	return candidate
+}
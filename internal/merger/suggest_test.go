@@ -0,0 +1,83 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_SuggestPrefixes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"id": {"type": "string"}}},
+				"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+			}
+		}
+	}`
+
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"User": {"type": "object", "properties": {"id": {"type": "integer"}}},
+				"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+			}
+		}
+	}`
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	ordersPath := filepath.Join(tempDir, "orders-api.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(users), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(orders), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{
+			{InputFile: usersPath},
+			{InputFile: ordersPath},
+		},
+		Output: outputPath,
+	}
+
+	// A regular merge fails on the collision
+	require.Error(t, New(cfg, false).Merge())
+
+	suggestions, err := New(cfg, false).SuggestPrefixes()
+	require.NoError(t, err)
+
+	// Only the differing User schema conflicts; identical Error schemas merge
+	assert.Equal(t, []PrefixSuggestion{
+		{
+			InputFile: ordersPath,
+			Prefix:    "OrdersApi_",
+			Conflicts: []Conflict{
+				{Kind: "schemas", Name: "User", First: usersPath, Second: ordersPath},
+			},
+		},
+	}, suggestions)
+
+	// Nothing is written
+	_, err = os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// Labels take precedence over file names
+	cfg.Inputs[1].Label = "orders"
+	suggestions, err = New(cfg, false).SuggestPrefixes()
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "Orders_", suggestions[0].Prefix)
+}
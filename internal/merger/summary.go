@@ -0,0 +1,161 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MergeSummary describes what a merge did without the merged document itself.
+type MergeSummary struct {
+	Inputs       []InputSummary     `json:"inputs"`
+	Components   map[string]int     `json:"components"`
+	Renames      []ComponentRename  `json:"renames,omitempty"`
+	Skipped      []SkippedOperation `json:"skipped,omitempty"`
+	PathRewrites []PathRewrite      `json:"pathRewrites,omitempty"`
+}
+
+// InputSummary counts the paths and operations an input contributed after
+// its filters and modifications.
+type InputSummary struct {
+	Source     string `json:"source"`
+	Paths      int    `json:"paths"`
+	Operations int    `json:"operations"`
+}
+
+// SkippedOperation records an operation dropped from an input by a filter.
+type SkippedOperation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// PathRewrite records a path of an input that appears under a different path
+// in the output, after path modification, variable normalization and the
+// global basePath.
+type PathRewrite struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Source string `json:"source"`
+}
+
+// DryRun runs the whole merge, including reference verification, without
+// writing anything and returns a summary of the result.
+func (m *Merger) DryRun() (*MergeSummary, error) {
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+
+	if m.cfg.StrictRefs {
+		if err := m.verifyRefs(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.Summary(), nil
+}
+
+// Summary returns the summary of the last merge.
+func (m *Merger) Summary() *MergeSummary {
+	return &MergeSummary{
+		Inputs:       m.inputs,
+		Components:   componentCounts(m.master.Components),
+		Renames:      m.renames,
+		Skipped:      m.skipped,
+		PathRewrites: m.pathRewrites,
+	}
+}
+
+// recordInput records the paths and operations an input is about to merge.
+func (m *Merger) recordInput(spec *openapi3.T, source string) {
+	summary := InputSummary{Source: source}
+	if spec.Paths != nil {
+		summary.Paths = spec.Paths.Len()
+		forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+			summary.Operations++
+		})
+	}
+	m.inputs = append(m.inputs, summary)
+}
+
+// recordSkipped records an operation dropped from an input by a filter.
+func (m *Merger) recordSkipped(source, method, path, reason string) {
+	m.skipped = append(m.skipped, SkippedOperation{Method: method, Path: path, Source: source, Reason: reason})
+}
+
+// recordPathRewrite records that a path of source moved from one path to
+// another. A path that was already rewritten is followed to its new target,
+// so each entry maps the input's original path to its final one.
+func (m *Merger) recordPathRewrite(source, from, to string) {
+	if from == to {
+		return
+	}
+	for i := range m.pathRewrites {
+		if r := &m.pathRewrites[i]; r.Source == source && r.To == from {
+			r.To = to
+			return
+		}
+	}
+	m.pathRewrites = append(m.pathRewrites, PathRewrite{From: from, To: to, Source: source})
+}
+
+// pathItemSources returns the distinct input files that contributed
+// operations to a merged path item, in sorted order.
+func (m *Merger) pathItemSources(pathItem *openapi3.PathItem) []string {
+	sources := make(map[string]bool)
+	for _, op := range getOperationsMap(pathItem) {
+		if source, ok := m.sources[op]; ok {
+			sources[source] = true
+		}
+	}
+	return sortedKeys(sources)
+}
+
+// writeReport writes the merge summary as JSON to path.
+func (m *Merger) writeReport(path string) error {
+	data, err := marshalJSONIndent(m.Summary(), "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := m.ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if m.verbose {
+		fmt.Fprintf(m.log, "Wrote merge report to %s\n", path)
+	}
+
+	return nil
+}
+
+// componentCounts counts the components of each kind, leaving out empty kinds.
+func componentCounts(c *openapi3.Components) map[string]int {
+	counts := make(map[string]int)
+	if c == nil {
+		return counts
+	}
+	for kind, n := range map[string]int{
+		"schemas":         len(c.Schemas),
+		"responses":       len(c.Responses),
+		"parameters":      len(c.Parameters),
+		"securitySchemes": len(c.SecuritySchemes),
+		"requestBodies":   len(c.RequestBodies),
+		"examples":        len(c.Examples),
+		"headers":         len(c.Headers),
+		"links":           len(c.Links),
+		"callbacks":       len(c.Callbacks),
+		"pathItems":       len(componentPathItems(c)),
+	} {
+		if n > 0 {
+			counts[kind] = n
+		}
+	}
+	return counts
+}
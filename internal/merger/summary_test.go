@@ -0,0 +1,157 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_DryRun(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {"operationId": "listUsers", "responses": {"200": {"description": "OK"}}},
+				"post": {"operationId": "createUser", "responses": {"201": {"description": "Created"}}}
+			},
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"delete": {"operationId": "deleteUser", "responses": {"204": {"description": "Deleted"}}}
+			}
+		},
+		"components": {
+			"schemas": {"User": {"type": "object"}}
+		}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"operationId": "listOrders", "responses": {"200": {"description": "OK"}}}
+			}
+		},
+		"components": {
+			"schemas": {"User": {"type": "object", "properties": {"id": {"type": "string"}}}},
+			"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}}
+		}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{users, orders} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+	inputs[0].OperationSelection = &config.OperationSelectionConfig{
+		ExcludePaths: []config.PathFilter{{Path: "/users/{id}", Method: "DELETE"}},
+	}
+	inputs[1].Dispute = &config.DisputeConfig{Prefix: "Orders_"}
+
+	output := filepath.Join(tempDir, "merged.json")
+	cfg := &config.Config{Inputs: inputs, Output: output}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, false)
+	summary, err := m.DryRun()
+	require.NoError(t, err)
+
+	_, err = os.Stat(output)
+	assert.True(t, os.IsNotExist(err), "dry run must not write the output")
+
+	assert.Equal(t, []InputSummary{
+		{Source: inputs[0].InputFile, Paths: 1, Operations: 2},
+		{Source: inputs[1].InputFile, Paths: 1, Operations: 1},
+	}, summary.Inputs)
+	assert.Equal(t, map[string]int{"schemas": 2, "parameters": 1}, summary.Components)
+	assert.Equal(t, []ComponentRename{
+		{Kind: "schemas", From: "User", To: "Orders_User", Source: inputs[1].InputFile},
+		{Kind: "parameters", From: "Limit", To: "Orders_Limit", Source: inputs[1].InputFile},
+	}, summary.Renames)
+	assert.Equal(t, []SkippedOperation{
+		{Method: "DELETE", Path: "/users/{id}", Source: inputs[0].InputFile, Reason: "operationSelection"},
+	}, summary.Skipped)
+}
+
+func TestMerger_ReportFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	items := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Items", "version": "1.0.0"},
+		"paths": {
+			"/v1/items/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"get": {"operationId": "getItem", "responses": {"200": {"description": "OK"}}}
+			},
+			"/v1/health": {
+				"get": {"operationId": "health", "responses": {"200": {"description": "OK"}}}
+			}
+		},
+		"components": {"schemas": {"Item": {"type": "object"}}}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"operationId": "listOrders", "responses": {"200": {"description": "OK"}}},
+				"delete": {"operationId": "purgeOrders", "responses": {"204": {"description": "Deleted"}}}
+			}
+		},
+		"components": {"schemas": {"Item": {"type": "object", "properties": {"sku": {"type": "string"}}}}}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{items, orders} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+	inputs[0].PathModification = &config.PathModificationConfig{StripStart: "/v1"}
+	inputs[1].Dispute = &config.DisputeConfig{Prefix: "Orders_"}
+	inputs[1].OperationSelection = &config.OperationSelectionConfig{
+		ExcludePaths: []config.PathFilter{{Path: "/orders", Method: "DELETE"}},
+	}
+
+	reportPath := filepath.Join(tempDir, "reports", "merge.json")
+	cfg := &config.Config{
+		Inputs:                    inputs,
+		Output:                    filepath.Join(tempDir, "merged.json"),
+		BasePath:                  "/api",
+		PathVariableNormalization: config.PathVariableNormalizationCanonical,
+		ReportFile:                reportPath,
+	}
+	require.NoError(t, cfg.Validate())
+	require.NoError(t, New(cfg, false).Merge())
+
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	var report MergeSummary
+	require.NoError(t, json.Unmarshal(data, &report))
+
+	assert.Equal(t, []PathRewrite{
+		{From: "/v1/health", To: "/api/health", Source: inputs[0].InputFile},
+		{From: "/v1/items/{id}", To: "/api/items/{itemId}", Source: inputs[0].InputFile},
+		{From: "/orders", To: "/api/orders", Source: inputs[1].InputFile},
+	}, report.PathRewrites)
+	assert.Equal(t, []ComponentRename{
+		{Kind: "schemas", From: "Item", To: "Orders_Item", Source: inputs[1].InputFile},
+	}, report.Renames)
+	assert.Equal(t, []SkippedOperation{
+		{Method: "DELETE", Path: "/orders", Source: inputs[1].InputFile, Reason: "operationSelection"},
+	}, report.Skipped)
+}
@@ -0,0 +1,149 @@
+package merger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// mergeTag adds an input's tag to the master tags. A tag that is already
+// declared keeps its position and is kept, replaced or combined with the new
+// definition according to TagMergeStrategy.
+func (m *Merger) mergeTag(tag *openapi3.Tag) {
+	if !m.hasTag(tag.Name) {
+		// Copy so combining never modifies the input's tag
+		added := *tag
+		m.addTag(&added)
+		return
+	}
+
+	for i, existing := range m.master.Tags {
+		if existing.Name != tag.Name {
+			continue
+		}
+		switch m.cfg.TagMergeStrategy {
+		case config.TagMergeLast:
+			replaced := *tag
+			m.master.Tags[i] = &replaced
+		case config.TagMergeCombine:
+			combineTag(existing, tag)
+		}
+		return
+	}
+}
+
+// combineTag merges tag into existing: distinct non-empty descriptions are
+// joined as paragraphs, and externalDocs and extensions fill in what existing
+// lacks.
+func combineTag(existing, tag *openapi3.Tag) {
+	description := strings.TrimSpace(tag.Description)
+	if description != "" && !containsParagraph(existing.Description, description) {
+		if existing.Description == "" {
+			existing.Description = description
+		} else {
+			existing.Description = strings.TrimSpace(existing.Description) + "\n\n" + description
+		}
+	}
+
+	if existing.ExternalDocs == nil {
+		existing.ExternalDocs = tag.ExternalDocs
+	}
+
+	for key, value := range tag.Extensions {
+		if _, ok := existing.Extensions[key]; ok {
+			continue
+		}
+		if existing.Extensions == nil {
+			existing.Extensions = make(map[string]interface{})
+		}
+		existing.Extensions[key] = value
+	}
+}
+
+// containsParagraph reports whether text already has paragraph as one of its
+// blank-line separated paragraphs.
+func containsParagraph(text, paragraph string) bool {
+	for _, p := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(p) == paragraph {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUndeclaredTags finds operation tags missing from the root tags array.
+// Missing tags are declared when AutoDeclareTags is set; otherwise each one is
+// reported as a warning, or as an error in strict mode.
+func (m *Merger) checkUndeclaredTags() error {
+	if m.master.Paths == nil {
+		return nil
+	}
+
+	// Map each undeclared tag to the input of the first operation using it
+	undeclared := make(map[string]string)
+	forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+		for _, tag := range op.Tags {
+			if m.hasTag(tag) {
+				continue
+			}
+			if _, seen := undeclared[tag]; !seen {
+				undeclared[tag] = m.sources[op]
+			}
+		}
+	})
+
+	if len(undeclared) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(undeclared))
+	for name := range undeclared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if m.cfg.AutoDeclareTags {
+		for _, name := range names {
+			m.addTag(&openapi3.Tag{Name: name})
+		}
+		if m.verbose {
+			fmt.Fprintf(m.log, "Declared %d missing tags: %s\n", len(names), strings.Join(names, ", "))
+		}
+		return nil
+	}
+
+	if m.cfg.Strict {
+		return fmt.Errorf("operations reference undeclared tags: %s", strings.Join(names, ", "))
+	}
+
+	for _, name := range names {
+		m.warnf(undeclared[name], "tag %q is used by operations but not declared in tags", name)
+	}
+	return nil
+}
+
+// tagOperationCountExtension is set on each tag by annotateTagCounts.
+const tagOperationCountExtension = "x-operation-count"
+
+// annotateTagCounts sets x-operation-count on every root tag to the number of
+// merged operations tagged with it. Tags no operation uses get a count of 0.
+func (m *Merger) annotateTagCounts() {
+	counts := make(map[string]int)
+	if m.master.Paths != nil {
+		forEachOperation(m.master.Paths, func(path, method string, op *openapi3.Operation) {
+			for _, tag := range op.Tags {
+				counts[tag]++
+			}
+		})
+	}
+
+	for _, tag := range m.master.Tags {
+		if tag.Extensions == nil {
+			tag.Extensions = make(map[string]interface{})
+		}
+		tag.Extensions[tagOperationCountExtension] = counts[tag.Name]
+	}
+}
@@ -0,0 +1,275 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_UndeclaredTags(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"tags": [{"name": "Users", "description": "User operations"}],
+		"paths": {
+			"/users": {
+				"get": {
+					"tags": ["Users", "Reports"],
+					"responses": {"200": {"description": "Success"}}
+				},
+				"post": {
+					"tags": ["Admin"],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Inputs: []config.InputConfig{{InputFile: specPath}},
+			Output: outputPath,
+		}
+	}
+
+	t.Run("warns", func(t *testing.T) {
+		m := New(newConfig(), false)
+		require.NoError(t, m.Merge())
+
+		assert.Equal(t, []Warning{
+			{Source: specPath, Message: `tag "Admin" is used by operations but not declared in tags`},
+			{Source: specPath, Message: `tag "Reports" is used by operations but not declared in tags`},
+		}, m.Warnings())
+		assert.Len(t, m.master.Tags, 1)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Strict = true
+
+		err := New(cfg, false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "undeclared tags: Admin, Reports")
+	})
+
+	t.Run("auto declare", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.AutoDeclareTags = true
+		cfg.Strict = true
+
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		assert.Empty(t, m.Warnings())
+		var names []string
+		for _, tag := range m.master.Tags {
+			names = append(names, tag.Name)
+		}
+		assert.Equal(t, []string{"Users", "Admin", "Reports"}, names)
+	})
+}
+
+func TestMerger_TagOrderIsFirstSeen(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	inputTags := [][]string{
+		{"Orders", "Accounts", "Orders"},
+		{"Billing", "Accounts", "Zones"},
+		{"Zones", "Audit"},
+	}
+
+	var inputs []config.InputConfig
+	for i, tags := range inputTags {
+		declared := ""
+		for j, tag := range tags {
+			if j > 0 {
+				declared += ","
+			}
+			declared += fmt.Sprintf(`{"name": %q, "description": "from input %d"}`, tag, i+1)
+		}
+		spec := fmt.Sprintf(`{"openapi": "3.0.0", "info": {"title": "API %d", "version": "1.0.0"}, "tags": [%s], "paths": {}}`, i+1, declared)
+
+		specPath := filepath.Join(tempDir, fmt.Sprintf("api%d.json", i+1))
+		require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+
+	cfg := &config.Config{
+		Inputs: inputs,
+		Output: filepath.Join(tempDir, "merged.json"),
+	}
+
+	for i := 0; i < 5; i++ {
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+
+		var names []string
+		for _, tag := range m.master.Tags {
+			names = append(names, tag.Name)
+		}
+		assert.Equal(t, []string{"Orders", "Accounts", "Billing", "Zones", "Audit"}, names)
+		// The first definition of a tag wins
+		assert.Equal(t, "from input 1", m.master.Tags[1].Description)
+		assert.Equal(t, "from input 2", m.master.Tags[3].Description)
+	}
+}
+
+func BenchmarkMerger_MergeTags(b *testing.B) {
+	// 50 inputs with 200 tags each, half of them shared with the next input
+	specs := make([]*openapi3.T, 50)
+	for i := range specs {
+		tags := make(openapi3.Tags, 200)
+		for j := range tags {
+			tags[j] = &openapi3.Tag{Name: fmt.Sprintf("Tag%d", i*100+j)}
+		}
+		specs[i] = &openapi3.T{Tags: tags}
+	}
+	cfg := &config.Config{}
+	input := &config.InputConfig{}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		m := New(cfg, false)
+		m.master = &openapi3.T{}
+		m.tagNames = make(map[string]bool)
+		for _, spec := range specs {
+			if err := m.mergeSpec(spec, input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestMerger_AnnotateTagCounts(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"tags": [{"name": "Users"}, {"name": "Admin"}, {"name": "Unused"}],
+		"paths": {
+			"/users": {
+				"get": {"tags": ["Users"], "responses": {"200": {"description": "OK"}}},
+				"post": {"tags": ["Users", "Admin"], "responses": {"201": {"description": "Created"}}}
+			},
+			"/users/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"get": {"tags": ["Users"], "responses": {"200": {"description": "OK"}}},
+				"delete": {"tags": ["Admin"], "responses": {"204": {"description": "Deleted"}}}
+			}
+		}
+	}`
+
+	specPath := filepath.Join(tempDir, "users.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+
+	cfg := &config.Config{
+		Inputs: []config.InputConfig{{
+			InputFile: specPath,
+			OperationSelection: &config.OperationSelectionConfig{
+				ExcludePaths: []config.PathFilter{{Path: "/users/{id}", Method: "DELETE"}},
+			},
+		}},
+		Output:            filepath.Join(tempDir, "merged.json"),
+		AnnotateTagCounts: true,
+	}
+
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	counts := make(map[string]interface{})
+	for _, tag := range m.master.Tags {
+		counts[tag.Name] = tag.Extensions["x-operation-count"]
+	}
+	// The filtered-out DELETE does not count towards Admin
+	assert.Equal(t, map[string]interface{}{"Users": 3, "Admin": 1, "Unused": 0}, counts)
+}
+
+func TestMerger_TagMergeStrategy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	invoices := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Invoices", "version": "1.0.0"},
+		"tags": [
+			{"name": "Billing", "description": "Invoices are issued monthly."},
+			{"name": "Invoices"}
+		],
+		"paths": {}
+	}`
+	payments := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Payments", "version": "1.0.0"},
+		"tags": [
+			{"name": "Billing", "description": "Payments are captured on shipping.", "externalDocs": {"url": "https://docs.example.com/billing"}}
+		],
+		"paths": {}
+	}`
+	refunds := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Refunds", "version": "1.0.0"},
+		"tags": [
+			{"name": "Billing", "description": "Invoices are issued monthly.", "externalDocs": {"url": "https://docs.example.com/refunds"}}
+		],
+		"paths": {}
+	}`
+
+	var inputs []config.InputConfig
+	for i, spec := range []string{invoices, payments, refunds} {
+		path := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: path})
+	}
+
+	billing := func(t *testing.T, strategy string) *openapi3.Tag {
+		cfg := &config.Config{
+			Inputs:           inputs,
+			Output:           filepath.Join(tempDir, "merged.json"),
+			TagMergeStrategy: strategy,
+		}
+		require.NoError(t, cfg.Validate())
+		m := New(cfg, false)
+		require.NoError(t, m.Merge())
+		require.Len(t, m.master.Tags, 2)
+		assert.Equal(t, "Billing", m.master.Tags[0].Name)
+		return m.master.Tags[0]
+	}
+
+	t.Run("first", func(t *testing.T) {
+		tag := billing(t, "")
+		assert.Equal(t, "Invoices are issued monthly.", tag.Description)
+		assert.Nil(t, tag.ExternalDocs)
+	})
+
+	t.Run("last", func(t *testing.T) {
+		tag := billing(t, config.TagMergeLast)
+		assert.Equal(t, "Invoices are issued monthly.", tag.Description)
+		assert.Equal(t, "https://docs.example.com/refunds", tag.ExternalDocs.URL)
+	})
+
+	t.Run("combine", func(t *testing.T) {
+		tag := billing(t, config.TagMergeCombine)
+		assert.Equal(t, "Invoices are issued monthly.\n\nPayments are captured on shipping.", tag.Description)
+		assert.Equal(t, "https://docs.example.com/billing", tag.ExternalDocs.URL)
+	})
+}
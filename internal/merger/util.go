@@ -1,13 +1,32 @@
 package merger
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gobwas/glob"
-	"github.com/rperez95/openapi-merge/internal/config"
+	"github.com/rperez95/openapi-merge/pkg/config"
 )
 
+// httpMethods lists the supported HTTP methods in a stable order.
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE"}
+
+// sortedPaths returns the path keys of a Paths object in alphabetical order.
+func sortedPaths(paths *openapi3.Paths) []string {
+	if paths == nil {
+		return nil
+	}
+	keys := make([]string, 0, paths.Len())
+	for path := range paths.Map() {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // getOperationsMap returns a map of HTTP method to operation.
 func getOperationsMap(pathItem *openapi3.PathItem) map[string]*openapi3.Operation {
 	return map[string]*openapi3.Operation{
@@ -67,6 +86,19 @@ func matchPathFilter(path, method string, filter config.PathFilter) bool {
 	return matchGlob(filter.Path, path)
 }
 
+// matchExtensionFilter checks if extensions contain the filter's key and, if a
+// value is given, whether the extension value matches it.
+func matchExtensionFilter(extensions map[string]interface{}, filter config.ExtensionFilter) bool {
+	value, ok := extensions[filter.Key]
+	if !ok {
+		return false
+	}
+	if filter.Value == "" {
+		return true
+	}
+	return fmt.Sprint(value) == filter.Value
+}
+
 // matchGlob performs glob matching on a path.
 func matchGlob(pattern, path string) bool {
 	// Handle exact match
@@ -84,48 +116,132 @@ func matchGlob(pattern, path string) bool {
 	return g.Match(path)
 }
 
-// mergePathItem merges operations from source into destination.
-func mergePathItem(dest, src *openapi3.PathItem) {
-	if src.Get != nil && dest.Get == nil {
-		dest.Get = src.Get
-	}
-	if src.Post != nil && dest.Post == nil {
-		dest.Post = src.Post
-	}
-	if src.Put != nil && dest.Put == nil {
-		dest.Put = src.Put
+// matchStatusCodes checks if a response code matches any of the patterns.
+// An empty pattern list matches every code.
+func matchStatusCodes(patterns []string, code string) bool {
+	if len(patterns) == 0 {
+		return true
 	}
-	if src.Delete != nil && dest.Delete == nil {
-		dest.Delete = src.Delete
+	for _, pattern := range patterns {
+		if matchStatusCode(pattern, code) {
+			return true
+		}
 	}
-	if src.Patch != nil && dest.Patch == nil {
-		dest.Patch = src.Patch
+	return false
+}
+
+// matchStatusCode checks if a response code matches a pattern.
+// An "X" in the pattern matches any digit (e.g., 2XX matches 200 and 2XX),
+// and "*" matches every code.
+func matchStatusCode(pattern, code string) bool {
+	if pattern == "*" {
+		return true
 	}
-	if src.Head != nil && dest.Head == nil {
-		dest.Head = src.Head
+	if len(pattern) != len(code) {
+		return false
 	}
-	if src.Options != nil && dest.Options == nil {
-		dest.Options = src.Options
+	for i := 0; i < len(pattern); i++ {
+		p := pattern[i]
+		c := code[i]
+		if p == 'X' || p == 'x' {
+			if (c >= '0' && c <= '9') || c == 'X' || c == 'x' {
+				continue
+			}
+			return false
+		}
+		if p != c {
+			return false
+		}
 	}
-	if src.Trace != nil && dest.Trace == nil {
-		dest.Trace = src.Trace
+	return true
+}
+
+// mergePathItem merges operations from source into destination. When both
+// define a method the destination operation is kept; the methods where the
+// dropped operation differs from the kept one are returned.
+func mergePathItem(dest, src *openapi3.PathItem) []string {
+	var drifted []string
+	for _, method := range httpMethods {
+		op := src.GetOperation(method)
+		if op == nil {
+			continue
+		}
+		if existing := dest.GetOperation(method); existing != nil {
+			if !jsonEqual(existing, op) {
+				drifted = append(drifted, method)
+			}
+			continue
+		}
+		dest.SetOperation(method, op)
 	}
 
 	// Merge parameters
-	if len(src.Parameters) > 0 {
-		for _, param := range src.Parameters {
-			exists := false
-			for _, existingParam := range dest.Parameters {
-				if existingParam.Value != nil && param.Value != nil &&
-					existingParam.Value.Name == param.Value.Name &&
-					existingParam.Value.In == param.Value.In {
-					exists = true
-					break
-				}
+	mergePathItemParameters(dest, src)
+
+	// Keep the summary, description and extensions dest lacks
+	if dest.Summary == "" {
+		dest.Summary = src.Summary
+	}
+	if dest.Description == "" {
+		dest.Description = src.Description
+	}
+	for key, value := range src.Extensions {
+		if _, ok := dest.Extensions[key]; !ok {
+			if dest.Extensions == nil {
+				dest.Extensions = make(map[string]interface{})
 			}
-			if !exists {
-				dest.Parameters = append(dest.Parameters, param)
+			dest.Extensions[key] = value
+		}
+	}
+
+	return drifted
+}
+
+// jsonEqual reports whether a and b serialize to the same JSON.
+func jsonEqual[V any](a, b V) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+// mergePathItemParameters adds path-level parameters from src that dest lacks.
+func mergePathItemParameters(dest, src *openapi3.PathItem) {
+	dest.Parameters = appendMissingParameters(dest.Parameters, src.Parameters)
+}
+
+// appendMissingParameters appends the parameters from src whose name and
+// location are not already in dest.
+func appendMissingParameters(dest, src openapi3.Parameters) openapi3.Parameters {
+	for _, param := range src {
+		exists := false
+		for _, existingParam := range dest {
+			if existingParam.Value != nil && param.Value != nil &&
+				existingParam.Value.Name == param.Value.Name &&
+				existingParam.Value.In == param.Value.In {
+				exists = true
+				break
 			}
 		}
+		if !exists {
+			dest = append(dest, param)
+		}
+	}
+	return dest
+}
+
+// overlayOperation adds the parameters and responses from src that dest lacks.
+func overlayOperation(dest, src *openapi3.Operation) {
+	dest.Parameters = appendMissingParameters(dest.Parameters, src.Parameters)
+
+	if src.Responses == nil {
+		return
+	}
+	if dest.Responses == nil {
+		dest.Responses = openapi3.NewResponsesWithCapacity(src.Responses.Len())
+	}
+	for code, resp := range src.Responses.Map() {
+		if dest.Responses.Value(code) == nil {
+			dest.Responses.Set(code, resp)
+		}
 	}
 }
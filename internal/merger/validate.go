@@ -0,0 +1,120 @@
+package merger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kinds of ValidationProblem.
+const (
+	// ProblemRef is a $ref that does not resolve
+	ProblemRef = "ref"
+
+	// ProblemOperationID is an operationId used by more than one operation
+	ProblemOperationID = "operationId"
+
+	// ProblemSpec is a violation reported by the OpenAPI validator, or by the
+	// OpenAPI 3.1 schema for 3.1 output
+	ProblemSpec = "spec"
+)
+
+// ValidationProblem is an issue found in the merged specification.
+type ValidationProblem struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (p ValidationProblem) String() string {
+	if p.Location == "" {
+		return fmt.Sprintf("[%s] %s", p.Kind, p.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", p.Kind, p.Location, p.Message)
+}
+
+// Validate runs the merge without writing output and checks the result:
+// every local $ref must resolve, operationIds must be unique, and the spec
+// must pass OpenAPI validation. The error is only set when the merge itself
+// fails; problems in the result are returned instead.
+func (m *Merger) Validate() ([]ValidationProblem, error) {
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(m.master)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize merged spec: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to serialize merged spec: %w", err)
+	}
+
+	var problems []ValidationProblem
+	uses := unresolvedLocalRefUses(raw)
+	sort.Slice(uses, func(i, j int) bool { return uses[i].Location < uses[j].Location })
+	for _, use := range uses {
+		problems = append(problems, ValidationProblem{
+			Kind:     ProblemRef,
+			Location: use.Location,
+			Message:  fmt.Sprintf("%s does not resolve", use.Ref),
+		})
+	}
+
+	duplicates := duplicateOperationIDs(m.master)
+	problems = append(problems, duplicates...)
+
+	// The validator only knows OpenAPI 3.0 and stops at the first problem, so
+	// 3.1 output is checked against the 3.1 schema instead
+	if m.targetsOpenAPI31() {
+		schemaProblems, err := openAPI31SchemaProblems(raw)
+		if err != nil {
+			return nil, err
+		}
+		for _, problem := range schemaProblems {
+			problems = append(problems, ValidationProblem{Kind: ProblemSpec, Location: problem.Location, Message: problem.Message})
+		}
+	} else {
+		if err := m.master.Validate(context.Background()); err != nil {
+			sameID := strings.Contains(err.Error(), "have the same operation id")
+			if !sameID || len(duplicates) == 0 {
+				problems = append(problems, ValidationProblem{Kind: ProblemSpec, Message: err.Error()})
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// duplicateOperationIDs reports each operationId shared by several
+// operations, listing all of them.
+func duplicateOperationIDs(spec *openapi3.T) []ValidationProblem {
+	if spec.Paths == nil {
+		return nil
+	}
+
+	users := make(map[string][]string)
+	forEachOperation(spec.Paths, func(path, method string, op *openapi3.Operation) {
+		if op.OperationID != "" {
+			users[op.OperationID] = append(users[op.OperationID], method+" "+path)
+		}
+	})
+
+	var problems []ValidationProblem
+	for _, id := range sortedKeys(users) {
+		if len(users[id]) < 2 {
+			continue
+		}
+		problems = append(problems, ValidationProblem{
+			Kind:     ProblemOperationID,
+			Location: id,
+			Message:  "used by " + strings.Join(users[id], ", "),
+		})
+	}
+	return problems
+}
@@ -0,0 +1,119 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Validate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	users := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Users", "version": "1.0.0"},
+		"paths": {
+			"/users": {
+				"get": {
+					"operationId": "list",
+					"parameters": [{"$ref": "#/components/parameters/Limit"}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {"User": {"type": "object"}},
+			"parameters": {"Limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}}
+		}
+	}`
+	orders := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Orders", "version": "1.0.0"},
+		"paths": {
+			"/orders": {
+				"get": {"operationId": "list", "responses": {"200": {"description": "OK"}}}
+			}
+		}
+	}`
+
+	usersPath := filepath.Join(tempDir, "users.json")
+	ordersPath := filepath.Join(tempDir, "orders.json")
+	outputPath := filepath.Join(tempDir, "merged.json")
+	require.NoError(t, os.WriteFile(usersPath, []byte(users), 0644))
+	require.NoError(t, os.WriteFile(ordersPath, []byte(orders), 0644))
+
+	t.Run("problems", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: usersPath, Components: &config.ComponentSelectionConfig{Exclude: []string{"Limit"}}},
+				{InputFile: ordersPath},
+			},
+			Output: outputPath,
+		}
+		problems, err := New(cfg, false).Validate()
+		require.NoError(t, err)
+
+		assert.Equal(t, []ValidationProblem{
+			{
+				Kind:     ProblemRef,
+				Location: "#/paths/~1users/get/parameters/0",
+				Message:  "#/components/parameters/Limit does not resolve",
+			},
+			{Kind: ProblemOperationID, Location: "list", Message: "used by GET /orders, GET /users"},
+		}, problems)
+		assert.NoFileExists(t, outputPath)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		cfg := &config.Config{
+			Inputs: []config.InputConfig{
+				{InputFile: usersPath},
+				{InputFile: ordersPath, OperationIDPrefix: "orders_"},
+			},
+			Output: outputPath,
+		}
+		problems, err := New(cfg, false).Validate()
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+		assert.NoFileExists(t, outputPath)
+	})
+
+	t.Run("openapi 3.1", func(t *testing.T) {
+		// The 3.0 validator does not know 3.1, so the 3.1 schema is used
+		events := `{
+			"openapi": "3.1.0",
+			"info": {"title": "Events", "version": "1.0.0"},
+			"paths": {
+				"/events": {
+					"get": {"operationId": "events", "responses": {"200": {"content": {}}}}
+				}
+			}
+		}`
+		eventsPath := filepath.Join(tempDir, "events.json")
+		require.NoError(t, os.WriteFile(eventsPath, []byte(events), 0644))
+
+		cfg := &config.Config{
+			Inputs:         []config.InputConfig{{InputFile: eventsPath}},
+			Output:         outputPath,
+			OpenAPIVersion: config.OpenAPIVersion31,
+		}
+		problems, err := New(cfg, false).Validate()
+		require.NoError(t, err)
+		assert.Equal(t, []ValidationProblem{{
+			Kind:     ProblemSpec,
+			Location: "#/paths/~1events/get/responses/200",
+			Message:  "missing property 'description'",
+		}}, problems)
+	})
+}
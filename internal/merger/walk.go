@@ -0,0 +1,159 @@
+package merger
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// forEachOperation calls fn for every operation in paths, ordered by path and method.
+func forEachOperation(paths *openapi3.Paths, fn func(path, method string, op *openapi3.Operation)) {
+	for _, path := range sortedPaths(paths) {
+		pathItem := paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		operations := getOperationsMap(pathItem)
+		for _, method := range httpMethods {
+			if op := operations[method]; op != nil {
+				fn(path, method, op)
+			}
+		}
+	}
+}
+
+// walkSchemaRef calls fn for the schema and every nested inline schema.
+// Referenced schemas are not followed; they are visited through components,
+// which also keeps recursive schemas from looping forever.
+func walkSchemaRef(schemaRef *openapi3.SchemaRef, fn func(*openapi3.Schema)) {
+	if schemaRef == nil || schemaRef.Ref != "" || schemaRef.Value == nil {
+		return
+	}
+
+	schema := schemaRef.Value
+	fn(schema)
+
+	walkSchemaRef(schema.Items, fn)
+	for _, prop := range schema.Properties {
+		walkSchemaRef(prop, fn)
+	}
+	walkSchemaRef(schema.AdditionalProperties.Schema, fn)
+	for _, s := range schema.AllOf {
+		walkSchemaRef(s, fn)
+	}
+	for _, s := range schema.OneOf {
+		walkSchemaRef(s, fn)
+	}
+	for _, s := range schema.AnyOf {
+		walkSchemaRef(s, fn)
+	}
+	walkSchemaRef(schema.Not, fn)
+}
+
+// walkSpecSchemas calls fn for every schema defined in the spec, both in
+// components and inline in paths.
+func walkSpecSchemas(spec *openapi3.T, fn func(*openapi3.Schema)) {
+	if spec.Components != nil {
+		for _, schema := range spec.Components.Schemas {
+			walkSchemaRef(schema, fn)
+		}
+		for _, param := range spec.Components.Parameters {
+			walkParameterSchemas(param, fn)
+		}
+		for _, header := range spec.Components.Headers {
+			walkHeaderSchemas(header, fn)
+		}
+		for _, body := range spec.Components.RequestBodies {
+			walkRequestBodySchemas(body, fn)
+		}
+		for _, resp := range spec.Components.Responses {
+			walkResponseSchemas(resp, fn)
+		}
+		for _, callback := range spec.Components.Callbacks {
+			walkCallbackSchemas(callback, fn)
+		}
+	}
+
+	if spec.Paths != nil {
+		for _, pathItem := range spec.Paths.Map() {
+			walkPathItemSchemas(pathItem, fn)
+		}
+	}
+}
+
+// walkPathItemSchemas calls fn for every inline schema in a path item.
+func walkPathItemSchemas(pathItem *openapi3.PathItem, fn func(*openapi3.Schema)) {
+	if pathItem == nil {
+		return
+	}
+
+	for _, param := range pathItem.Parameters {
+		walkParameterSchemas(param, fn)
+	}
+
+	for _, op := range getOperationsMap(pathItem) {
+		if op == nil {
+			continue
+		}
+		for _, param := range op.Parameters {
+			walkParameterSchemas(param, fn)
+		}
+		walkRequestBodySchemas(op.RequestBody, fn)
+		if op.Responses != nil {
+			for _, resp := range op.Responses.Map() {
+				walkResponseSchemas(resp, fn)
+			}
+		}
+		for _, callback := range op.Callbacks {
+			walkCallbackSchemas(callback, fn)
+		}
+	}
+}
+
+func walkParameterSchemas(paramRef *openapi3.ParameterRef, fn func(*openapi3.Schema)) {
+	if paramRef == nil || paramRef.Ref != "" || paramRef.Value == nil {
+		return
+	}
+	walkSchemaRef(paramRef.Value.Schema, fn)
+	walkContentSchemas(paramRef.Value.Content, fn)
+}
+
+func walkHeaderSchemas(headerRef *openapi3.HeaderRef, fn func(*openapi3.Schema)) {
+	if headerRef == nil || headerRef.Ref != "" || headerRef.Value == nil {
+		return
+	}
+	walkSchemaRef(headerRef.Value.Schema, fn)
+	walkContentSchemas(headerRef.Value.Content, fn)
+}
+
+func walkRequestBodySchemas(bodyRef *openapi3.RequestBodyRef, fn func(*openapi3.Schema)) {
+	if bodyRef == nil || bodyRef.Ref != "" || bodyRef.Value == nil {
+		return
+	}
+	walkContentSchemas(bodyRef.Value.Content, fn)
+}
+
+func walkResponseSchemas(respRef *openapi3.ResponseRef, fn func(*openapi3.Schema)) {
+	if respRef == nil || respRef.Ref != "" || respRef.Value == nil {
+		return
+	}
+	walkContentSchemas(respRef.Value.Content, fn)
+	for _, header := range respRef.Value.Headers {
+		walkHeaderSchemas(header, fn)
+	}
+}
+
+func walkCallbackSchemas(callbackRef *openapi3.CallbackRef, fn func(*openapi3.Schema)) {
+	if callbackRef == nil || callbackRef.Ref != "" || callbackRef.Value == nil {
+		return
+	}
+	for _, pathItem := range callbackRef.Value.Map() {
+		walkPathItemSchemas(pathItem, fn)
+	}
+}
+
+func walkContentSchemas(content openapi3.Content, fn func(*openapi3.Schema)) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			walkSchemaRef(mediaType.Schema, fn)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package merger
+
+import (
+	"fmt"
+)
+
+// Warning represents a non-fatal issue found during the merge.
+type Warning struct {
+	// Source is the input file the warning relates to (empty if unknown)
+	Source string
+
+	// Message describes the issue
+	Message string
+
+	// Validation marks the validation findings the loader reports for an
+	// input, which are noisy and only shown in verbose mode by the CLI
+	Validation bool
+}
+
+// String returns a human-readable representation of the warning.
+func (w Warning) String() string {
+	if w.Source == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Source, w.Message)
+}
+
+// InputError is returned when processing a specific input file fails.
+type InputError struct {
+	// Source is the input file that failed
+	Source string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *InputError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// Warnings returns the warnings collected during the last merge.
+func (m *Merger) Warnings() []Warning {
+	return m.warnings
+}
+
+// warnf records a warning for the given source file. Warnings are not
+// printed here; callers report Warnings once the merge is done.
+func (m *Merger) warnf(source, format string, args ...interface{}) {
+	w := Warning{
+		Source:  source,
+		Message: fmt.Sprintf(format, args...),
+	}
+	m.warnings = append(m.warnings, w)
+}
+
+// validationWarnf records a validation finding of the loader for the given
+// source file.
+func (m *Merger) validationWarnf(source, format string, args ...interface{}) {
+	m.warnf(source, format, args...)
+	m.warnings[len(m.warnings)-1].Validation = true
+}
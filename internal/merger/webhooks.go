@@ -0,0 +1,80 @@
+package merger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// webhooksKey is the root key of the webhooks of OpenAPI 3.1. The loader has
+// no field for them and keeps them among the root extensions.
+const webhooksKey = "webhooks"
+
+// specWebhooks returns the webhooks of spec by name, or nil if there are none.
+// Like componentPathItems, they are decoded on first use and stored back
+// typed.
+func specWebhooks(spec *openapi3.T) map[string]*openapi3.PathItem {
+	raw, ok := spec.Extensions[webhooksKey]
+	if !ok {
+		return nil
+	}
+	if webhooks, ok := raw.(map[string]*openapi3.PathItem); ok {
+		return webhooks
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var webhooks map[string]*openapi3.PathItem
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil
+	}
+	spec.Extensions[webhooksKey] = webhooks
+	return webhooks
+}
+
+// mergeWebhooks merges the webhooks of an input. Webhooks with the same name
+// are combined operation by operation like paths; an operation that differs
+// from the one already merged keeps the earlier one, or fails the merge when
+// onPathConflict is error. Webhooks only exist in OpenAPI 3.1, so for 3.0
+// output they are dropped with a warning.
+func (m *Merger) mergeWebhooks(spec *openapi3.T, input *config.InputConfig) error {
+	webhooks := specWebhooks(spec)
+	if len(webhooks) == 0 {
+		return nil
+	}
+	if !m.targetsOpenAPI31() {
+		m.warnf(input.InputFile, "dropping %d webhooks, which require OpenAPI 3.1 output", len(webhooks))
+		return nil
+	}
+
+	for _, name := range sortedKeys(webhooks) {
+		pathItem := webhooks[name]
+		if pathItem == nil {
+			continue
+		}
+		for _, op := range getOperationsMap(pathItem) {
+			if op != nil {
+				m.sources[op] = input.InputFile
+			}
+		}
+
+		existing := m.webhooks[name]
+		if existing == nil {
+			m.webhooks[name] = pathItem
+			continue
+		}
+		for _, method := range mergePathItem(existing, pathItem) {
+			earlier := m.sources[existing.GetOperation(method)]
+			if m.cfg.OnPathConflict == config.PathConflictError {
+				return fmt.Errorf("webhook %s %s is defined differently by %s and %s", method, name, earlier, input.InputFile)
+			}
+			m.warnf(input.InputFile, "webhook %s %s differs from the operation already merged from %s; keeping the earlier one",
+				method, name, earlier)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_Webhooks(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	specs := []string{
+		`{
+			"openapi": "3.1.0",
+			"info": {"title": "Users", "version": "1.0.0"},
+			"paths": {},
+			"webhooks": {
+				"userCreated": {
+					"post": {
+						"requestBody": {
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}
+						},
+						"responses": {"200": {"description": "OK"}}
+					}
+				}
+			},
+			"components": {
+				"schemas": {"Event": {"type": "object", "title": "UserEvent"}}
+			}
+		}`,
+		`{
+			"openapi": "3.1.0",
+			"info": {"title": "Orders", "version": "1.0.0"},
+			"paths": {},
+			"webhooks": {
+				"orderPlaced": {
+					"post": {
+						"requestBody": {
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}
+						},
+						"responses": {"200": {"description": "OK"}}
+					}
+				},
+				"userCreated": {
+					"post": {"responses": {"204": {"description": "No Content"}}}
+				}
+			},
+			"components": {
+				"schemas": {"Event": {"type": "object", "title": "OrderEvent"}}
+			}
+		}`,
+	}
+
+	var inputs []config.InputConfig
+	for i, spec := range specs {
+		specPath := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+	inputs[1].Dispute = &config.DisputeConfig{Prefix: "Orders"}
+
+	newConfig := func(version, onPathConflict string) *config.Config {
+		return &config.Config{
+			Inputs:         inputs,
+			Output:         filepath.Join(tempDir, "merged.json"),
+			OpenAPIVersion: version,
+			OnPathConflict: onPathConflict,
+		}
+	}
+
+	t.Run("merged for 3.1 output", func(t *testing.T) {
+		m := New(newConfig(config.OpenAPIVersion31, ""), false)
+		require.NoError(t, m.Merge())
+
+		webhooks := specWebhooks(m.master)
+		require.Contains(t, webhooks, "userCreated")
+		require.Contains(t, webhooks, "orderPlaced")
+
+		schemaRef := func(name string) string {
+			return webhooks[name].Post.RequestBody.Value.Content.Get("application/json").Schema.Ref
+		}
+		assert.Equal(t, "#/components/schemas/Event", schemaRef("userCreated"))
+		assert.Equal(t, "#/components/schemas/OrdersEvent", schemaRef("orderPlaced"))
+		assert.Contains(t, m.master.Components.Schemas, "OrdersEvent")
+
+		// The differing userCreated operation keeps the first input's
+		require.NotNil(t, webhooks["userCreated"].Post.Responses.Value("200"))
+		require.Len(t, m.Warnings(), 1)
+		assert.Contains(t, m.Warnings()[0].Message, "webhook POST userCreated differs")
+
+		data, err := os.ReadFile(filepath.Join(tempDir, "merged.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"webhooks"`)
+	})
+
+	t.Run("conflict error", func(t *testing.T) {
+		err := New(newConfig(config.OpenAPIVersion31, config.PathConflictError), false).Merge()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "webhook POST userCreated is defined differently by")
+	})
+
+	t.Run("dropped for 3.0 output", func(t *testing.T) {
+		m := New(newConfig("", ""), false)
+		require.NoError(t, m.Merge())
+
+		assert.NotContains(t, m.master.Extensions, webhooksKey)
+		var messages []string
+		for _, w := range m.Warnings() {
+			messages = append(messages, w.Message)
+		}
+		assert.Contains(t, messages, "dropping 1 webhooks, which require OpenAPI 3.1 output")
+		assert.Contains(t, messages, "dropping 2 webhooks, which require OpenAPI 3.1 output")
+	})
+}
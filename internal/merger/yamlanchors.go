@@ -0,0 +1,136 @@
+package merger
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeKeyTag is the resolved tag of a YAML merge key (<<).
+const mergeKeyTag = "!!merge"
+
+// expandYAMLAliases replaces every alias in a YAML document with a copy of
+// its anchored value and folds merge keys (<<) into their mappings, so the
+// loader sees plain content. Keys written in a mapping win over merged ones,
+// and earlier entries of a merged sequence win over later ones. Documents
+// without aliases are returned unchanged.
+func expandYAMLAliases(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if !hasYAMLAliases(&doc) {
+		return data, nil
+	}
+
+	expanded, err := expandYAMLNode(&doc, make(map[*yaml.Node]bool))
+	if err != nil {
+		return nil, err
+	}
+	out, err := yaml.Marshal(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand YAML aliases: %w", err)
+	}
+	return out, nil
+}
+
+// hasYAMLAliases reports whether a node or any of its children is an alias.
+func hasYAMLAliases(n *yaml.Node) bool {
+	if n.Kind == yaml.AliasNode {
+		return true
+	}
+	for _, child := range n.Content {
+		if hasYAMLAliases(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandYAMLNode returns a copy of n without anchors, aliases or merge keys.
+// expanding holds the anchored nodes whose aliases are being expanded, which
+// catches anchors that contain themselves.
+func expandYAMLNode(n *yaml.Node, expanding map[*yaml.Node]bool) (*yaml.Node, error) {
+	if n.Kind == yaml.AliasNode {
+		if expanding[n.Alias] {
+			return nil, fmt.Errorf("YAML anchor %q contains itself", n.Value)
+		}
+		expanding[n.Alias] = true
+		defer delete(expanding, n.Alias)
+		return expandYAMLNode(n.Alias, expanding)
+	}
+
+	out := *n
+	out.Anchor = ""
+	out.Content = nil
+
+	if n.Kind != yaml.MappingNode {
+		for _, child := range n.Content {
+			expanded, err := expandYAMLNode(child, expanding)
+			if err != nil {
+				return nil, err
+			}
+			out.Content = append(out.Content, expanded)
+		}
+		return &out, nil
+	}
+
+	// Keys written in the mapping itself take precedence over merged ones
+	explicit := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Tag != mergeKeyTag {
+			explicit[n.Content[i].Value] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	add := func(key, value *yaml.Node) error {
+		if seen[key.Value] {
+			return nil
+		}
+		seen[key.Value] = true
+		k, err := expandYAMLNode(key, expanding)
+		if err != nil {
+			return err
+		}
+		v, err := expandYAMLNode(value, expanding)
+		if err != nil {
+			return err
+		}
+		out.Content = append(out.Content, k, v)
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Tag != mergeKeyTag {
+			if err := add(key, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			merged, err := expandYAMLNode(source, expanding)
+			if err != nil {
+				return nil, err
+			}
+			if merged.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("line %d: merge key value is not a mapping", key.Line)
+			}
+			for j := 0; j+1 < len(merged.Content); j += 2 {
+				if !explicit[merged.Content[j].Value] {
+					if err := add(merged.Content[j], merged.Content[j+1]); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,103 @@
+package merger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerger_YAMLAnchorsAndMergeKeys(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	spec := `openapi: 3.0.0
+info:
+  title: Users
+  version: 2024-01-01
+x-defaults:
+  audit: &audit
+    createdAt: {type: string, format: date-time}
+    updatedAt: {type: string, format: date-time}
+  errorResponse: &error
+    description: Error
+    content:
+      application/json:
+        schema: {$ref: '#/components/schemas/Error'}
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+        default: *error
+components:
+  schemas:
+    Base: &base
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+    User:
+      <<: *base
+      description: A user
+      properties:
+        <<: *audit
+        id: {type: string, format: uuid}
+        name: {type: string}
+    Error:
+      <<: [*base]
+      required: [code]
+`
+	input := filepath.Join(tempDir, "users.yaml")
+	require.NoError(t, os.WriteFile(input, []byte(spec), 0644))
+
+	output := filepath.Join(tempDir, "merged.yaml")
+	cfg := &config.Config{Inputs: []config.InputConfig{{InputFile: input}}, Output: output}
+	m := New(cfg, false)
+	require.NoError(t, m.Merge())
+
+	user := m.master.Components.Schemas["User"].Value
+	assert.Equal(t, "A user", user.Description)
+	assert.Equal(t, []string{"id"}, user.Required)
+	assert.ElementsMatch(t, []string{"id", "name", "createdAt", "updatedAt"}, sortedKeys(user.Properties))
+	assert.Equal(t, "uuid", user.Properties["id"].Value.Format, "keys in the mapping win over merged keys")
+
+	errSchema := m.master.Components.Schemas["Error"].Value
+	assert.Equal(t, []string{"code"}, errSchema.Required)
+	assert.Contains(t, errSchema.Properties, "id")
+
+	errResponse := m.master.Paths.Value("/users").Get.Responses.Default().Value
+	assert.Equal(t, "Error", *errResponse.Description)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "<<")
+	assert.NotContains(t, string(data), "*base")
+}
+
+func TestExpandYAMLAliases(t *testing.T) {
+	plain := []byte("a: 1\nb: [x, y]\n")
+	out, err := expandYAMLAliases(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, out, "documents without aliases are left untouched")
+
+	out, err = expandYAMLAliases([]byte("a: &one {x: 1, y: 2}\nb: &two {y: 3, z: 4}\nc:\n  <<: [*one, *two]\n  x: 0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "a: {x: 1, y: 2}\nb: {y: 3, z: 4}\nc:\n    y: 2\n    z: 4\n    x: 0\n", string(out))
+
+	out, err = expandYAMLAliases([]byte("a: &date 2024-01-01\nb: *date\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "a: 2024-01-01\nb: 2024-01-01\n", string(out), "scalars keep their form")
+
+	_, err = expandYAMLAliases([]byte("a: &one 1\nb:\n  <<: *one\n"))
+	assert.Error(t, err)
+}
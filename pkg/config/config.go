@@ -0,0 +1,1636 @@
+// Package config provides configuration types and loading for openapi-merge.
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Config represents the main configuration for the merge operation.
+type Config struct {
+	// Inputs is the list of OpenAPI files to merge
+	Inputs []InputConfig `mapstructure:"inputs" json:"inputs" yaml:"inputs"`
+
+	// Output is the path to save the merged file, or "-" for standard output
+	Output string `mapstructure:"output" json:"output" yaml:"output"`
+
+	// OutputFormat is json or yaml, used when the output path has no .json, .yaml or .yml extension (default json)
+	OutputFormat string `mapstructure:"outputFormat" json:"outputFormat,omitempty" yaml:"outputFormat,omitempty"`
+
+	// OutputNewline ensures the output ends with a trailing newline (default true)
+	OutputNewline *bool `mapstructure:"outputNewline" json:"outputNewline,omitempty" yaml:"outputNewline,omitempty"`
+
+	// Indent is the output indentation, e.g. four spaces or a tab (default two spaces).
+	// YAML output honors space-only values.
+	Indent string `mapstructure:"indent" json:"indent,omitempty" yaml:"indent,omitempty"`
+
+	// OutputHeader prepends a generated-file comment to YAML output
+	OutputHeader bool `mapstructure:"outputHeader" json:"outputHeader,omitempty" yaml:"outputHeader,omitempty"`
+
+	// Checksum writes a SHA-256 sidecar file (<output>.sha256) next to the output
+	Checksum bool `mapstructure:"checksum" json:"checksum,omitempty" yaml:"checksum,omitempty"`
+
+	// OutputURL is an optional endpoint the merged spec is POSTed to after it is written
+	OutputURL string `mapstructure:"outputUrl" json:"outputUrl,omitempty" yaml:"outputUrl,omitempty"`
+
+	// OutputURLHeaders are extra request headers for OutputURL; values expand ${ENV} variables
+	OutputURLHeaders map[string]string `mapstructure:"outputUrlHeaders" json:"outputUrlHeaders,omitempty" yaml:"outputUrlHeaders,omitempty"`
+
+	// CreateOutputDir creates missing output directories (default true)
+	CreateOutputDir *bool `mapstructure:"createOutputDir" json:"createOutputDir,omitempty" yaml:"createOutputDir,omitempty"`
+
+	// BasePath is a global prefix prepended to all paths after individual processing
+	BasePath string `mapstructure:"basePath" json:"basePath,omitempty" yaml:"basePath,omitempty"`
+
+	// BasePathOverlap handles output server URLs whose path already ends with
+	// BasePath: warn (default), strip it from the server URL, or ignore
+	BasePathOverlap string `mapstructure:"basePathOverlap" json:"basePathOverlap,omitempty" yaml:"basePathOverlap,omitempty"`
+
+	// Info contains metadata to override in the final file
+	Info *InfoConfig `mapstructure:"info" json:"info,omitempty" yaml:"info,omitempty"`
+
+	// InfoMode selects the base Info before overrides: config (default), first, last, or primary
+	InfoMode string `mapstructure:"infoMode" json:"infoMode,omitempty" yaml:"infoMode,omitempty"`
+
+	// OpenAPIVersion is the OpenAPI version of the output: 3.0.3 (default) or
+	// 3.1.0, which keeps 3.1 constructs from inputs instead of down-converting them
+	OpenAPIVersion string `mapstructure:"openapiVersion" json:"openapiVersion,omitempty" yaml:"openapiVersion,omitempty"`
+
+	// Servers is the list of servers to replace in the final file
+	Servers []ServerConfig `mapstructure:"servers" json:"servers,omitempty" yaml:"servers,omitempty"`
+
+	// ServersMode controls where output servers come from: config (default) or union
+	ServersMode string `mapstructure:"serversMode" json:"serversMode,omitempty" yaml:"serversMode,omitempty"`
+
+	// ServerVariableConflict controls same-URL servers whose variables differ in
+	// union mode: merge (default) or error
+	ServerVariableConflict string `mapstructure:"serverVariableConflict" json:"serverVariableConflict,omitempty" yaml:"serverVariableConflict,omitempty"`
+
+	// StripConvertedServers drops servers synthesized from Swagger 2.0 host/basePath/schemes
+	StripConvertedServers bool `mapstructure:"stripConvertedServers" json:"stripConvertedServers,omitempty" yaml:"stripConvertedServers,omitempty"`
+
+	// FormDataMode sets the media type of request bodies converted from Swagger 2.0
+	// formData parameters: urlencoded or multipart (default: the operation's consumes)
+	FormDataMode string `mapstructure:"formDataMode" json:"formDataMode,omitempty" yaml:"formDataMode,omitempty"`
+
+	// SecuritySchemes defines authentication methods (OAS3 components.securitySchemes)
+	SecuritySchemes map[string]SecuritySchemeConfig `mapstructure:"securitySchemes" json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+
+	// SecuritySchemeAliases maps security scheme names to the canonical name they
+	// are renamed to after merge, e.g. jwtAuth: bearerAuth
+	SecuritySchemeAliases map[string]string `mapstructure:"securitySchemeAliases" json:"securitySchemeAliases,omitempty" yaml:"securitySchemeAliases,omitempty"`
+
+	// Security contains global security requirements
+	Security []map[string][]string `mapstructure:"security" json:"security,omitempty" yaml:"security,omitempty"`
+
+	// SecurityMergeStrategy controls the global security of the inputs: replace
+	// (default) drops it in favor of security, merge combines both
+	SecurityMergeStrategy string `mapstructure:"securityMergeStrategy" json:"securityMergeStrategy,omitempty" yaml:"securityMergeStrategy,omitempty"`
+
+	// GlobalParameters are injected into every merged operation that does not already define them
+	GlobalParameters []ParameterConfig `mapstructure:"globalParameters" json:"globalParameters,omitempty" yaml:"globalParameters,omitempty"`
+
+	// GlobalResponses are added to every merged operation, keyed by status code, unless it already defines the status
+	GlobalResponses map[string]ResponseConfig `mapstructure:"globalResponses" json:"globalResponses,omitempty" yaml:"globalResponses,omitempty"`
+
+	// ConflictPolicy sets per component type how same-named components that
+	// differ between inputs are resolved
+	ConflictPolicy *ConflictPolicyConfig `mapstructure:"conflictPolicy" json:"conflictPolicy,omitempty" yaml:"conflictPolicy,omitempty"`
+
+	// OnPathConflict controls how an operation that differs from one already merged
+	// for the same path and method is handled: first (default), error or prefix
+	OnPathConflict string `mapstructure:"onPathConflict" json:"onPathConflict,omitempty" yaml:"onPathConflict,omitempty"`
+
+	// SchemaConflict controls how same-named schemas that differ are handled: error (default) or merge-enums
+	SchemaConflict string `mapstructure:"schemaConflict" json:"schemaConflict,omitempty" yaml:"schemaConflict,omitempty"`
+
+	// Fetch configures how remote (URL) inputs are fetched
+	Fetch *FetchConfig `mapstructure:"fetch" json:"fetch,omitempty" yaml:"fetch,omitempty"`
+
+	// Auth configures credentials for remote inputs
+	Auth *AuthConfig `mapstructure:"auth" json:"auth,omitempty" yaml:"auth,omitempty"`
+
+	// HTTP sets the timeout and retries used when fetching remote inputs
+	HTTP *HTTPConfig `mapstructure:"http" json:"http,omitempty" yaml:"http,omitempty"`
+
+	// AutoDeclareTags adds a root tag entry for every operation tag that is not declared
+	AutoDeclareTags bool `mapstructure:"autoDeclareTags" json:"autoDeclareTags,omitempty" yaml:"autoDeclareTags,omitempty"`
+
+	// DefaultAdditionalProperties is set on object schemas that do not declare
+	// additionalProperties (unset leaves them unchanged)
+	DefaultAdditionalProperties *bool `mapstructure:"defaultAdditionalProperties" json:"defaultAdditionalProperties,omitempty" yaml:"defaultAdditionalProperties,omitempty"`
+
+	// OperationIDStyle rewrites every operationId to camelCase, snake_case or kebab-case
+	OperationIDStyle string `mapstructure:"operationIdStyle" json:"operationIdStyle,omitempty" yaml:"operationIdStyle,omitempty"`
+
+	// GenerateMissingOperationIDs derives an operationId from method and path for
+	// merged operations that have none
+	GenerateMissingOperationIDs bool `mapstructure:"generateMissingOperationIds" json:"generateMissingOperationIds,omitempty" yaml:"generateMissingOperationIds,omitempty"`
+
+	// GeneratedOperationIDStyle formats generated operationIds: snake_case (default), camelCase or kebab-case
+	GeneratedOperationIDStyle string `mapstructure:"generatedOperationIdStyle" json:"generatedOperationIdStyle,omitempty" yaml:"generatedOperationIdStyle,omitempty"`
+
+	// PathVariableNormalization renames path template variables so that routes
+	// like /items/{id} and /items/{itemId} merge: canonical (or empty to disable)
+	PathVariableNormalization string `mapstructure:"pathVariableNormalization" json:"pathVariableNormalization,omitempty" yaml:"pathVariableNormalization,omitempty"`
+
+	// PathVariableNames overrides the canonical variable name used after a path
+	// segment, e.g. people: personId
+	PathVariableNames map[string]string `mapstructure:"pathVariableNames" json:"pathVariableNames,omitempty" yaml:"pathVariableNames,omitempty"`
+
+	// AnnotateTagCounts sets x-operation-count on every root tag to the number of
+	// merged operations using it
+	AnnotateTagCounts bool `mapstructure:"annotateTagCounts" json:"annotateTagCounts,omitempty" yaml:"annotateTagCounts,omitempty"`
+
+	// StripInternal removes operations, parameters, schemas and properties marked
+	// x-internal: true from the output
+	StripInternal bool `mapstructure:"stripInternal" json:"stripInternal,omitempty" yaml:"stripInternal,omitempty"`
+
+	// PruneUnusedComponents removes components that nothing outside components
+	// references, directly or through other components
+	PruneUnusedComponents bool `mapstructure:"pruneUnusedComponents" json:"pruneUnusedComponents,omitempty" yaml:"pruneUnusedComponents,omitempty"`
+
+	// HoistExamples moves repeated inline examples into components.examples and references them
+	HoistExamples bool `mapstructure:"hoistExamples" json:"hoistExamples,omitempty" yaml:"hoistExamples,omitempty"`
+
+	// DeduplicateSchemas keeps one copy of structurally identical component schemas and points every $ref at it
+	DeduplicateSchemas bool `mapstructure:"deduplicateSchemas" json:"deduplicateSchemas,omitempty" yaml:"deduplicateSchemas,omitempty"`
+
+	// ResponseView limits the responses of every operation: all (default),
+	// success-only or errors-only
+	ResponseView string `mapstructure:"responseView" json:"responseView,omitempty" yaml:"responseView,omitempty"`
+
+	// DefaultErrorResponse adds a default error response to every operation without one
+	DefaultErrorResponse *DefaultErrorResponseConfig `mapstructure:"defaultErrorResponse" json:"defaultErrorResponse,omitempty" yaml:"defaultErrorResponse,omitempty"`
+
+	// KeepExternalRefs leaves $refs to other files unresolved and unchanged in the output
+	KeepExternalRefs bool `mapstructure:"keepExternalRefs" json:"keepExternalRefs,omitempty" yaml:"keepExternalRefs,omitempty"`
+
+	// BundleExternalRefs copies the targets of $refs to other files into components
+	BundleExternalRefs bool `mapstructure:"bundleExternalRefs" json:"bundleExternalRefs,omitempty" yaml:"bundleExternalRefs,omitempty"`
+
+	// ValidateDefaults checks that schema default values conform to their schemas
+	ValidateDefaults bool `mapstructure:"validateDefaults" json:"validateDefaults,omitempty" yaml:"validateDefaults,omitempty"`
+
+	// Strict turns consistency warnings into errors
+	Strict bool `mapstructure:"strict" json:"strict,omitempty" yaml:"strict,omitempty"`
+
+	// AnnotateRenames records the original name and input file of every renamed
+	// component in x-original-name and x-source extensions
+	AnnotateRenames bool `mapstructure:"annotateRenames" json:"annotateRenames,omitempty" yaml:"annotateRenames,omitempty"`
+
+	// StrictRefs resolves every $ref of the merged spec before writing it and
+	// fails the merge if any does not resolve
+	StrictRefs bool `mapstructure:"strictRefs" json:"strictRefs,omitempty" yaml:"strictRefs,omitempty"`
+
+	// TagOrder defines the order of tags in the output
+	TagOrder []string `mapstructure:"tagOrder" json:"tagOrder,omitempty" yaml:"tagOrder,omitempty"`
+
+	// ExtensionConflict controls x- extensions on the root or info that inputs define
+	// differently: first (default), last or error. x-tagGroups are always concatenated.
+	ExtensionConflict string `mapstructure:"extensionConflict" json:"extensionConflict,omitempty" yaml:"extensionConflict,omitempty"`
+
+	// TagMergeStrategy controls tags declared by several inputs: first (default), last or combine
+	TagMergeStrategy string `mapstructure:"tagMergeStrategy" json:"tagMergeStrategy,omitempty" yaml:"tagMergeStrategy,omitempty"`
+
+	// PathsOrder defines high-priority paths that should appear first
+	PathsOrder []string `mapstructure:"pathsOrder" json:"pathsOrder,omitempty" yaml:"pathsOrder,omitempty"`
+
+	// MaxInlineDepth moves inline object schemas nested deeper than this into
+	// components.schemas (0 = unlimited)
+	MaxInlineDepth int `mapstructure:"maxInlineDepth" json:"maxInlineDepth,omitempty" yaml:"maxInlineDepth,omitempty"`
+
+	// MaxDescriptionLength truncates longer descriptions with an ellipsis (0 = unlimited)
+	MaxDescriptionLength int `mapstructure:"maxDescriptionLength" json:"maxDescriptionLength,omitempty" yaml:"maxDescriptionLength,omitempty"`
+
+	// OperationIndex is an optional path to write a per-operation index (JSON or CSV)
+	OperationIndex string `mapstructure:"operationIndex" json:"operationIndex,omitempty" yaml:"operationIndex,omitempty"`
+
+	// ReportFile is an optional path to write a JSON report of renamed
+	// components, skipped operations and path rewrites
+	ReportFile string `mapstructure:"reportFile" json:"reportFile,omitempty" yaml:"reportFile,omitempty"`
+
+	// LockFile records the SHA-256 of every remote input and verifies later fetches against it
+	LockFile string `mapstructure:"lockFile" json:"lockFile,omitempty" yaml:"lockFile,omitempty"`
+
+	// UpdateLock rewrites lock file entries instead of verifying them (set by --update-lock)
+	UpdateLock bool `mapstructure:"-" json:"-" yaml:"-"`
+
+	// Coverage sets documentation coverage thresholds checked after merge
+	Coverage *CoverageConfig `mapstructure:"coverage" json:"coverage,omitempty" yaml:"coverage,omitempty"`
+
+	// RefRewrite rewrites $refs after merge; the first matching rule applies
+	RefRewrite []RefRewriteConfig `mapstructure:"refRewrite" json:"refRewrite,omitempty" yaml:"refRewrite,omitempty"`
+
+	// OperationPolicies attach extensions (e.g. x-timeout) to matching merged operations
+	OperationPolicies []OperationPolicyConfig `mapstructure:"operationPolicies" json:"operationPolicies,omitempty" yaml:"operationPolicies,omitempty"`
+
+	// Patches are JSON Patch (RFC 6902) operations applied to the merged spec after the other overrides
+	Patches []PatchConfig `mapstructure:"patches" json:"patches,omitempty" yaml:"patches,omitempty"`
+}
+
+// Supported values for Config.InfoMode.
+const (
+	// InfoModeConfig starts from built-in defaults; only the info config is applied
+	InfoModeConfig = "config"
+
+	// InfoModeFirst uses the first input's Info as the base
+	InfoModeFirst = "first"
+
+	// InfoModeLast uses the last input's Info as the base
+	InfoModeLast = "last"
+
+	// InfoModePrimary uses the Info of the input marked primary as the base
+	InfoModePrimary = "primary"
+)
+
+// Supported values for Config.OperationIDStyle.
+const (
+	// OperationIDStyleCamel formats operationIds like listUserOrders
+	OperationIDStyleCamel = "camelCase"
+
+	// OperationIDStyleSnake formats operationIds like list_user_orders
+	OperationIDStyleSnake = "snake_case"
+
+	// OperationIDStyleKebab formats operationIds like list-user-orders
+	OperationIDStyleKebab = "kebab-case"
+)
+
+// Supported values for Config.PathVariableNormalization.
+const (
+	// PathVariableNormalizationCanonical names each path variable after the
+	// segment before it, e.g. /items/{id} becomes /items/{itemId}
+	PathVariableNormalizationCanonical = "canonical"
+)
+
+// Supported values for Config.OpenAPIVersion.
+const (
+	// OpenAPIVersion30 writes OpenAPI 3.0 output
+	OpenAPIVersion30 = "3.0.3"
+
+	// OpenAPIVersion31 writes OpenAPI 3.1 output
+	OpenAPIVersion31 = "3.1.0"
+)
+
+// Supported values for Config.FormDataMode.
+const (
+	// FormDataModeURLEncoded sends converted form fields as application/x-www-form-urlencoded
+	FormDataModeURLEncoded = "urlencoded"
+
+	// FormDataModeMultipart sends converted form fields as multipart/form-data
+	FormDataModeMultipart = "multipart"
+)
+
+// Supported values for Config.ResponseView.
+const (
+	// ResponseViewAll keeps every response
+	ResponseViewAll = "all"
+
+	// ResponseViewSuccessOnly keeps 2XX and default responses
+	ResponseViewSuccessOnly = "success-only"
+
+	// ResponseViewErrorsOnly keeps 4XX, 5XX and default responses
+	ResponseViewErrorsOnly = "errors-only"
+)
+
+// StdoutOutput is the Config.Output value that writes the merged spec to
+// standard output.
+const StdoutOutput = "-"
+
+// Supported values for Config.OutputFormat.
+const (
+	// OutputFormatJSON writes JSON (default)
+	OutputFormatJSON = "json"
+
+	// OutputFormatYAML writes YAML
+	OutputFormatYAML = "yaml"
+)
+
+// Supported values for Config.ServersMode.
+const (
+	// ServersModeConfig uses only the servers defined in the config file
+	ServersModeConfig = "config"
+
+	// ServersModeUnion combines config servers with the servers of every input
+	ServersModeUnion = "union"
+)
+
+// Supported values for Config.SecurityMergeStrategy.
+const (
+	// SecurityMergeStrategyReplace uses only the security defined in the config file
+	SecurityMergeStrategyReplace = "replace"
+
+	// SecurityMergeStrategyMerge combines the global security of every input
+	// with the config's, keeping identical requirements once
+	SecurityMergeStrategyMerge = "merge"
+)
+
+// Supported values for PatchConfig.Op.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// Supported values for the fields of ConflictPolicyConfig.
+const (
+	// ConflictPolicyError fails the merge (default for schemas and parameters)
+	ConflictPolicyError = "error"
+
+	// ConflictPolicyFirst keeps the component merged first (default for the rest)
+	ConflictPolicyFirst = "first"
+
+	// ConflictPolicyLast replaces the component with the one merged last
+	ConflictPolicyLast = "last"
+
+	// ConflictPolicyPrefix keeps both, renaming the later one with a prefix
+	// derived from its input's label or file name
+	ConflictPolicyPrefix = "prefix"
+)
+
+// Supported values for Config.OnPathConflict.
+const (
+	// PathConflictFirst keeps the operation merged first and warns
+	PathConflictFirst = "first"
+
+	// PathConflictError fails the merge
+	PathConflictError = "error"
+
+	// PathConflictPrefix keeps both, moving the later one under a path prefix
+	// derived from its input's label or file name
+	PathConflictPrefix = "prefix"
+)
+
+// Supported values for Config.BasePathOverlap.
+const (
+	// BasePathOverlapWarn keeps both prefixes and reports each overlapping server
+	BasePathOverlapWarn = "warn"
+
+	// BasePathOverlapStrip removes the basePath from the end of overlapping server URLs
+	BasePathOverlapStrip = "strip"
+
+	// BasePathOverlapIgnore keeps both prefixes without a warning
+	BasePathOverlapIgnore = "ignore"
+)
+
+// Supported values for Config.ExtensionConflict.
+const (
+	// ExtensionConflictFirst keeps the first input's value
+	ExtensionConflictFirst = "first"
+
+	// ExtensionConflictLast keeps the last input's value
+	ExtensionConflictLast = "last"
+
+	// ExtensionConflictError fails the merge
+	ExtensionConflictError = "error"
+)
+
+// Supported values for Config.TagMergeStrategy.
+const (
+	// TagMergeFirst keeps the first definition of each tag
+	TagMergeFirst = "first"
+
+	// TagMergeLast replaces a tag with each later definition
+	TagMergeLast = "last"
+
+	// TagMergeCombine joins distinct descriptions and fills in missing externalDocs
+	TagMergeCombine = "combine"
+)
+
+// Supported values for Config.ServerVariableConflict.
+const (
+	// ServerVariableConflictMerge unions the enums of same-named variables and
+	// keeps the first non-empty default
+	ServerVariableConflictMerge = "merge"
+
+	// ServerVariableConflictError fails the merge when same-named variables differ
+	ServerVariableConflictError = "error"
+)
+
+// Supported values for Config.SchemaConflict.
+const (
+	// SchemaConflictError fails the merge on conflicting schemas without a dispute prefix
+	SchemaConflictError = "error"
+
+	// SchemaConflictMergeEnums unions the enum values of conflicting scalar enum schemas
+	SchemaConflictMergeEnums = "merge-enums"
+)
+
+// InfoConfig represents the info section override configuration.
+type InfoConfig struct {
+	Title          string         `mapstructure:"title" json:"title,omitempty" yaml:"title,omitempty"`
+	Description    string         `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+	Version        string         `mapstructure:"version" json:"version,omitempty" yaml:"version,omitempty"`
+	TermsOfService string         `mapstructure:"termsOfService" json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
+	Contact        *ContactConfig `mapstructure:"contact" json:"contact,omitempty" yaml:"contact,omitempty"`
+	License        *LicenseConfig `mapstructure:"license" json:"license,omitempty" yaml:"license,omitempty"`
+}
+
+// ContactConfig represents contact information.
+type ContactConfig struct {
+	Name  string `mapstructure:"name" json:"name,omitempty" yaml:"name,omitempty"`
+	URL   string `mapstructure:"url" json:"url,omitempty" yaml:"url,omitempty"`
+	Email string `mapstructure:"email" json:"email,omitempty" yaml:"email,omitempty"`
+}
+
+// LicenseConfig represents license information.
+type LicenseConfig struct {
+	Name string `mapstructure:"name" json:"name,omitempty" yaml:"name,omitempty"`
+	URL  string `mapstructure:"url" json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// ServerConfig represents a server configuration.
+type ServerConfig struct {
+	URL         string                          `mapstructure:"url" json:"url" yaml:"url"`
+	Description string                          `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+	Variables   map[string]ServerVariableConfig `mapstructure:"variables" json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// ServerVariableConfig represents a server variable.
+type ServerVariableConfig struct {
+	Enum        []string `mapstructure:"enum" json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     string   `mapstructure:"default" json:"default" yaml:"default"`
+	Description string   `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// SecuritySchemeConfig represents an OAS3 security scheme definition.
+// Supports: apiKey, http (basic/bearer), oauth2, openIdConnect
+type SecuritySchemeConfig struct {
+	// Type is the security scheme type: apiKey, http, oauth2, openIdConnect
+	Type string `mapstructure:"type" json:"type" yaml:"type"`
+
+	// Description of the security scheme
+	Description string `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Name is the name of the header, query or cookie parameter (for apiKey type)
+	Name string `mapstructure:"name" json:"name,omitempty" yaml:"name,omitempty"`
+
+	// In is the location of the API key: header, query, or cookie (for apiKey type)
+	In string `mapstructure:"in" json:"in,omitempty" yaml:"in,omitempty"`
+
+	// Scheme is the HTTP auth scheme: basic, bearer, etc. (for http type)
+	Scheme string `mapstructure:"scheme" json:"scheme,omitempty" yaml:"scheme,omitempty"`
+
+	// BearerFormat is the format of the bearer token (for http bearer type)
+	BearerFormat string `mapstructure:"bearerFormat" json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+
+	// Flows contains OAuth2 flow configurations (for oauth2 type)
+	Flows *OAuthFlowsConfig `mapstructure:"flows" json:"flows,omitempty" yaml:"flows,omitempty"`
+
+	// OpenIdConnectUrl is the URL for OpenID Connect discovery (for openIdConnect type)
+	OpenIdConnectUrl string `mapstructure:"openIdConnectUrl" json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlowsConfig represents OAuth2 flow configurations.
+type OAuthFlowsConfig struct {
+	Implicit          *OAuthFlowConfig `mapstructure:"implicit" json:"implicit,omitempty" yaml:"implicit,omitempty"`
+	Password          *OAuthFlowConfig `mapstructure:"password" json:"password,omitempty" yaml:"password,omitempty"`
+	ClientCredentials *OAuthFlowConfig `mapstructure:"clientCredentials" json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlowConfig `mapstructure:"authorizationCode" json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+}
+
+// OAuthFlowConfig represents a single OAuth2 flow configuration.
+type OAuthFlowConfig struct {
+	AuthorizationURL string            `mapstructure:"authorizationUrl" json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `mapstructure:"tokenUrl" json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	RefreshURL       string            `mapstructure:"refreshUrl" json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string `mapstructure:"scopes" json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// FetchConfig defines options for fetching remote input files.
+type FetchConfig struct {
+	// UserAgent overrides the default User-Agent header (openapi-merge/<version>)
+	UserAgent string `mapstructure:"userAgent" json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+}
+
+// Defaults for HTTPConfig.
+const (
+	// DefaultHTTPTimeoutSeconds bounds each request for a remote input
+	DefaultHTTPTimeoutSeconds = 30
+
+	// DefaultHTTPRetryBackoffMs is the wait before the first retry; it doubles
+	// with every further attempt
+	DefaultHTTPRetryBackoffMs = 500
+
+	// MaxHTTPRetryBackoff caps the wait before any single retry
+	MaxHTTPRetryBackoff = 30 * time.Second
+)
+
+// HTTPConfig defines the timeout and retries for fetching remote inputs.
+type HTTPConfig struct {
+	// TimeoutSeconds bounds each request (default 30)
+	TimeoutSeconds int `mapstructure:"timeoutSeconds" json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+
+	// Retries is the number of retries after a network error or 5xx response (default 0)
+	Retries int `mapstructure:"retries" json:"retries,omitempty" yaml:"retries,omitempty"`
+
+	// RetryBackoffMs is the wait before the first retry, doubled for each further one (default 500)
+	RetryBackoffMs int `mapstructure:"retryBackoffMs" json:"retryBackoffMs,omitempty" yaml:"retryBackoffMs,omitempty"`
+}
+
+// Timeout returns the configured request timeout or the default.
+func (c *HTTPConfig) Timeout() time.Duration {
+	if c == nil || c.TimeoutSeconds == 0 {
+		return DefaultHTTPTimeoutSeconds * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// Attempts returns the number of requests made before giving up.
+func (c *HTTPConfig) Attempts() int {
+	if c == nil {
+		return 1
+	}
+	return c.Retries + 1
+}
+
+// Backoff returns the wait before the given retry, counting from 1. It never
+// exceeds MaxHTTPRetryBackoff.
+func (c *HTTPConfig) Backoff(retry int) time.Duration {
+	backoff := DefaultHTTPRetryBackoffMs
+	if c != nil && c.RetryBackoffMs != 0 {
+		backoff = c.RetryBackoffMs
+	}
+	if backoff >= int(MaxHTTPRetryBackoff/time.Millisecond) {
+		return MaxHTTPRetryBackoff
+	}
+	wait := time.Duration(backoff) * time.Millisecond
+	for i := 1; i < retry; i++ {
+		if wait >= MaxHTTPRetryBackoff/2 {
+			return MaxHTTPRetryBackoff
+		}
+		wait *= 2
+	}
+	return wait
+}
+
+// ConflictPolicyConfig sets the conflict policy of each component type:
+// error, first, last or prefix. Empty fields use the default for the type.
+type ConflictPolicyConfig struct {
+	Schemas         string `mapstructure:"schemas" json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Responses       string `mapstructure:"responses" json:"responses,omitempty" yaml:"responses,omitempty"`
+	Parameters      string `mapstructure:"parameters" json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	SecuritySchemes string `mapstructure:"securitySchemes" json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+	RequestBodies   string `mapstructure:"requestBodies" json:"requestBodies,omitempty" yaml:"requestBodies,omitempty"`
+	Examples        string `mapstructure:"examples" json:"examples,omitempty" yaml:"examples,omitempty"`
+	Headers         string `mapstructure:"headers" json:"headers,omitempty" yaml:"headers,omitempty"`
+	Links           string `mapstructure:"links" json:"links,omitempty" yaml:"links,omitempty"`
+	Callbacks       string `mapstructure:"callbacks" json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+}
+
+// componentKinds lists the component types in the order they are merged.
+var componentKinds = []string{
+	"schemas", "responses", "parameters", "securitySchemes", "requestBodies",
+	"examples", "headers", "links", "callbacks",
+}
+
+// byKind returns the configured policies keyed by component type.
+func (c *ConflictPolicyConfig) byKind() map[string]string {
+	if c == nil {
+		return nil
+	}
+	return map[string]string{
+		"schemas":         c.Schemas,
+		"responses":       c.Responses,
+		"parameters":      c.Parameters,
+		"securitySchemes": c.SecuritySchemes,
+		"requestBodies":   c.RequestBodies,
+		"examples":        c.Examples,
+		"headers":         c.Headers,
+		"links":           c.Links,
+		"callbacks":       c.Callbacks,
+	}
+}
+
+// For returns the conflict policy of a component type, such as "schemas",
+// falling back to error for schemas and parameters and first otherwise.
+func (c *ConflictPolicyConfig) For(kind string) string {
+	if policy := c.byKind()[kind]; policy != "" {
+		return policy
+	}
+	if kind == "schemas" || kind == "parameters" {
+		return ConflictPolicyError
+	}
+	return ConflictPolicyFirst
+}
+
+// AuthConfig configures credentials for fetching remote inputs.
+type AuthConfig struct {
+	// TokenFile is a file holding the GitHub token, used when neither GITHUB_TOKEN
+	// nor GITHUB_TOKEN_FILE is set
+	TokenFile string `mapstructure:"tokenFile" json:"tokenFile,omitempty" yaml:"tokenFile,omitempty"`
+}
+
+// InputConfig represents a single input file configuration.
+type InputConfig struct {
+	// InputFile is the path to the source file (JSON or YAML)
+	InputFile string `mapstructure:"inputFile" json:"inputFile" yaml:"inputFile"`
+
+	// Label is a short name for the input, usable with the --only flag
+	Label string `mapstructure:"label" json:"label,omitempty" yaml:"label,omitempty"`
+
+	// Headers are extra request headers for a URL input; values expand ${ENV} variables
+	Headers map[string]string `mapstructure:"headers" json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Primary marks the input whose Info is used as the base when infoMode is primary
+	Primary bool `mapstructure:"primary" json:"primary,omitempty" yaml:"primary,omitempty"`
+
+	// OverlayOnly merges this input only into operations already present from
+	// earlier inputs; paths and operations not found there are skipped
+	OverlayOnly bool `mapstructure:"overlayOnly" json:"overlayOnly,omitempty" yaml:"overlayOnly,omitempty"`
+
+	// RemovePaths deletes matching operations merged by earlier inputs before
+	// this input is merged
+	RemovePaths []PathFilter `mapstructure:"removePaths" json:"removePaths,omitempty" yaml:"removePaths,omitempty"`
+
+	// OperationIDPrefix is prepended to the operationId of every operation of this input
+	OperationIDPrefix string `mapstructure:"operationIdPrefix" json:"operationIdPrefix,omitempty" yaml:"operationIdPrefix,omitempty"`
+
+	// Dispute renames all of the input's components with a prefix and/or suffix
+	Dispute *DisputeConfig `mapstructure:"dispute" json:"dispute,omitempty" yaml:"dispute,omitempty"`
+
+	// PathModification defines path transformation rules
+	PathModification *PathModificationConfig `mapstructure:"pathModification" json:"pathModification,omitempty" yaml:"pathModification,omitempty"`
+
+	// OperationSelection defines which operations to include/exclude
+	OperationSelection *OperationSelectionConfig `mapstructure:"operationSelection" json:"operationSelection,omitempty" yaml:"operationSelection,omitempty"`
+
+	// Components selects which components of this input are merged
+	Components *ComponentSelectionConfig `mapstructure:"components" json:"components,omitempty" yaml:"components,omitempty"`
+
+	// IncludeExtraParameters are parameters to inject into every operation
+	IncludeExtraParameters []ParameterConfig `mapstructure:"includeExtraParameters" json:"includeExtraParameters,omitempty" yaml:"includeExtraParameters,omitempty"`
+
+	// ExcludeParameters are parameter filters to remove from operations
+	ExcludeParameters []ParamFilter `mapstructure:"excludeParameters" json:"excludeParameters,omitempty" yaml:"excludeParameters,omitempty"`
+
+	// IncludeResponseHeaders are headers to inject into matching operation responses
+	IncludeResponseHeaders []ResponseHeaderConfig `mapstructure:"includeResponseHeaders" json:"includeResponseHeaders,omitempty" yaml:"includeResponseHeaders,omitempty"`
+
+	// Description defines how to merge the input's description
+	Description *DescriptionConfig `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// DisputeConfig defines conflict resolution configuration.
+type DisputeConfig struct {
+	// Prefix to add to component names on collision
+	Prefix string `mapstructure:"prefix" json:"prefix" yaml:"prefix"`
+
+	// Suffix to append to component names on collision (e.g. User -> UserV2)
+	Suffix string `mapstructure:"suffix" json:"suffix,omitempty" yaml:"suffix,omitempty"`
+}
+
+// Active reports whether the dispute renames anything.
+func (d *DisputeConfig) Active() bool {
+	return d != nil && (d.Prefix != "" || d.Suffix != "")
+}
+
+// Rename returns the disputed name of a component.
+func (d *DisputeConfig) Rename(name string) string {
+	return d.Prefix + name + d.Suffix
+}
+
+// PathModificationConfig defines path transformation rules.
+type PathModificationConfig struct {
+	// Replace rewrites paths with regular expressions, in order, before StripStart and Prepend
+	Replace []PathReplaceConfig `mapstructure:"replace" json:"replace,omitempty" yaml:"replace,omitempty"`
+
+	// StripStart is a string to remove from the beginning of paths
+	StripStart string `mapstructure:"stripStart" json:"stripStart,omitempty" yaml:"stripStart,omitempty"`
+
+	// Prepend is a string to add to the start of paths
+	Prepend string `mapstructure:"prepend" json:"prepend,omitempty" yaml:"prepend,omitempty"`
+}
+
+// PathReplaceConfig is a regular expression substitution applied to paths.
+type PathReplaceConfig struct {
+	// Pattern is a Go regular expression matched against the path
+	Pattern string `mapstructure:"pattern" json:"pattern" yaml:"pattern"`
+
+	// Replacement replaces every match and may use $1-style groups
+	Replacement string `mapstructure:"replacement" json:"replacement" yaml:"replacement"`
+}
+
+// OperationSelectionConfig defines operation filtering rules.
+type OperationSelectionConfig struct {
+	// IncludeTags - only include operations with these tags
+	IncludeTags []string `mapstructure:"includeTags" json:"includeTags,omitempty" yaml:"includeTags,omitempty"`
+
+	// ExcludeTags - exclude operations with these tags
+	ExcludeTags []string `mapstructure:"excludeTags" json:"excludeTags,omitempty" yaml:"excludeTags,omitempty"`
+
+	// IncludePaths - whitelist specific paths/methods
+	IncludePaths []PathFilter `mapstructure:"includePaths" json:"includePaths,omitempty" yaml:"includePaths,omitempty"`
+
+	// ExcludePaths - blacklist specific paths/methods
+	ExcludePaths []PathFilter `mapstructure:"excludePaths" json:"excludePaths,omitempty" yaml:"excludePaths,omitempty"`
+
+	// IncludeOperationIDs - only include operations whose operationId matches a glob
+	IncludeOperationIDs []string `mapstructure:"includeOperationIds" json:"includeOperationIds,omitempty" yaml:"includeOperationIds,omitempty"`
+
+	// ExcludeOperationIDs - exclude operations whose operationId matches a glob
+	ExcludeOperationIDs []string `mapstructure:"excludeOperationIds" json:"excludeOperationIds,omitempty" yaml:"excludeOperationIds,omitempty"`
+
+	// IncludeByExtension - only include operations with a matching x- extension
+	IncludeByExtension []ExtensionFilter `mapstructure:"includeByExtension" json:"includeByExtension,omitempty" yaml:"includeByExtension,omitempty"`
+
+	// ExcludeByExtension - exclude operations with a matching x- extension
+	ExcludeByExtension []ExtensionFilter `mapstructure:"excludeByExtension" json:"excludeByExtension,omitempty" yaml:"excludeByExtension,omitempty"`
+
+	// ExcludeDeprecated - drop operations marked deprecated
+	ExcludeDeprecated bool `mapstructure:"excludeDeprecated" json:"excludeDeprecated,omitempty" yaml:"excludeDeprecated,omitempty"`
+
+	// IncludeOnlyDeprecated - keep only operations marked deprecated, e.g. for a sunset report
+	IncludeOnlyDeprecated bool `mapstructure:"includeOnlyDeprecated" json:"includeOnlyDeprecated,omitempty" yaml:"includeOnlyDeprecated,omitempty"`
+
+	// RequireOperationID - drop operations without an operationId (an error in strict mode)
+	RequireOperationID bool `mapstructure:"requireOperationId" json:"requireOperationId,omitempty" yaml:"requireOperationId,omitempty"`
+
+	// AutoGenerateOperationID - give operations without an operationId one derived from method and path
+	AutoGenerateOperationID bool `mapstructure:"autoGenerateOperationId" json:"autoGenerateOperationId,omitempty" yaml:"autoGenerateOperationId,omitempty"`
+}
+
+// ComponentSelectionConfig filters an input's components by name.
+type ComponentSelectionConfig struct {
+	// Include - only merge components whose names match these globs
+	Include []string `mapstructure:"include" json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Exclude - skip components whose names match these globs
+	Exclude []string `mapstructure:"exclude" json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// KeepReferenced keeps the components of every type that kept operations
+	// and components reference, transitively, as is always done for schemas;
+	// excluding one of them is an error
+	KeepReferenced bool `mapstructure:"keepReferenced" json:"keepReferenced,omitempty" yaml:"keepReferenced,omitempty"`
+}
+
+// ExtensionFilter matches an operation's x- extension.
+type ExtensionFilter struct {
+	// Key is the extension name (e.g., x-visibility)
+	Key string `mapstructure:"key" json:"key" yaml:"key"`
+
+	// Value is the expected value; empty matches any value as long as the key is present
+	Value string `mapstructure:"value" json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// PathFilter represents a path/method filter with glob support.
+type PathFilter struct {
+	// Path supports glob matching (e.g., /api/*)
+	Path string `mapstructure:"path" json:"path" yaml:"path"`
+
+	// Method is the HTTP verb (GET, POST, etc.) or empty for all methods
+	Method string `mapstructure:"method" json:"method,omitempty" yaml:"method,omitempty"`
+}
+
+// DefaultErrorResponseConfig defines the default error response added to
+// operations, e.g. an RFC 7807 Problem.
+type DefaultErrorResponseConfig struct {
+	// SchemaRef is the component schema of the response body, e.g. #/components/schemas/Problem
+	SchemaRef string `mapstructure:"schemaRef" json:"schemaRef" yaml:"schemaRef"`
+
+	// Description of the response (defaults to "Unexpected error")
+	Description string `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+
+	// ContentType of the response body (defaults to application/problem+json)
+	ContentType string `mapstructure:"contentType" json:"contentType,omitempty" yaml:"contentType,omitempty"`
+
+	// Schema defines the referenced schema when no input provides it
+	Schema interface{} `mapstructure:"schema" json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// ResponseConfig represents a response to inject, either inline or, with Ref,
+// as a reference to a component response.
+type ResponseConfig struct {
+	// Ref references a component response, e.g. #/components/responses/TooManyRequests;
+	// the other fields are ignored when it is set
+	Ref string `mapstructure:"ref" json:"ref,omitempty" yaml:"ref,omitempty"`
+
+	// Description of the response
+	Description string `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+
+	// ContentType of the response body (defaults to application/json)
+	ContentType string `mapstructure:"contentType" json:"contentType,omitempty" yaml:"contentType,omitempty"`
+
+	// Schema of the response body, inline or as a $ref; no body when empty
+	Schema interface{} `mapstructure:"schema" json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// responseRefPrefix is the required prefix of ResponseConfig.Ref.
+const responseRefPrefix = "#/components/responses/"
+
+// ComponentName returns the component response Ref points to.
+func (r *ResponseConfig) ComponentName() string {
+	return strings.TrimPrefix(r.Ref, responseRefPrefix)
+}
+
+// validate checks that the response is either a reference to a component
+// response or has a description.
+func (r *ResponseConfig) validate() error {
+	if r.Ref != "" {
+		if !strings.HasPrefix(r.Ref, responseRefPrefix) || r.ComponentName() == "" {
+			return fmt.Errorf("ref %q must point to %s<name>", r.Ref, responseRefPrefix)
+		}
+		return nil
+	}
+	if r.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+	return nil
+}
+
+// statusCodePattern matches the response keys OpenAPI allows besides "default".
+var statusCodePattern = regexp.MustCompile(`^[1-5]([0-9]{2}|XX)$`)
+
+// NormalizeStatusCode upper-cases the range suffix of a response key, so
+// "5xx" becomes "5XX". Other keys are returned unchanged.
+func NormalizeStatusCode(status string) string {
+	if len(status) == 3 && strings.EqualFold(status[1:], "XX") {
+		return status[:1] + "XX"
+	}
+	return status
+}
+
+// defaultErrorSchemaPrefix is the required prefix of DefaultErrorResponseConfig.SchemaRef.
+const defaultErrorSchemaPrefix = "#/components/schemas/"
+
+// SchemaName returns the component name SchemaRef points to.
+func (d *DefaultErrorResponseConfig) SchemaName() string {
+	return strings.TrimPrefix(d.SchemaRef, defaultErrorSchemaPrefix)
+}
+
+// CoverageConfig sets documentation coverage thresholds for merged operations.
+type CoverageConfig struct {
+	// RequireTags reports every operation without tags
+	RequireTags bool `mapstructure:"requireTags" json:"requireTags,omitempty" yaml:"requireTags,omitempty"`
+
+	// MinSummaryPercent is the minimum share of operations with a summary (0-100)
+	MinSummaryPercent float64 `mapstructure:"minSummaryPercent" json:"minSummaryPercent,omitempty" yaml:"minSummaryPercent,omitempty"`
+
+	// MinDescriptionPercent is the minimum share of operations with a description (0-100)
+	MinDescriptionPercent float64 `mapstructure:"minDescriptionPercent" json:"minDescriptionPercent,omitempty" yaml:"minDescriptionPercent,omitempty"`
+}
+
+// RefRewriteConfig rewrites $refs matching From to To.
+type RefRewriteConfig struct {
+	// From is a ref prefix, or a regular expression when Regex is set
+	From string `mapstructure:"from" json:"from" yaml:"from"`
+
+	// To replaces the matched prefix; with Regex it may use $1-style groups
+	To string `mapstructure:"to" json:"to" yaml:"to"`
+
+	// Regex treats From as a regular expression
+	Regex bool `mapstructure:"regex" json:"regex,omitempty" yaml:"regex,omitempty"`
+}
+
+// PatchConfig is a JSON Patch (RFC 6902) operation on the merged spec.
+type PatchConfig struct {
+	// Op is add, remove, replace, move, copy or test
+	Op string `mapstructure:"op" json:"op" yaml:"op"`
+
+	// Path is a JSON Pointer into the merged spec, e.g. /paths/~1users/get/deprecated
+	Path string `mapstructure:"path" json:"path" yaml:"path"`
+
+	// From is the JSON Pointer a move or copy reads from
+	From string `mapstructure:"from" json:"from,omitempty" yaml:"from,omitempty"`
+
+	// Value is written by add and replace, and compared by test
+	Value interface{} `mapstructure:"value" json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// OperationPolicyConfig adds extensions to the merged operations matching a path and method.
+type OperationPolicyConfig struct {
+	// Path supports glob matching (e.g., /api/*) against the final output paths
+	Path string `mapstructure:"path" json:"path" yaml:"path"`
+
+	// Method is the HTTP verb (GET, POST, etc.) or empty for all methods
+	Method string `mapstructure:"method" json:"method,omitempty" yaml:"method,omitempty"`
+
+	// Extensions are merged into the operation's extensions, replacing existing keys
+	Extensions map[string]interface{} `mapstructure:"extensions" json:"extensions" yaml:"extensions"`
+}
+
+// ParamFilter represents a parameter filter.
+type ParamFilter struct {
+	// Name is the parameter name to match
+	Name string `mapstructure:"name" json:"name" yaml:"name"`
+
+	// In is the parameter location (query, header, path, cookie)
+	In string `mapstructure:"in" json:"in,omitempty" yaml:"in,omitempty"`
+}
+
+// ParameterConfig represents a parameter to inject, either inline or, with
+// Ref, as a reference to a component parameter.
+type ParameterConfig struct {
+	// Ref references a component parameter, e.g. #/components/parameters/TenantId;
+	// the other fields are ignored when it is set
+	Ref string `mapstructure:"ref" json:"ref,omitempty" yaml:"ref,omitempty"`
+
+	Name            string      `mapstructure:"name" json:"name,omitempty" yaml:"name,omitempty"`
+	In              string      `mapstructure:"in" json:"in,omitempty" yaml:"in,omitempty"`
+	Description     string      `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+	Required        bool        `mapstructure:"required" json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated      bool        `mapstructure:"deprecated" json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool        `mapstructure:"allowEmptyValue" json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Schema          interface{} `mapstructure:"schema" json:"schema,omitempty" yaml:"schema,omitempty"`
+
+	// Methods limits injection to operations with these HTTP methods (case-insensitive); empty means all
+	Methods []string `mapstructure:"methods" json:"methods,omitempty" yaml:"methods,omitempty"`
+}
+
+// ResponseHeaderConfig represents a response header to inject.
+type ResponseHeaderConfig struct {
+	// Name is the header name
+	Name string `mapstructure:"name" json:"name" yaml:"name"`
+
+	// Description of the header
+	Description string `mapstructure:"description" json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Schema is the header schema (defaults to string)
+	Schema interface{} `mapstructure:"schema" json:"schema,omitempty" yaml:"schema,omitempty"`
+
+	// StatusCodes limits injection to matching response codes (e.g., 200, 2XX).
+	// Empty means all responses.
+	StatusCodes []string `mapstructure:"statusCodes" json:"statusCodes,omitempty" yaml:"statusCodes,omitempty"`
+}
+
+// DescriptionConfig defines description merging logic.
+type DescriptionConfig struct {
+	// Append indicates whether to append the input's description
+	Append bool `mapstructure:"append" json:"append,omitempty" yaml:"append,omitempty"`
+
+	// Title configuration for the description section
+	Title *DescriptionTitleConfig `mapstructure:"title" json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// DescriptionTitleConfig defines the title for description sections.
+type DescriptionTitleConfig struct {
+	// Value is the title text
+	Value string `mapstructure:"value" json:"value" yaml:"value"`
+
+	// HeadingLevel is the markdown heading level (1-6)
+	HeadingLevel int `mapstructure:"headingLevel" json:"headingLevel,omitempty" yaml:"headingLevel,omitempty"`
+}
+
+// Prepare completes the configuration before it is validated: input globs
+// are expanded, so relative paths must already be resolved.
+func (c *Config) Prepare() error {
+	return c.ExpandInputGlobs()
+}
+
+// Validate checks if the configuration is valid.
+func (c *Config) Validate() error {
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("at least one input file is required")
+	}
+
+	if c.Output == "" {
+		return fmt.Errorf("output file path is required")
+	}
+
+	if c.OutputURL != "" && !IsURL(c.OutputURL) {
+		return fmt.Errorf("outputUrl %q must be an http:// or https:// URL", c.OutputURL)
+	}
+
+	if c.KeepExternalRefs && c.BundleExternalRefs {
+		return fmt.Errorf("keepExternalRefs and bundleExternalRefs cannot both be set")
+	}
+
+	switch c.OutputFormat {
+	case "", OutputFormatJSON, OutputFormatYAML:
+	default:
+		return fmt.Errorf("invalid outputFormat %q (expected %s or %s)", c.OutputFormat, OutputFormatJSON, OutputFormatYAML)
+	}
+
+	if c.WritesToStdout() && c.Checksum {
+		return fmt.Errorf("checksum cannot be written when output is standard output")
+	}
+
+	if strings.Trim(c.Indent, " \t") != "" {
+		return fmt.Errorf("indent %q must contain only spaces or tabs", c.Indent)
+	}
+
+	if h := c.HTTP; h != nil && (h.TimeoutSeconds < 0 || h.Retries < 0 || h.RetryBackoffMs < 0) {
+		return fmt.Errorf("http timeoutSeconds, retries and retryBackoffMs must not be negative")
+	}
+
+	if c.MaxDescriptionLength < 0 {
+		return fmt.Errorf("maxDescriptionLength must not be negative")
+	}
+
+	if c.MaxInlineDepth < 0 {
+		return fmt.Errorf("maxInlineDepth must not be negative")
+	}
+
+	switch c.SchemaConflict {
+	case "", SchemaConflictError, SchemaConflictMergeEnums:
+	default:
+		return fmt.Errorf("invalid schemaConflict %q (expected %s or %s)", c.SchemaConflict, SchemaConflictError, SchemaConflictMergeEnums)
+	}
+
+	switch c.OpenAPIVersion {
+	case "", OpenAPIVersion30, OpenAPIVersion31:
+	default:
+		return fmt.Errorf("invalid openapiVersion %q (expected %s or %s)", c.OpenAPIVersion, OpenAPIVersion30, OpenAPIVersion31)
+	}
+
+	switch c.FormDataMode {
+	case "", FormDataModeURLEncoded, FormDataModeMultipart:
+	default:
+		return fmt.Errorf("invalid formDataMode %q (expected %s or %s)", c.FormDataMode, FormDataModeURLEncoded, FormDataModeMultipart)
+	}
+
+	switch c.ResponseView {
+	case "", ResponseViewAll, ResponseViewSuccessOnly, ResponseViewErrorsOnly:
+	default:
+		return fmt.Errorf("invalid responseView %q (expected %s, %s or %s)", c.ResponseView, ResponseViewAll, ResponseViewSuccessOnly, ResponseViewErrorsOnly)
+	}
+
+	switch c.ServersMode {
+	case "", ServersModeConfig, ServersModeUnion:
+	default:
+		return fmt.Errorf("invalid serversMode %q (expected %s or %s)", c.ServersMode, ServersModeConfig, ServersModeUnion)
+	}
+
+	switch c.SecurityMergeStrategy {
+	case "", SecurityMergeStrategyReplace, SecurityMergeStrategyMerge:
+	default:
+		return fmt.Errorf("invalid securityMergeStrategy %q (expected %s or %s)", c.SecurityMergeStrategy, SecurityMergeStrategyReplace, SecurityMergeStrategyMerge)
+	}
+
+	policies := c.ConflictPolicy.byKind()
+	for _, kind := range componentKinds {
+		switch policy := policies[kind]; policy {
+		case "", ConflictPolicyError, ConflictPolicyFirst, ConflictPolicyLast, ConflictPolicyPrefix:
+		default:
+			return fmt.Errorf("invalid conflictPolicy.%s %q (expected %s, %s, %s or %s)", kind, policy,
+				ConflictPolicyError, ConflictPolicyFirst, ConflictPolicyLast, ConflictPolicyPrefix)
+		}
+	}
+
+	switch c.OnPathConflict {
+	case "", PathConflictFirst, PathConflictError, PathConflictPrefix:
+	default:
+		return fmt.Errorf("invalid onPathConflict %q (expected %s, %s or %s)", c.OnPathConflict, PathConflictFirst, PathConflictError, PathConflictPrefix)
+	}
+
+	switch c.BasePathOverlap {
+	case "", BasePathOverlapWarn, BasePathOverlapStrip, BasePathOverlapIgnore:
+	default:
+		return fmt.Errorf("invalid basePathOverlap %q (expected %s, %s or %s)", c.BasePathOverlap, BasePathOverlapWarn, BasePathOverlapStrip, BasePathOverlapIgnore)
+	}
+
+	switch c.ExtensionConflict {
+	case "", ExtensionConflictFirst, ExtensionConflictLast, ExtensionConflictError:
+	default:
+		return fmt.Errorf("invalid extensionConflict %q (expected %s, %s or %s)", c.ExtensionConflict, ExtensionConflictFirst, ExtensionConflictLast, ExtensionConflictError)
+	}
+
+	switch c.TagMergeStrategy {
+	case "", TagMergeFirst, TagMergeLast, TagMergeCombine:
+	default:
+		return fmt.Errorf("invalid tagMergeStrategy %q (expected %s, %s or %s)", c.TagMergeStrategy, TagMergeFirst, TagMergeLast, TagMergeCombine)
+	}
+
+	switch c.ServerVariableConflict {
+	case "", ServerVariableConflictMerge, ServerVariableConflictError:
+	default:
+		return fmt.Errorf("invalid serverVariableConflict %q (expected %s or %s)", c.ServerVariableConflict, ServerVariableConflictMerge, ServerVariableConflictError)
+	}
+
+	statuses := make([]string, 0, len(c.GlobalResponses))
+	for status := range c.GlobalResponses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if status != "default" && !statusCodePattern.MatchString(NormalizeStatusCode(status)) {
+			return fmt.Errorf("globalResponses: invalid status code %q (expected default, 1XX-5XX or a code from 100 to 599)", status)
+		}
+		response := c.GlobalResponses[status]
+		if err := response.validate(); err != nil {
+			return fmt.Errorf("globalResponses[%s]: %w", status, err)
+		}
+	}
+
+	for i, param := range c.GlobalParameters {
+		if err := param.validate(); err != nil {
+			return fmt.Errorf("globalParameters[%d]: %w", i, err)
+		}
+	}
+
+	primaryCount := 0
+	for i, input := range c.Inputs {
+		if input.InputFile == "" {
+			return fmt.Errorf("input[%d]: inputFile is required", i)
+		}
+		if len(input.Headers) > 0 && !IsURL(input.InputFile) {
+			return fmt.Errorf("input[%d]: headers require a URL inputFile", i)
+		}
+		if input.Primary {
+			primaryCount++
+		}
+		for j, param := range input.IncludeExtraParameters {
+			if err := param.validate(); err != nil {
+				return fmt.Errorf("input[%d]: includeExtraParameters[%d]: %w", i, j, err)
+			}
+		}
+		for j, header := range input.IncludeResponseHeaders {
+			if header.Name == "" {
+				return fmt.Errorf("input[%d]: includeResponseHeaders[%d]: name is required", i, j)
+			}
+		}
+		for j, filter := range input.RemovePaths {
+			if filter.Path == "" {
+				return fmt.Errorf("input[%d]: removePaths[%d]: path is required", i, j)
+			}
+		}
+
+		if sel := input.OperationSelection; sel != nil && sel.ExcludeDeprecated && sel.IncludeOnlyDeprecated {
+			return fmt.Errorf("input[%d]: operationSelection: excludeDeprecated and includeOnlyDeprecated cannot both be set", i)
+		}
+
+		if mod := input.PathModification; mod != nil {
+			for j, rule := range mod.Replace {
+				if rule.Pattern == "" {
+					return fmt.Errorf("input[%d]: pathModification.replace[%d]: pattern is required", i, j)
+				}
+				if _, err := regexp.Compile(rule.Pattern); err != nil {
+					return fmt.Errorf("input[%d]: pathModification.replace[%d]: invalid regex: %w", i, j, err)
+				}
+			}
+		}
+	}
+
+	if primaryCount > 1 {
+		return fmt.Errorf("at most one input can be marked primary, found %d", primaryCount)
+	}
+
+	switch c.OperationIDStyle {
+	case "", OperationIDStyleCamel, OperationIDStyleSnake, OperationIDStyleKebab:
+	default:
+		return fmt.Errorf("invalid operationIdStyle %q (expected %s, %s or %s)", c.OperationIDStyle, OperationIDStyleCamel, OperationIDStyleSnake, OperationIDStyleKebab)
+	}
+
+	if c.Coverage != nil {
+		if c.Coverage.MinSummaryPercent < 0 || c.Coverage.MinSummaryPercent > 100 {
+			return fmt.Errorf("coverage.minSummaryPercent must be between 0 and 100")
+		}
+		if c.Coverage.MinDescriptionPercent < 0 || c.Coverage.MinDescriptionPercent > 100 {
+			return fmt.Errorf("coverage.minDescriptionPercent must be between 0 and 100")
+		}
+	}
+
+	if d := c.DefaultErrorResponse; d != nil {
+		if !strings.HasPrefix(d.SchemaRef, defaultErrorSchemaPrefix) || d.SchemaName() == "" {
+			return fmt.Errorf("defaultErrorResponse.schemaRef %q must point to %s<name>", d.SchemaRef, defaultErrorSchemaPrefix)
+		}
+	}
+
+	for i, rule := range c.RefRewrite {
+		if rule.From == "" {
+			return fmt.Errorf("refRewrite[%d]: from is required", i)
+		}
+		if rule.Regex {
+			if _, err := regexp.Compile(rule.From); err != nil {
+				return fmt.Errorf("refRewrite[%d]: invalid regex: %w", i, err)
+			}
+		}
+	}
+
+	for i, policy := range c.OperationPolicies {
+		if policy.Path == "" {
+			return fmt.Errorf("operationPolicies[%d]: path is required", i)
+		}
+		for key := range policy.Extensions {
+			if !strings.HasPrefix(key, "x-") {
+				return fmt.Errorf("operationPolicies[%d]: extension %q must start with x-", i, key)
+			}
+		}
+	}
+
+	for i, patch := range c.Patches {
+		switch patch.Op {
+		case PatchOpAdd, PatchOpRemove, PatchOpReplace, PatchOpMove, PatchOpCopy, PatchOpTest:
+		default:
+			return fmt.Errorf("patches[%d]: invalid op %q (expected add, remove, replace, move, copy or test)", i, patch.Op)
+		}
+		if !strings.HasPrefix(patch.Path, "/") {
+			return fmt.Errorf("patches[%d]: path %q must be a JSON Pointer starting with /", i, patch.Path)
+		}
+		if (patch.Op == PatchOpMove || patch.Op == PatchOpCopy) && !strings.HasPrefix(patch.From, "/") {
+			return fmt.Errorf("patches[%d]: %s requires a from JSON Pointer starting with /", i, patch.Op)
+		}
+	}
+
+	for alias, canonical := range c.SecuritySchemeAliases {
+		if canonical == "" || canonical == alias {
+			return fmt.Errorf("securitySchemeAliases: invalid canonical name %q for %q", canonical, alias)
+		}
+		if _, chained := c.SecuritySchemeAliases[canonical]; chained {
+			return fmt.Errorf("securitySchemeAliases: %q maps to %q, which is itself an alias", alias, canonical)
+		}
+	}
+
+	switch c.GeneratedOperationIDStyle {
+	case "", OperationIDStyleCamel, OperationIDStyleSnake, OperationIDStyleKebab:
+	default:
+		return fmt.Errorf("invalid generatedOperationIdStyle %q (expected %s, %s or %s)", c.GeneratedOperationIDStyle, OperationIDStyleCamel, OperationIDStyleSnake, OperationIDStyleKebab)
+	}
+
+	switch c.PathVariableNormalization {
+	case "", PathVariableNormalizationCanonical:
+	default:
+		return fmt.Errorf("invalid pathVariableNormalization %q (expected %s)", c.PathVariableNormalization, PathVariableNormalizationCanonical)
+	}
+
+	for segment, name := range c.PathVariableNames {
+		if name == "" || strings.ContainsAny(name, "{}/") {
+			return fmt.Errorf("pathVariableNames[%s]: invalid variable name %q", segment, name)
+		}
+	}
+
+	switch c.InfoMode {
+	case "", InfoModeConfig, InfoModeFirst, InfoModeLast:
+	case InfoModePrimary:
+		if primaryCount == 0 {
+			return fmt.Errorf("infoMode %q requires an input marked primary", InfoModePrimary)
+		}
+	default:
+		return fmt.Errorf("invalid infoMode %q (expected %s, %s, %s or %s)", c.InfoMode, InfoModeConfig, InfoModeFirst, InfoModeLast, InfoModePrimary)
+	}
+
+	return nil
+}
+
+// TrailingNewline reports whether the output should end with a newline.
+func (c *Config) TrailingNewline() bool {
+	return c.OutputNewline == nil || *c.OutputNewline
+}
+
+// OutputOpenAPIVersion returns the OpenAPI version of the output.
+func (c *Config) OutputOpenAPIVersion() string {
+	if c.OpenAPIVersion == "" {
+		return OpenAPIVersion30
+	}
+	return c.OpenAPIVersion
+}
+
+// OutputIndent returns the indentation for JSON output.
+func (c *Config) OutputIndent() string {
+	if c.Indent == "" {
+		return "  "
+	}
+	return c.Indent
+}
+
+// YAMLIndent returns the number of spaces to indent YAML output, or 0 to keep
+// the YAML encoder default. Tabs are not valid YAML indentation.
+func (c *Config) YAMLIndent() int {
+	if c.Indent == "" || strings.Trim(c.Indent, " ") != "" {
+		return 0
+	}
+	return len(c.Indent)
+}
+
+// WritesToStdout reports whether the merged spec is written to standard output.
+func (c *Config) WritesToStdout() bool {
+	return c.Output == StdoutOutput
+}
+
+// OutputIsYAML reports whether the output is written as YAML: by the output
+// file extension if it has a known one, otherwise by OutputFormat.
+func (c *Config) OutputIsYAML() bool {
+	switch strings.ToLower(filepath.Ext(c.Output)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	return c.OutputFormat == OutputFormatYAML
+}
+
+// ShouldCreateOutputDir reports whether missing output directories are created.
+func (c *Config) ShouldCreateOutputDir() bool {
+	return c.CreateOutputDir == nil || *c.CreateOutputDir
+}
+
+// IsURL checks if a path is an HTTP/HTTPS URL.
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// ToOpenAPI3Info converts InfoConfig to openapi3.Info.
+func (c *InfoConfig) ToOpenAPI3Info() *openapi3.Info {
+	if c == nil {
+		return nil
+	}
+
+	info := &openapi3.Info{
+		Title:          c.Title,
+		Description:    c.Description,
+		Version:        c.Version,
+		TermsOfService: c.TermsOfService,
+	}
+
+	if c.Contact != nil {
+		info.Contact = &openapi3.Contact{
+			Name:  c.Contact.Name,
+			URL:   c.Contact.URL,
+			Email: c.Contact.Email,
+		}
+	}
+
+	if c.License != nil {
+		info.License = &openapi3.License{
+			Name: c.License.Name,
+			URL:  c.License.URL,
+		}
+	}
+
+	return info
+}
+
+// ToOpenAPI3Servers converts ServerConfig slice to openapi3.Servers.
+func ToOpenAPI3Servers(servers []ServerConfig) openapi3.Servers {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	result := make(openapi3.Servers, len(servers))
+	for i, s := range servers {
+		server := &openapi3.Server{
+			URL:         s.URL,
+			Description: s.Description,
+		}
+
+		if len(s.Variables) > 0 {
+			server.Variables = make(map[string]*openapi3.ServerVariable)
+			for name, v := range s.Variables {
+				server.Variables[name] = &openapi3.ServerVariable{
+					Enum:        v.Enum,
+					Default:     v.Default,
+					Description: v.Description,
+				}
+			}
+		}
+
+		result[i] = server
+	}
+
+	return result
+}
+
+// ToOpenAPI3Security converts security config to openapi3.SecurityRequirements.
+func ToOpenAPI3Security(security []map[string][]string) openapi3.SecurityRequirements {
+	if len(security) == 0 {
+		return nil
+	}
+
+	result := make(openapi3.SecurityRequirements, len(security))
+	for i, s := range security {
+		result[i] = openapi3.SecurityRequirement(s)
+	}
+
+	return result
+}
+
+// ToOpenAPI3SecuritySchemes converts SecuritySchemeConfig map to openapi3.SecuritySchemes.
+func ToOpenAPI3SecuritySchemes(schemes map[string]SecuritySchemeConfig) openapi3.SecuritySchemes {
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	result := make(openapi3.SecuritySchemes)
+	for name, cfg := range schemes {
+		scheme := &openapi3.SecurityScheme{
+			Type:             cfg.Type,
+			Description:      cfg.Description,
+			Name:             cfg.Name,
+			In:               cfg.In,
+			Scheme:           cfg.Scheme,
+			BearerFormat:     cfg.BearerFormat,
+			OpenIdConnectUrl: cfg.OpenIdConnectUrl,
+		}
+
+		// Convert OAuth2 flows if present
+		if cfg.Flows != nil {
+			scheme.Flows = &openapi3.OAuthFlows{}
+			if cfg.Flows.Implicit != nil {
+				scheme.Flows.Implicit = convertOAuthFlow(cfg.Flows.Implicit)
+			}
+			if cfg.Flows.Password != nil {
+				scheme.Flows.Password = convertOAuthFlow(cfg.Flows.Password)
+			}
+			if cfg.Flows.ClientCredentials != nil {
+				scheme.Flows.ClientCredentials = convertOAuthFlow(cfg.Flows.ClientCredentials)
+			}
+			if cfg.Flows.AuthorizationCode != nil {
+				scheme.Flows.AuthorizationCode = convertOAuthFlow(cfg.Flows.AuthorizationCode)
+			}
+		}
+
+		result[name] = &openapi3.SecuritySchemeRef{
+			Value: scheme,
+		}
+	}
+
+	return result
+}
+
+func convertOAuthFlow(cfg *OAuthFlowConfig) *openapi3.OAuthFlow {
+	if cfg == nil {
+		return nil
+	}
+	return &openapi3.OAuthFlow{
+		AuthorizationURL: cfg.AuthorizationURL,
+		TokenURL:         cfg.TokenURL,
+		RefreshURL:       cfg.RefreshURL,
+		Scopes:           cfg.Scopes,
+	}
+}
+
+// parameterRefPrefix is the required prefix of ParameterConfig.Ref.
+const parameterRefPrefix = "#/components/parameters/"
+
+// ComponentName returns the component parameter Ref points to.
+func (p *ParameterConfig) ComponentName() string {
+	return strings.TrimPrefix(p.Ref, parameterRefPrefix)
+}
+
+// AppliesTo reports whether the parameter is injected into operations with
+// the given HTTP method.
+func (p *ParameterConfig) AppliesTo(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, m := range p.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that Ref, when set, points to a component parameter.
+func (p *ParameterConfig) validate() error {
+	if p.Ref != "" && (!strings.HasPrefix(p.Ref, parameterRefPrefix) || p.ComponentName() == "") {
+		return fmt.Errorf("ref %q must point to %s<name>", p.Ref, parameterRefPrefix)
+	}
+	return nil
+}
+
+// ToOpenAPI3Parameter converts ParameterConfig to openapi3.Parameter.
+func (p *ParameterConfig) ToOpenAPI3Parameter() *openapi3.Parameter {
+	param := &openapi3.Parameter{
+		Name:            p.Name,
+		In:              p.In,
+		Description:     p.Description,
+		Required:        p.Required,
+		Deprecated:      p.Deprecated,
+		AllowEmptyValue: p.AllowEmptyValue,
+	}
+
+	// Handle schema conversion
+	if p.Schema != nil {
+		param.Schema = convertToSchemaRef(p.Schema)
+	} else {
+		// Default to string schema
+		param.Schema = &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type: &openapi3.Types{"string"},
+			},
+		}
+	}
+
+	return param
+}
+
+// ToOpenAPI3Header converts ResponseHeaderConfig to openapi3.Header.
+func (h *ResponseHeaderConfig) ToOpenAPI3Header() *openapi3.Header {
+	header := &openapi3.Header{
+		Parameter: openapi3.Parameter{
+			Description: h.Description,
+		},
+	}
+
+	if h.Schema != nil {
+		header.Schema = convertToSchemaRef(h.Schema)
+	} else {
+		header.Schema = &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type: &openapi3.Types{"string"},
+			},
+		}
+	}
+
+	return header
+}
+
+// convertToSchemaRef converts a schema written in the config file, keeping its
+// type, format, description and common validation keywords. Array items are
+// converted the same way; anything that is not a mapping becomes a string.
+func convertToSchemaRef(schema interface{}) *openapi3.SchemaRef {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		schemaVal := &openapi3.Schema{}
+		if typeVal, ok := schemaField(s, "type").(string); ok {
+			schemaVal.Type = &openapi3.Types{typeVal}
+		}
+		if format, ok := schemaField(s, "format").(string); ok {
+			schemaVal.Format = format
+		}
+		if desc, ok := schemaField(s, "description").(string); ok {
+			schemaVal.Description = desc
+		}
+		if enum, ok := schemaField(s, "enum").([]interface{}); ok {
+			schemaVal.Enum = enum
+		}
+		if def := schemaField(s, "default"); def != nil {
+			schemaVal.Default = def
+		}
+		if minimum, ok := toFloat64(schemaField(s, "minimum")); ok {
+			schemaVal.Min = &minimum
+		}
+		if maximum, ok := toFloat64(schemaField(s, "maximum")); ok {
+			schemaVal.Max = &maximum
+		}
+		if pattern, ok := schemaField(s, "pattern").(string); ok {
+			schemaVal.Pattern = pattern
+		}
+		if minLength, ok := toFloat64(schemaField(s, "minLength")); ok && minLength >= 0 {
+			schemaVal.MinLength = uint64(minLength)
+		}
+		if maxLength, ok := toFloat64(schemaField(s, "maxLength")); ok && maxLength >= 0 {
+			n := uint64(maxLength)
+			schemaVal.MaxLength = &n
+		}
+		if items := schemaField(s, "items"); items != nil {
+			schemaVal.Items = convertToSchemaRef(items)
+		}
+		return &openapi3.SchemaRef{Value: schemaVal}
+	default:
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type: &openapi3.Types{"string"},
+			},
+		}
+	}
+}
+
+// schemaField returns the value of key in a config schema. Keys are matched
+// case-insensitively as a fallback, so "maxlength" is read as "maxLength".
+func schemaField(s map[string]interface{}, key string) interface{} {
+	if v, ok := s[key]; ok {
+		return v
+	}
+	for k, v := range s {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// toFloat64 converts a number decoded from JSON or YAML to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// DecodeHook returns a mapstructure decode hook for custom types.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		interfaceToMapHookFunc(),
+	)
+}
+
+func interfaceToMapHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		return data, nil
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseSettings parses a YAML or JSON config file into settings for
+// DeepMerge and Decode. It keeps the case of every key, which free-form
+// values such as inline schemas, extensions and status code ranges depend on.
+func ParseSettings(data []byte) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	return normalizeSettings(settings).(map[string]interface{}), nil
+}
+
+// normalizeSettings turns mappings with non-string keys, such as status
+// codes written as 200, into maps with string keys.
+func normalizeSettings(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = normalizeSettings(child)
+		}
+		return v
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			out[fmt.Sprint(k)] = normalizeSettings(child)
+		}
+		return out
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalizeSettings(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Decode decodes settings into a Config. Field names are matched without
+// regard to case, and scalars are converted weakly (e.g. "true" for a bool).
+func Decode(settings map[string]interface{}) (*Config, error) {
+	var cfg Config
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       DecodeHook(),
+		WeaklyTypedInput: true,
+		Result:           &cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(settings); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSettingsAndDecode(t *testing.T) {
+	settings, err := ParseSettings([]byte(`
+inputs:
+  - inputFile: users.json
+output: merged.json
+strict: "true"
+globalResponses:
+  429:
+    description: Too Many Requests
+  5XX:
+    description: Server Error
+operationPolicies:
+  - path: /users
+    extensions:
+      x-rateLimit: 100
+`))
+	require.NoError(t, err)
+
+	cfg, err := Decode(settings)
+	require.NoError(t, err)
+	assert.Equal(t, "users.json", cfg.Inputs[0].InputFile)
+	assert.True(t, cfg.Strict)
+	assert.Contains(t, cfg.GlobalResponses, "429")
+	assert.Contains(t, cfg.GlobalResponses, "5XX")
+	assert.Contains(t, cfg.OperationPolicies[0].Extensions, "x-rateLimit")
+
+	_, err = ParseSettings([]byte("output: ["))
+	assert.Error(t, err)
+}
+
+func TestParameterSchemaLowercasedKeys(t *testing.T) {
+	param := (&ParameterConfig{
+		Name: "q",
+		In:   "query",
+		Schema: map[string]interface{}{
+			"type":      "string",
+			"minlength": 2,
+			"maxlength": 10,
+		},
+	}).ToOpenAPI3Parameter()
+
+	assert.Equal(t, uint64(2), param.Schema.Value.MinLength)
+	require.NotNil(t, param.Schema.Value.MaxLength)
+	assert.Equal(t, uint64(10), *param.Schema.Value.MaxLength)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// isGlob reports whether an input file is a glob pattern rather than a single
+// file. URLs are never globs.
+func isGlob(path string) bool {
+	return !IsURL(path) && strings.ContainsAny(path, "*?[{")
+}
+
+// ExpandInputGlobs replaces every input whose inputFile is a glob with one
+// input per matching file, in sorted order. The copies keep all of the
+// input's other settings. A glob that matches nothing is an error.
+func (c *Config) ExpandInputGlobs() error {
+	inputs := make([]InputConfig, 0, len(c.Inputs))
+	for i, input := range c.Inputs {
+		if !isGlob(input.InputFile) {
+			inputs = append(inputs, input)
+			continue
+		}
+
+		files, err := globFiles(input.InputFile)
+		if err != nil {
+			return fmt.Errorf("input[%d]: %w", i, err)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("input[%d]: inputFile %q matches no files", i, input.InputFile)
+		}
+		for _, file := range files {
+			expanded := input
+			expanded.InputFile = file
+			inputs = append(inputs, expanded)
+		}
+	}
+	c.Inputs = inputs
+	return nil
+}
+
+// globFiles returns the sorted, distinct files matching pattern, which may
+// contain {a,b} alternatives on top of filepath.Match syntax.
+func globFiles(pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	for _, p := range expandBraces(pattern) {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputFile pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// expandBraces expands the first {a,b,...} group of pattern, recursively, so
+// "specs/{users,orders}/*.yaml" becomes "specs/users/*.yaml" and
+// "specs/orders/*.yaml". Unbalanced braces are left as they are.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	depth := 0
+	var alternatives []string
+	last := start + 1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				alternatives = append(alternatives, pattern[last:i])
+				var out []string
+				for _, alt := range alternatives {
+					out = append(out, expandBraces(pattern[:start]+alt+pattern[i+1:])...)
+				}
+				return out
+			}
+		}
+	}
+	return []string{pattern}
+}
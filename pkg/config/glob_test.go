@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandInputGlobs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	for _, name := range []string{"users.yaml", "orders.yaml", "billing.yaml", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name), []byte("openapi: 3.0.0"), 0644))
+	}
+
+	cfg := &Config{
+		Inputs: []InputConfig{
+			{InputFile: "https://example.com/first.json"},
+			{InputFile: filepath.Join(tempDir, "*.yaml"), OperationIDPrefix: "svc_"},
+			{InputFile: "https://example.com/specs/*.json"},
+		},
+	}
+	require.NoError(t, cfg.ExpandInputGlobs())
+
+	var files []string
+	for _, input := range cfg.Inputs {
+		files = append(files, input.InputFile)
+	}
+	assert.Equal(t, []string{
+		"https://example.com/first.json",
+		filepath.Join(tempDir, "billing.yaml"),
+		filepath.Join(tempDir, "orders.yaml"),
+		filepath.Join(tempDir, "users.yaml"),
+		"https://example.com/specs/*.json",
+	}, files)
+	for _, input := range cfg.Inputs[1:4] {
+		assert.Equal(t, "svc_", input.OperationIDPrefix)
+	}
+
+	t.Run("braces", func(t *testing.T) {
+		cfg := &Config{Inputs: []InputConfig{{InputFile: filepath.Join(tempDir, "{users,orders,users}.yaml")}}}
+		require.NoError(t, cfg.ExpandInputGlobs())
+		require.Len(t, cfg.Inputs, 2)
+		assert.Equal(t, filepath.Join(tempDir, "orders.yaml"), cfg.Inputs[0].InputFile)
+		assert.Equal(t, filepath.Join(tempDir, "users.yaml"), cfg.Inputs[1].InputFile)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		pattern := filepath.Join(tempDir, "*.json")
+		cfg := &Config{Inputs: []InputConfig{{InputFile: pattern}}}
+		assert.EqualError(t, cfg.ExpandInputGlobs(), `input[0]: inputFile "`+pattern+`" matches no files`)
+	})
+}
+
+func TestExpandBraces(t *testing.T) {
+	assert.Equal(t, []string{"a.yaml"}, expandBraces("a.yaml"))
+	assert.Equal(t, []string{"a/x.yaml", "a/y.json", "b/x.yaml", "b/y.json"}, expandBraces("{a,b}/{x.yaml,y.json}"))
+	assert.Equal(t, []string{"v1.yaml", "v2a.yaml", "v2b.yaml"}, expandBraces("v{1,2{a,b}}.yaml"))
+	assert.Equal(t, []string{"broken{a,b.yaml"}, expandBraces("broken{a,b.yaml"))
+}
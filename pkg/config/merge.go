@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+)
+
+// appendListKeys are the config keys whose lists are appended (rather than
+// replaced) when merging config files.
+var appendListKeys = map[string]bool{
+	"inputs": true,
+}
+
+// DeepMerge merges override into base and returns the result.
+//
+// Merge semantics:
+//   - maps are merged recursively
+//   - the top-level "inputs" list is appended to the base list
+//   - any other value (scalars and lists) in override replaces the base value
+func DeepMerge(base, override map[string]interface{}) map[string]interface{} {
+	return deepMerge(base, override, true)
+}
+
+func deepMerge(base, override map[string]interface{}, topLevel bool) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		result[k] = v
+	}
+
+	for k, v := range override {
+		existing, ok := result[k]
+		if !ok {
+			result[k] = v
+			continue
+		}
+
+		if topLevel && appendListKeys[strings.ToLower(k)] {
+			baseList, baseOK := existing.([]interface{})
+			overrideList, overrideOK := v.([]interface{})
+			if baseOK && overrideOK {
+				merged := make([]interface{}, 0, len(baseList)+len(overrideList))
+				merged = append(merged, baseList...)
+				result[k] = append(merged, overrideList...)
+				continue
+			}
+		}
+
+		baseMap, baseOK := existing.(map[string]interface{})
+		overrideMap, overrideOK := v.(map[string]interface{})
+		if baseOK && overrideOK {
+			result[k] = deepMerge(baseMap, overrideMap, false)
+			continue
+		}
+
+		result[k] = v
+	}
+
+	return result
+}
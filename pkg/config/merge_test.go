@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepMerge(t *testing.T) {
+	base := map[string]interface{}{
+		"output":   "merged.json",
+		"basepath": "/api",
+		"info": map[string]interface{}{
+			"title":   "Base API",
+			"version": "1.0.0",
+		},
+		"tagorder": []interface{}{"Users", "Orders"},
+		"inputs": []interface{}{
+			map[string]interface{}{"inputfile": "users.json"},
+		},
+	}
+
+	override := map[string]interface{}{
+		"output": "dist/merged.yaml",
+		"info": map[string]interface{}{
+			"version": "2.0.0",
+		},
+		"tagorder": []interface{}{"Orders"},
+		"inputs": []interface{}{
+			map[string]interface{}{"inputfile": "orders.json"},
+		},
+	}
+
+	got := DeepMerge(base, override)
+
+	assert.Equal(t, map[string]interface{}{
+		"output":   "dist/merged.yaml",
+		"basepath": "/api",
+		"info": map[string]interface{}{
+			"title":   "Base API",
+			"version": "2.0.0",
+		},
+		"tagorder": []interface{}{"Orders"},
+		"inputs": []interface{}{
+			map[string]interface{}{"inputfile": "users.json"},
+			map[string]interface{}{"inputfile": "orders.json"},
+		},
+	}, got)
+
+	// Inputs are not mutated
+	assert.Equal(t, "merged.json", base["output"])
+	assert.Len(t, base["inputs"], 1)
+}
+
+func TestDeepMerge_Precedence(t *testing.T) {
+	first := map[string]interface{}{"basepath": "/a"}
+	second := map[string]interface{}{"basepath": "/b"}
+	third := map[string]interface{}{"basepath": "/c"}
+
+	got := DeepMerge(DeepMerge(first, second), third)
+	assert.Equal(t, "/c", got["basepath"])
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SelectInputs keeps only the inputs matching one of the selectors, preserving
+// their configured order. A selector is a 1-based index, an input label, or a
+// file name (full path, base name, or trailing path segments).
+func (c *Config) SelectInputs(selectors []string) error {
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	selected := make([]bool, len(c.Inputs))
+	for _, sel := range selectors {
+		matched := false
+		for i, input := range c.Inputs {
+			if input.matchesSelector(i, sel) {
+				selected[i] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("no input matches %q", sel)
+		}
+	}
+
+	inputs := make([]InputConfig, 0, len(c.Inputs))
+	for i, input := range c.Inputs {
+		if selected[i] {
+			inputs = append(inputs, input)
+		}
+	}
+	c.Inputs = inputs
+
+	return nil
+}
+
+// matchesSelector reports whether the input at index i matches sel.
+func (ic *InputConfig) matchesSelector(i int, sel string) bool {
+	if n, err := strconv.Atoi(sel); err == nil {
+		return n == i+1
+	}
+	if ic.Label != "" && ic.Label == sel {
+		return true
+	}
+
+	file := filepath.ToSlash(ic.InputFile)
+	sel = filepath.ToSlash(sel)
+	return file == sel || strings.HasSuffix(file, "/"+strings.TrimPrefix(sel, "./"))
+}
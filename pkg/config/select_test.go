@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectInputs(t *testing.T) {
+	newConfig := func() *Config {
+		return &Config{
+			Inputs: []InputConfig{
+				{InputFile: "/specs/users/api.yaml", Label: "users"},
+				{InputFile: "/specs/orders/api.yaml"},
+				{InputFile: "https://example.com/billing.json"},
+			},
+		}
+	}
+
+	files := func(c *Config) []string {
+		var out []string
+		for _, input := range c.Inputs {
+			out = append(out, input.InputFile)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		selectors []string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "none keeps all", want: []string{"/specs/users/api.yaml", "/specs/orders/api.yaml", "https://example.com/billing.json"}},
+		{name: "by label", selectors: []string{"users"}, want: []string{"/specs/users/api.yaml"}},
+		{name: "by index", selectors: []string{"2"}, want: []string{"/specs/orders/api.yaml"}},
+		{name: "by base name", selectors: []string{"billing.json"}, want: []string{"https://example.com/billing.json"}},
+		{name: "by trailing path", selectors: []string{"orders/api.yaml"}, want: []string{"/specs/orders/api.yaml"}},
+		{name: "keeps config order", selectors: []string{"3", "users"}, want: []string{"/specs/users/api.yaml", "https://example.com/billing.json"}},
+		{name: "ambiguous name selects all matches", selectors: []string{"api.yaml"}, want: []string{"/specs/users/api.yaml", "/specs/orders/api.yaml"}},
+		{name: "no match", selectors: []string{"payments"}, wantErr: true},
+		{name: "index out of range", selectors: []string{"4"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig()
+			err := c.SelectInputs(tt.selectors)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, files(c))
+		})
+	}
+}
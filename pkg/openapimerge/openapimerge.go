@@ -0,0 +1,94 @@
+// Package openapimerge merges OpenAPI specifications from Go code. It runs
+// the same pipeline as the openapi-merge CLI, configured with a config.Config
+// built in code instead of read from a file.
+//
+//	cfg := &config.Config{
+//		Inputs: []config.InputConfig{
+//			{InputFile: "users.yaml"},
+//			{InputFile: "orders.yaml", Dispute: &config.DisputeConfig{Prefix: "Orders"}},
+//		},
+//	}
+//	doc, err := openapimerge.Merge(cfg)
+//
+// Relative input paths are resolved against the working directory, and input
+// globs are expanded by Config.Prepare. Besides merging, the package loads,
+// compares and measures single specifications for the CLI's other commands.
+package openapimerge
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/internal/merger"
+	"github.com/rperez95/openapi-merge/pkg/config"
+)
+
+// Config is the merge configuration, the same as in a config file.
+type Config = config.Config
+
+// Merger merges the inputs of a Config. Besides MergeToDocument and
+// MergeToBytes, Merge writes the output like the CLI does, and Warnings and
+// Summary describe the last merge.
+type Merger = merger.Merger
+
+// Summary describes what a merge did without the merged document itself.
+type Summary = merger.MergeSummary
+
+// The parts of a Summary.
+type (
+	InputSummary     = merger.InputSummary
+	ComponentRename  = merger.ComponentRename
+	SkippedOperation = merger.SkippedOperation
+	PathRewrite      = merger.PathRewrite
+)
+
+// Warning is a non-fatal problem found during a merge.
+type Warning = merger.Warning
+
+// InputError is returned when processing a specific input file fails.
+type InputError = merger.InputError
+
+// Supported formats for MergeToBytes.
+const (
+	FormatJSON = config.OutputFormatJSON
+	FormatYAML = config.OutputFormatYAML
+)
+
+// Options controls a Merger beyond its Config.
+type Options struct {
+	// Verbose prints progress to standard output
+	Verbose bool
+
+	// Command is the command line recorded in the YAML output header. The
+	// header has no command line when it is empty.
+	Command string
+}
+
+// SetVersion sets the tool version used in the default User-Agent for URL
+// fetches and in output header comments. It defaults to "dev".
+func SetVersion(version string) {
+	merger.Version = version
+}
+
+// New creates a Merger for cfg. The config is used as is; call its Prepare
+// and Validate methods first to expand input globs and reject invalid
+// settings.
+func New(cfg *Config, opts Options) *Merger {
+	m := merger.New(cfg, opts.Verbose)
+	m.SetCommand(opts.Command)
+	return m
+}
+
+// Merge prepares and validates a copy of cfg and returns the merged document.
+// Nothing is written, so cfg.Output may be left empty.
+func Merge(cfg *Config) (*openapi3.T, error) {
+	checked := *cfg
+	if checked.Output == "" {
+		checked.Output = config.StdoutOutput
+	}
+	if err := checked.Prepare(); err != nil {
+		return nil, err
+	}
+	if err := checked.Validate(); err != nil {
+		return nil, err
+	}
+	return New(&checked, Options{}).MergeToDocument()
+}
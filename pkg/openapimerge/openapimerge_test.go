@@ -0,0 +1,88 @@
+package openapimerge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rperez95/openapi-merge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpecs(t *testing.T) []config.InputConfig {
+	tempDir, err := os.MkdirTemp("", "openapi-merge-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	specs := []string{
+		`{
+			"openapi": "3.0.0",
+			"info": {"title": "Users", "version": "1.0.0"},
+			"paths": {"/users": {"get": {"responses": {"200": {"description": "OK"}}}}}
+		}`,
+		`{
+			"openapi": "3.0.0",
+			"info": {"title": "Orders", "version": "1.0.0"},
+			"paths": {"/orders": {"get": {"responses": {"200": {"description": "OK"}}}}}
+		}`,
+	}
+
+	var inputs []config.InputConfig
+	for i, spec := range specs {
+		specPath := filepath.Join(tempDir, fmt.Sprintf("spec%d.json", i))
+		require.NoError(t, os.WriteFile(specPath, []byte(spec), 0644))
+		inputs = append(inputs, config.InputConfig{InputFile: specPath})
+	}
+	return inputs
+}
+
+func TestMerge(t *testing.T) {
+	inputs := writeSpecs(t)
+
+	doc, err := Merge(&Config{Inputs: inputs, Info: &config.InfoConfig{Title: "Platform"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", doc.Info.Title)
+	assert.NotNil(t, doc.Paths.Value("/users"))
+	assert.NotNil(t, doc.Paths.Value("/orders"))
+
+	_, err = Merge(&Config{})
+	assert.EqualError(t, err, "at least one input file is required")
+}
+
+func TestMerge_ExpandsInputGlobs(t *testing.T) {
+	inputs := writeSpecs(t)
+	pattern := filepath.Join(filepath.Dir(inputs[0].InputFile), "spec*.json")
+
+	cfg := &Config{Inputs: []config.InputConfig{{InputFile: pattern}}}
+	doc, err := Merge(cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, doc.Paths.Value("/users"))
+	assert.NotNil(t, doc.Paths.Value("/orders"))
+	assert.Equal(t, pattern, cfg.Inputs[0].InputFile, "the caller's config is left alone")
+}
+
+func TestMerger_MergeToBytes(t *testing.T) {
+	inputs := writeSpecs(t)
+	outputPath := filepath.Join(filepath.Dir(inputs[0].InputFile), "merged.json")
+	cfg := &Config{Inputs: inputs, Output: outputPath}
+	require.NoError(t, cfg.Validate())
+
+	m := New(cfg, Options{})
+
+	data, err := m.MergeToBytes(FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "openapi: 3.0.3")
+	assert.Contains(t, string(data), "/orders:")
+
+	data, err = m.MergeToBytes(FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"/users": {`)
+
+	_, err = m.MergeToBytes("xml")
+	assert.EqualError(t, err, `invalid format "xml" (expected json or yaml)`)
+
+	assert.NoFileExists(t, outputPath)
+	assert.Len(t, m.Summary().Inputs, 2)
+}
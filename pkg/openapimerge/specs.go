@@ -0,0 +1,59 @@
+package openapimerge
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rperez95/openapi-merge/internal/merger"
+)
+
+// Kinds of SpecChange.
+const (
+	ChangeAdded   = merger.ChangeAdded
+	ChangeRemoved = merger.ChangeRemoved
+	ChangeChanged = merger.ChangeChanged
+)
+
+// SpecChange is a difference between two specifications.
+type SpecChange = merger.SpecChange
+
+// DiffSpecs compares the paths, operations and component schemas of two
+// specifications. Removed paths, operations and responses, and parameters
+// that are new or newly required, are breaking changes.
+func DiffSpecs(base, revision *openapi3.T) []SpecChange {
+	return merger.DiffSpecs(base, revision)
+}
+
+// BreakingChanges returns the breaking changes among changes.
+func BreakingChanges(changes []SpecChange) []SpecChange {
+	return merger.BreakingChanges(changes)
+}
+
+// SpecStats holds metrics about a single OpenAPI specification.
+type SpecStats = merger.SpecStats
+
+// SchemaSizeStat reports the number of properties of a component schema.
+type SchemaSizeStat = merger.SchemaSizeStat
+
+// ComputeStats returns metrics about spec.
+func ComputeStats(spec *openapi3.T) SpecStats {
+	return merger.ComputeStats(spec)
+}
+
+// Kinds of ValidationProblem.
+const (
+	ProblemRef         = merger.ProblemRef
+	ProblemOperationID = merger.ProblemOperationID
+	ProblemSpec        = merger.ProblemSpec
+)
+
+// ValidationProblem is an issue found by Merger.Validate.
+type ValidationProblem = merger.ValidationProblem
+
+// Conflict describes a component that two inputs define differently.
+type Conflict = merger.Conflict
+
+// PrefixSuggestion proposes a dispute prefix for an input, as returned by
+// Merger.SuggestPrefixes.
+type PrefixSuggestion = merger.PrefixSuggestion
+
+// NormalizePasses lists every pass of Merger.Normalize in run order.
+var NormalizePasses = merger.NormalizePasses